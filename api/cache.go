@@ -0,0 +1,38 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package api
+
+import (
+	"github.com/capitalone/checks-out/remote"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlushOrgCache evicts the cached teams of an organization
+// and the cached permissions of its repositories.
+func FlushOrgCache(c *gin.Context) {
+	evicted := remote.EvictOrg(c, c.Param("owner"))
+	c.IndentedJSON(200, gin.H{"evicted": evicted})
+}
+
+// FlushRepoCache evicts the cached permissions of a repository.
+func FlushRepoCache(c *gin.Context) {
+	evicted := remote.EvictRepo(c, c.Param("owner"), c.Param("repo"))
+	c.IndentedJSON(200, gin.H{"evicted": evicted})
+}
@@ -0,0 +1,102 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	hookDeliveryLimit    = 100
+	hookDeliveryMaxLimit = 1000
+)
+
+// GetHookDeliveries searches the archived webhooks. The optional
+// query parameters repo (owner/name), pr, event and limit filter
+// the latest deliveries. The headers and payloads are omitted.
+func GetHookDeliveries(c *gin.Context) {
+	filter := model.HookDeliveryFilter{
+		Repo:  c.Query("repo"),
+		Event: c.Query("event"),
+		Limit: hookDeliveryLimit,
+	}
+	if pr := c.Query("pr"); pr != "" {
+		number, err := strconv.Atoi(pr)
+		if err != nil {
+			err = errors.New("Pull request number must be an integer")
+			c.Error(exterror.Create(http.StatusBadRequest, err))
+			return
+		}
+		filter.PullRequest = number
+	}
+	if limit := c.Query("limit"); limit != "" {
+		number, err := strconv.Atoi(limit)
+		if err != nil || number <= 0 || number > hookDeliveryMaxLimit {
+			err = fmt.Errorf("Limit must be an integer between 1 and %d", hookDeliveryMaxLimit)
+			c.Error(exterror.Create(http.StatusBadRequest, err))
+			return
+		}
+		filter.Limit = number
+	}
+	deliveries, err := store.GetHookDeliveries(c, filter)
+	if err != nil {
+		c.Error(exterror.Append(err, "Getting archived webhooks"))
+		return
+	}
+	for _, d := range deliveries {
+		d.Headers = ""
+		d.Payload = ""
+	}
+	c.IndentedJSON(200, deliveries)
+}
+
+// GetHookDelivery returns an archived webhook with its
+// redacted headers and payload.
+func GetHookDelivery(c *gin.Context) {
+	delivery, ok := HookDelivery(c)
+	if ok {
+		c.IndentedJSON(200, delivery)
+	}
+}
+
+// HookDelivery gets the archived webhook of the id parameter. The
+// error response is written when the webhook is not found.
+func HookDelivery(c *gin.Context) (*model.HookDelivery, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		err = errors.New("Archived webhook id must be an integer")
+		c.Error(exterror.Create(http.StatusBadRequest, err))
+		return nil, false
+	}
+	delivery, err := store.GetHookDelivery(c, id)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Getting archived webhook %d", id)))
+		return nil, false
+	}
+	return delivery, true
+}
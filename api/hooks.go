@@ -0,0 +1,69 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const hookJobLimit = 100
+
+// GetHookJobs lists the latest queued webhooks. The state query
+// parameter is either pending, done or failed (default).
+func GetHookJobs(c *gin.Context) {
+	state := c.DefaultQuery("state", model.HookJobFailed)
+	if state != model.HookJobPending && state != model.HookJobDone && state != model.HookJobFailed {
+		err := fmt.Errorf("Unknown webhook state %s", state)
+		c.Error(exterror.Create(http.StatusBadRequest, err))
+		return
+	}
+	jobs, err := store.GetHookJobsByState(c, state, hookJobLimit)
+	if err != nil {
+		c.Error(exterror.Append(err, "Getting webhooks"))
+		return
+	}
+	c.IndentedJSON(200, jobs)
+}
+
+// GetHookJob returns the status and result of a webhook delivery.
+func GetHookJob(c *gin.Context) {
+	job, ok := HookJob(c)
+	if ok {
+		c.IndentedJSON(200, job)
+	}
+}
+
+// HookJob gets the webhook of the delivery parameter. The
+// error response is written when the webhook is not found.
+func HookJob(c *gin.Context) (*model.HookJob, bool) {
+	delivery := c.Param("delivery")
+	job, err := store.GetHookJobDelivery(c, delivery)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Getting webhook delivery %s", delivery)))
+		return nil, false
+	}
+	return job, true
+}
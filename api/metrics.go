@@ -0,0 +1,43 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics serves the metrics in the Prometheus text format. When
+// METRICS_TOKEN is set the request must carry it as a bearer token.
+func Metrics(c *gin.Context) {
+	token := envvars.Env.Monitor.MetricsToken
+	if token != "" {
+		auth := []byte(c.Request.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(auth, []byte("Bearer "+token)) != 1 {
+			c.String(http.StatusUnauthorized, "A valid metrics token is required")
+			return
+		}
+	}
+	metrics.DefaultRegistry.Handler().ServeHTTP(c.Writer, c.Request)
+}
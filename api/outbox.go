@@ -0,0 +1,102 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOutbox lists the queued notifications. The state
+// query parameter is either pending or dead (default).
+func GetOutbox(c *gin.Context) {
+	state := c.DefaultQuery("state", model.OutboxDead)
+	if state != model.OutboxPending && state != model.OutboxDead {
+		err := fmt.Errorf("Unknown outbox state %s", state)
+		c.Error(exterror.Create(http.StatusBadRequest, err))
+		return
+	}
+	msgs, err := store.GetOutboxMessagesByState(c, state)
+	if err != nil {
+		c.Error(exterror.Append(err, "Getting outbox messages"))
+		return
+	}
+	c.IndentedJSON(200, msgs)
+}
+
+// GetOutboxMessage returns a queued notification.
+func GetOutboxMessage(c *gin.Context) {
+	msg, ok := outboxMessage(c)
+	if ok {
+		c.IndentedJSON(200, msg)
+	}
+}
+
+// RetryOutboxMessage schedules a dead notification
+// for immediate delivery.
+func RetryOutboxMessage(c *gin.Context) {
+	msg, ok := outboxMessage(c)
+	if !ok {
+		return
+	}
+	err := notifier.RetryOutboxMessage(c, msg)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Retrying outbox message %d", msg.ID)))
+		return
+	}
+	c.IndentedJSON(200, msg)
+}
+
+// DeleteOutboxMessage removes a notification from the outbox.
+func DeleteOutboxMessage(c *gin.Context) {
+	msg, ok := outboxMessage(c)
+	if !ok {
+		return
+	}
+	err := store.DeleteOutboxMessage(c, msg.ID)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Deleting outbox message %d", msg.ID)))
+		return
+	}
+	c.String(200, "")
+}
+
+func outboxMessage(c *gin.Context) (*model.OutboxMessage, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		err = errors.New("Outbox message id must be an integer")
+		c.Error(exterror.Create(http.StatusBadRequest, err))
+		return nil, false
+	}
+	msg, err := store.GetOutboxMessage(c, id)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Getting outbox message %d", id)))
+		return nil, false
+	}
+	return msg, true
+}
@@ -19,6 +19,7 @@ See the License for the specific language governing permissions and limitations
 package api
 
 import (
+	"github.com/capitalone/checks-out/remote"
 	"github.com/capitalone/checks-out/usage"
 
 	"github.com/gin-gonic/gin"
@@ -28,3 +29,8 @@ func AdminStats(c *gin.Context) {
 	stats := usage.GetStats()
 	IndentedJSON(c, 200, stats)
 }
+
+// GetRateLimits returns the GitHub rate limit state of the tokens.
+func GetRateLimits(c *gin.Context) {
+	IndentedJSON(c, 200, remote.RateLimits())
+}
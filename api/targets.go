@@ -0,0 +1,197 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/router/middleware/session"
+	"github.com/capitalone/checks-out/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UrlHolder struct {
+	Url string `json:"url"`
+}
+
+// SlackTarget, TeamsTarget and WebhookTarget serve the
+// registration endpoints of the notification targets.
+var (
+	SlackTarget = urlTarget("slack URL",
+		store.GetSlackUrl, store.AddUpdateSlackUrl, store.DeleteSlackUrl)
+	TeamsTarget = urlTarget("teams URL",
+		store.GetTeamsUrl, store.AddUpdateTeamsUrl, store.DeleteTeamsUrl)
+	WebhookTarget = &notifyTarget{
+		name:   "webhook target",
+		secret: true,
+		get:    store.GetWebhookTarget,
+		put:    store.AddUpdateWebhookTarget,
+		remove: store.DeleteWebhookTarget,
+	}
+)
+
+// notifyTarget stores the notification targets that are registered
+// for a hostname. The targets of each sender are registered by an
+// admin or by a user. A blank user selects the admin-level target.
+type notifyTarget struct {
+	// name of the target in error messages
+	name string
+	// secret is true when the target must have a signing secret
+	secret bool
+	get    func(c context.Context, hostname string, user string) (*model.WebhookTarget, error)
+	put    func(c context.Context, hostname string, user string, target *model.WebhookTarget) error
+	remove func(c context.Context, hostname string, user string) error
+}
+
+func (t *notifyTarget) register(c *gin.Context, user string, successCode int) {
+	var (
+		hostname = c.Param("hostname")
+	)
+	var target model.WebhookTarget
+	defer func() {
+		c.Request.Body.Close()
+	}()
+	msg := fmt.Sprintf("Registering %s by user %s for host %s ", t.name, user, hostname)
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(exterror.Append(err, msg))
+		return
+	}
+	err = json.Unmarshal(body, &target)
+	if err != nil {
+		c.Error(exterror.Append(exterror.Create(400, err), msg))
+		return
+	}
+	if target.Url == "" {
+		c.Error(exterror.Append(exterror.Create(400, errors.New("url is required")), msg))
+		return
+	}
+	if t.secret && target.Secret == "" {
+		c.Error(exterror.Append(exterror.Create(400, errors.New("secret is required")), msg))
+		return
+	}
+	err = t.put(c, hostname, user, &target)
+	if err != nil {
+		c.Error(exterror.Append(err, msg))
+	} else {
+		c.Status(successCode)
+	}
+}
+
+func (t *notifyTarget) show(c *gin.Context, user string) {
+	var (
+		hostname = c.Param("hostname")
+	)
+
+	target, err := t.get(c, hostname, user)
+	if err != nil {
+		msg := fmt.Sprintf("Getting %s by user %s for host %s ", t.name, user, hostname)
+		c.Error(exterror.Append(err, msg))
+	} else if target == nil {
+		c.Status(404)
+	} else {
+		// never return the signing secret
+		c.IndentedJSON(200, UrlHolder{
+			Url: target.Url,
+		})
+	}
+}
+
+func (t *notifyTarget) delete(c *gin.Context, user string) {
+	var (
+		hostname = c.Param("hostname")
+	)
+
+	err := t.remove(c, hostname, user)
+	if err != nil {
+		msg := fmt.Sprintf("Deleting %s by user %s for host %s ", t.name, user, hostname)
+		c.Error(exterror.Append(err, msg))
+	} else {
+		c.Status(204)
+	}
+}
+
+// Register, Get, Delete and Update manage the admin-level targets.
+
+func (t *notifyTarget) Register(c *gin.Context) {
+	t.register(c, "", 201)
+}
+
+func (t *notifyTarget) Get(c *gin.Context) {
+	t.show(c, "")
+}
+
+func (t *notifyTarget) Delete(c *gin.Context) {
+	t.delete(c, "")
+}
+
+func (t *notifyTarget) Update(c *gin.Context) {
+	t.register(c, "", 200)
+}
+
+// UserRegister, UserGet, UserDelete and UserUpdate manage
+// the targets of the current user.
+
+func (t *notifyTarget) UserRegister(c *gin.Context) {
+	user := session.User(c)
+	t.register(c, user.Login, 201)
+}
+
+func (t *notifyTarget) UserGet(c *gin.Context) {
+	user := session.User(c)
+	t.show(c, user.Login)
+}
+
+func (t *notifyTarget) UserDelete(c *gin.Context) {
+	user := session.User(c)
+	t.delete(c, user.Login)
+}
+
+func (t *notifyTarget) UserUpdate(c *gin.Context) {
+	user := session.User(c)
+	t.register(c, user.Login, 200)
+}
+
+// urlTarget adapts the store of a target that is only a URL.
+func urlTarget(name string,
+	get func(c context.Context, hostname string, user string) (string, error),
+	put func(c context.Context, hostname string, user string, url string) error,
+	remove func(c context.Context, hostname string, user string) error) *notifyTarget {
+	return &notifyTarget{
+		name: name,
+		get: func(c context.Context, hostname string, user string) (*model.WebhookTarget, error) {
+			url, err := get(c, hostname, user)
+			if err != nil || url == "" {
+				return nil, err
+			}
+			return &model.WebhookTarget{Url: url}, nil
+		},
+		put: func(c context.Context, hostname string, user string, target *model.WebhookTarget) error {
+			return put(c, hostname, user, target.Url)
+		},
+		remove: remove,
+	}
+}
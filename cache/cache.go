@@ -20,15 +20,28 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/koding/cache"
 	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
+
+	log "github.com/Sirupsen/logrus"
 )
 
+// ErrNotFound is returned by Get when the key is not in the cache.
+var ErrNotFound = errors.New("not found")
+
 type Cache interface {
 	Get(string) (interface{}, error)
 	Set(string, interface{}) error
+	// Delete removes the key from the cache
+	Delete(string) error
+	// DeletePrefix removes all the keys with the prefix from the cache
+	DeletePrefix(string) error
 }
 
 func Get(c context.Context, key string) (interface{}, error) {
@@ -39,6 +52,17 @@ func Set(c context.Context, key string, value interface{}) error {
 	return FromContext(c).Set(key, value)
 }
 
+// Delete removes the key from the cache associated with the context.
+func Delete(c context.Context, key string) error {
+	return FromContext(c).Delete(key)
+}
+
+// DeletePrefix removes the keys with the prefix from the cache
+// associated with the context.
+func DeletePrefix(c context.Context, prefix string) error {
+	return FromContext(c).DeletePrefix(prefix)
+}
+
 // Default creates an in-memory cache with the default
 // 30 minute expiration period.
 func Default() Cache {
@@ -48,9 +72,78 @@ func Default() Cache {
 // NewTTL returns an in-memory cache with the specified
 // ttl expiration period.
 func NewTTL(t time.Duration) Cache {
-	return cache.NewMemoryWithTTL(t)
+	return newMemory(t)
 }
 
+const (
+	MemoryBackend = "memory"
+	RedisBackend  = "redis"
+)
+
 var (
-	Longterm = cache.NewMemoryWithTTL(time.Duration(envvars.Env.Cache.LongCacheTTL))
+	once      sync.Once
+	shortterm Cache
+	longterm  Cache
 )
+
+// Shared returns the cache for short lived entries of the backend
+// selected by CACHE_BACKEND. Entries expire after CACHE_TTL.
+func Shared() Cache {
+	setup()
+	return shortterm
+}
+
+// Longterm returns the cache for long lived entries of the backend
+// selected by CACHE_BACKEND. Entries expire after LONG_CACHE_TTL.
+func Longterm() Cache {
+	setup()
+	return longterm
+}
+
+func setup() {
+	once.Do(func() {
+		var err error
+		env := envvars.Env.Cache
+		shortterm, longterm, err = open(env.Backend, env.RedisUrl, env.Namespace, env.CacheTTL, env.LongCacheTTL)
+		if err != nil {
+			log.Fatalf("Unable to create %s cache: %v", env.Backend, err)
+		}
+		shortterm = counted{shortterm, "short"}
+		longterm = counted{longterm, "long"}
+	})
+}
+
+// counted records the hits and misses of a cache.
+type counted struct {
+	Cache
+	name string
+}
+
+func (cc counted) Get(key string) (interface{}, error) {
+	val, err := cc.Cache.Get(key)
+	if err == nil {
+		metrics.CacheRequests.Inc(cc.name, "hit")
+	} else {
+		metrics.CacheRequests.Inc(cc.name, "miss")
+	}
+	return val, err
+}
+
+// open creates the short and long term caches of the backend. The
+// caches of the Redis backend are shared by all the instances of
+// the service so the keys are namespaced.
+func open(backend, url, namespace string, short, long time.Duration) (Cache, Cache, error) {
+	switch backend {
+	case "", MemoryBackend:
+		return NewTTL(short), NewTTL(long), nil
+	case RedisBackend:
+		pool, err := newRedisPool(url)
+		if err != nil {
+			return nil, nil, err
+		}
+		namespace = strings.TrimSuffix(namespace, ":")
+		return newRedis(pool, namespace+":short:", short), newRedis(pool, namespace+":long:", long), nil
+	default:
+		return nil, nil, fmt.Errorf("Unknown cache backend %s", backend)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/set"
+)
+
+// envelope records the type of a cached value so that
+// the value can be decoded into the same type.
+type envelope struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+const (
+	typeString  = "string"
+	typeSet     = "set"
+	typePerm    = "perm"
+	typeOrgs    = "orgs"
+	typePerson  = "person"
+	typeStrings = "strings"
+)
+
+// encode serializes the values that are stored in the cache.
+func encode(value interface{}) ([]byte, error) {
+	var t string
+	switch value.(type) {
+	case string:
+		t = typeString
+	case []string:
+		t = typeStrings
+	case set.Set:
+		t = typeSet
+	case *model.Perm:
+		t = typePerm
+	case []*model.GitHubOrg:
+		t = typeOrgs
+	case *model.Person:
+		t = typePerson
+	default:
+		return nil, fmt.Errorf("Unable to cache values of type %T", value)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Type: t, Value: raw})
+}
+
+// decode restores a value serialized by encode.
+func decode(data []byte) (interface{}, error) {
+	var env envelope
+	err := json.Unmarshal(data, &env)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	switch env.Type {
+	case typeString:
+		var v string
+		err = json.Unmarshal(env.Value, &v)
+		value = v
+	case typeStrings:
+		var v []string
+		err = json.Unmarshal(env.Value, &v)
+		value = v
+	case typeSet:
+		v := set.Empty()
+		err = json.Unmarshal(env.Value, &v)
+		value = v
+	case typePerm:
+		v := new(model.Perm)
+		err = json.Unmarshal(env.Value, v)
+		value = v
+	case typeOrgs:
+		var v []*model.GitHubOrg
+		err = json.Unmarshal(env.Value, &v)
+		value = v
+	case typePerson:
+		v := new(model.Person)
+		err = json.Unmarshal(env.Value, v)
+		value = v
+	default:
+		return nil, fmt.Errorf("Unknown cached type %s", env.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
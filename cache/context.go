@@ -21,3 +21,8 @@ func FromContext(c context.Context) Cache {
 func ToContext(c Setter, cache Cache) {
 	c.Set(key, cache)
 }
+
+// AddToContext returns a copy of the context with the Cache.
+func AddToContext(c context.Context, cache Cache) context.Context {
+	return context.WithValue(c, key, cache)
+}
@@ -0,0 +1,96 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// memory is an in-memory cache. Expired entries are
+// removed when they are read or when the cache is swept.
+type memory struct {
+	sync.RWMutex
+	ttl     time.Duration
+	entries map[string]entry
+	swept   time.Time
+}
+
+func newMemory(ttl time.Duration) *memory {
+	return &memory{ttl: ttl, entries: make(map[string]entry), swept: time.Now()}
+}
+
+func (m *memory) Get(key string) (interface{}, error) {
+	m.RLock()
+	e, ok := m.entries[key]
+	m.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if time.Now().After(e.expires) {
+		m.Delete(key)
+		return nil, ErrNotFound
+	}
+	return e.value, nil
+}
+
+func (m *memory) Set(key string, value interface{}) error {
+	now := time.Now()
+	m.Lock()
+	defer m.Unlock()
+	if now.Sub(m.swept) > m.ttl {
+		m.sweep(now)
+	}
+	m.entries[key] = entry{value: value, expires: now.Add(m.ttl)}
+	return nil
+}
+
+func (m *memory) Delete(key string) error {
+	m.Lock()
+	delete(m.entries, key)
+	m.Unlock()
+	return nil
+}
+
+func (m *memory) DeletePrefix(prefix string) error {
+	m.Lock()
+	for key := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+		}
+	}
+	m.Unlock()
+	return nil
+}
+
+// sweep removes the expired entries. The lock must be held.
+func (m *memory) sweep(now time.Time) {
+	for key, e := range m.entries {
+		if now.After(e.expires) {
+			delete(m.entries, key)
+		}
+	}
+	m.swept = now
+}
@@ -0,0 +1,297 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisMaxIdle     = 8
+	redisDialTimeout = 5 * time.Second
+	redisIOTimeout   = 5 * time.Second
+	redisScanCount   = "500"
+)
+
+// redisError is an error reply from the Redis server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a connection that speaks the Redis protocol (RESP).
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// redisPool keeps the idle connections to a Redis server.
+type redisPool struct {
+	addr     string
+	password string
+	db       int
+	tls      *tls.Config
+	idle     chan *redisConn
+}
+
+// newRedisPool parses a redis://[:password@]host:port[/db] url.
+// The rediss scheme connects to the server with TLS.
+func newRedisPool(rawurl string) (*redisPool, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("Redis url %s must have the redis or rediss scheme", rawurl)
+	}
+	p := &redisPool{
+		addr: u.Host,
+		idle: make(chan *redisConn, redisMaxIdle),
+	}
+	if u.Scheme == "rediss" {
+		p.tls = &tls.Config{ServerName: u.Hostname()}
+	}
+	if u.Port() == "" {
+		p.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		p.password, _ = u.User.Password()
+	}
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		p.db, err = strconv.Atoi(path)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Redis database %s", path)
+		}
+	}
+	return p, nil
+}
+
+func (p *redisPool) get() (*redisConn, error) {
+	select {
+	case rc := <-p.idle:
+		return rc, nil
+	default:
+	}
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	rc := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+	if p.password != "" {
+		if _, err = rc.do("AUTH", p.password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if p.db != 0 {
+		if _, err = rc.do("SELECT", strconv.Itoa(p.db)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+func (p *redisPool) dial() (net.Conn, error) {
+	if p.tls == nil {
+		return net.DialTimeout("tcp", p.addr, redisDialTimeout)
+	}
+	dialer := &net.Dialer{Timeout: redisDialTimeout}
+	return tls.DialWithDialer(dialer, "tcp", p.addr, p.tls)
+}
+
+func (p *redisPool) put(rc *redisConn) {
+	select {
+	case p.idle <- rc:
+	default:
+		rc.conn.Close()
+	}
+}
+
+// do sends a command on an idle connection. Connections with
+// network or protocol errors are closed instead of reused.
+func (p *redisPool) do(args ...string) (interface{}, error) {
+	rc, err := p.get()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := rc.do(args...)
+	if _, ok := err.(redisError); err != nil && !ok {
+		rc.conn.Close()
+		return nil, err
+	}
+	p.put(rc)
+	return reply, err
+}
+
+func (rc *redisConn) do(args ...string) (interface{}, error) {
+	rc.conn.SetDeadline(time.Now().Add(redisIOTimeout))
+	fmt.Fprintf(rc.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(rc.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	err := rc.w.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return rc.read()
+}
+
+// read parses a reply. Bulk strings are returned as []byte,
+// integers as int64 and arrays as []interface{}.
+func (rc *redisConn) read() (interface{}, error) {
+	line, err := rc.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		_, err = io.ReadFull(rc.r, buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			items[i], err = rc.read()
+			if _, ok := err.(redisError); err != nil && !ok {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+// redis is a cache stored in a Redis server that is shared by the
+// instances of the service. Values are serialized with their type.
+// Deleted keys are invalidated for all the instances.
+type redis struct {
+	pool      *redisPool
+	namespace string
+	ttl       time.Duration
+}
+
+// newRedis returns a cache that stores the keys in the namespace
+// of the Redis server with the specified ttl expiration period.
+func newRedis(pool *redisPool, namespace string, ttl time.Duration) Cache {
+	return &redis{pool: pool, namespace: namespace, ttl: ttl}
+}
+
+func (r *redis) Get(key string) (interface{}, error) {
+	reply, err := r.pool.do("GET", r.namespace+key)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return decode(data)
+}
+
+func (r *redis) Set(key string, value interface{}) error {
+	data, err := encode(value)
+	if err != nil {
+		return err
+	}
+	ttl := strconv.FormatInt(int64(r.ttl/time.Millisecond), 10)
+	_, err = r.pool.do("SET", r.namespace+key, string(data), "PX", ttl)
+	return err
+}
+
+func (r *redis) Delete(key string) error {
+	_, err := r.pool.do("DEL", r.namespace+key)
+	return err
+}
+
+func (r *redis) DeletePrefix(prefix string) error {
+	match := escapeGlob(r.namespace+prefix) + "*"
+	cursor := "0"
+	for {
+		reply, err := r.pool.do("SCAN", cursor, "MATCH", match, "COUNT", redisScanCount)
+		if err != nil {
+			return err
+		}
+		items, ok := reply.([]interface{})
+		if !ok || len(items) != 2 {
+			return fmt.Errorf("redis: unexpected SCAN reply %v", reply)
+		}
+		next, _ := items[0].([]byte)
+		keys, _ := items[1].([]interface{})
+		if len(keys) > 0 {
+			args := []string{"DEL"}
+			for _, k := range keys {
+				b, _ := k.([]byte)
+				args = append(args, string(b))
+			}
+			if _, err = r.pool.do(args...); err != nil {
+				return err
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// escapeGlob escapes the glob characters of a SCAN pattern.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`*?[]\`, c) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,228 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/set"
+)
+
+// fakeRedis answers the GET, SET, DEL and SCAN commands
+// from a map. SCAN returns all the keys in one reply.
+type fakeRedis struct {
+	sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func (f *fakeRedis) serve(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.handle(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	rc := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+	for {
+		req, err := rc.read()
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, a := range req.([]interface{}) {
+			args = append(args, string(a.([]byte)))
+		}
+		f.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		switch args[0] {
+		case "GET":
+			v, ok := f.data[args[1]]
+			if ok {
+				fmt.Fprintf(rc.w, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				fmt.Fprint(rc.w, "$-1\r\n")
+			}
+		case "SET":
+			f.data[args[1]] = args[2]
+			fmt.Fprint(rc.w, "+OK\r\n")
+		case "DEL":
+			for _, k := range args[1:] {
+				delete(f.data, k)
+			}
+			fmt.Fprintf(rc.w, ":%d\r\n", len(args)-1)
+		case "SCAN":
+			prefix := strings.Replace(strings.TrimSuffix(args[3], "*"), `\`, "", -1)
+			var keys []string
+			for k := range f.data {
+				if strings.HasPrefix(k, prefix) {
+					keys = append(keys, k)
+				}
+			}
+			fmt.Fprintf(rc.w, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+			for _, k := range keys {
+				fmt.Fprintf(rc.w, "$%d\r\n%s\r\n", len(k), k)
+			}
+		default:
+			fmt.Fprintf(rc.w, "-ERR unknown command %s\r\n", args[0])
+		}
+		f.Unlock()
+		rc.w.Flush()
+	}
+}
+
+func newFakeRedis(t *testing.T) (*fakeRedis, Cache) {
+	f := &fakeRedis{data: make(map[string]string)}
+	short, _, err := open(RedisBackend, "redis://"+f.serve(t), "test", time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, short
+}
+
+func TestRedisTypes(t *testing.T) {
+	_, c := newFakeRedis(t)
+	values := map[string]interface{}{
+		"string": "bar",
+		"set":    set.New("dev", "ops"),
+		"perm":   &model.Perm{Pull: true, Push: true},
+		"orgs":   []*model.GitHubOrg{{Login: "octocat", Admin: true}},
+		"person": &model.Person{Login: "alice", Slack: "U0ALICE"},
+	}
+	for key, value := range values {
+		if err := c.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	teams, err := c.Get("set")
+	if err != nil || !teams.(set.Set).Contains("ops") || len(teams.(set.Set)) != 2 {
+		t.Errorf("Unexpected set %v %v", teams, err)
+	}
+	perm, err := c.Get("perm")
+	if err != nil || !perm.(*model.Perm).Push || perm.(*model.Perm).Admin {
+		t.Errorf("Unexpected perm %v %v", perm, err)
+	}
+	orgs, err := c.Get("orgs")
+	if err != nil || len(orgs.([]*model.GitHubOrg)) != 1 || orgs.([]*model.GitHubOrg)[0].Login != "octocat" {
+		t.Errorf("Unexpected orgs %v %v", orgs, err)
+	}
+	person, err := c.Get("person")
+	if err != nil || person.(*model.Person).Slack != "U0ALICE" {
+		t.Errorf("Unexpected person %v %v", person, err)
+	}
+	if err = c.Set("unsupported", 42); err == nil {
+		t.Error("Expected error caching unsupported type")
+	}
+}
+
+func TestRedisNamespace(t *testing.T) {
+	f, c := newFakeRedis(t)
+	c.Set("teams:octocat", set.New("dev"))
+	if _, ok := f.data["test:short:teams:octocat"]; !ok {
+		t.Errorf("Expected namespaced key but was %v", f.data)
+	}
+	if !strings.HasSuffix(f.commands[0], "PX 60000") {
+		t.Errorf("Expected ttl of CACHE_TTL but was %s", f.commands[0])
+	}
+	if _, err := c.Get("teams:missing"); err != ErrNotFound {
+		t.Errorf("Expected not found but was %v", err)
+	}
+}
+
+func TestRedisDelete(t *testing.T) {
+	f, c := newFakeRedis(t)
+	c.Set("perms:octocat/hello:alice", &model.Perm{Pull: true})
+	c.Set("perms:octocat/hello:bob", &model.Perm{Pull: true})
+	c.Set("perms:octocat/world:alice", &model.Perm{Pull: true})
+	c.Set("teams:octocat", set.New("dev"))
+	if err := c.Delete("teams:octocat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeletePrefix("perms:octocat/hello:"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.data) != 1 {
+		t.Errorf("Expected only octocat/world to remain but was %v", f.data)
+	}
+}
+
+func TestMemoryDeletePrefix(t *testing.T) {
+	c := NewTTL(time.Minute)
+	c.Set("perms:octocat/hello:alice", true)
+	c.Set("perms:octocat/world:alice", true)
+	c.DeletePrefix("perms:octocat/hello:")
+	if _, err := c.Get("perms:octocat/hello:alice"); err != ErrNotFound {
+		t.Errorf("Expected key to be deleted but was %v", err)
+	}
+	if _, err := c.Get("perms:octocat/world:alice"); err != nil {
+		t.Errorf("Expected key to remain but was %v", err)
+	}
+}
+
+func TestMemoryExpires(t *testing.T) {
+	c := NewTTL(time.Millisecond)
+	c.Set("foo", "bar")
+	time.Sleep(5 * time.Millisecond)
+	if _, err := c.Get("foo"); err != ErrNotFound {
+		t.Errorf("Expected key to expire but was %v", err)
+	}
+}
+
+func TestRedisPoolURL(t *testing.T) {
+	p, err := newRedisPool("rediss://:secret@cache.example.com/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.addr != "cache.example.com:6379" || p.password != "secret" || p.db != 2 {
+		t.Errorf("Unexpected pool %v", p)
+	}
+	if p.tls == nil || p.tls.ServerName != "cache.example.com" {
+		t.Error("Expected the rediss scheme to connect with TLS")
+	}
+	p, err = newRedisPool("redis://cache.example.com:6380")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.tls != nil || p.addr != "cache.example.com:6380" {
+		t.Errorf("Unexpected pool %v", p)
+	}
+	if _, err = newRedisPool("http://cache.example.com"); err == nil {
+		t.Error("Expected error for the http scheme")
+	}
+}
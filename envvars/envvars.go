@@ -60,10 +60,80 @@ type EnvValues struct {
 		Scope      string
 		AdminOrg   string
 		RequestsHz int
+		// Requests of a token are paced when its remaining
+		// rate limit is below RateLow
+		RateLow      int
+		RateMaxWait  time.Duration
+		AbuseRetries int
+		// GraphQL loads the pull request evaluation data
+		// with GraphQL queries
+		GraphQL bool
+		// ETagCacheSize is the number of REST responses
+		// kept for conditional requests
+		ETagCacheSize int
+	}
+	// Tag signing
+	Signing struct {
+		GpgKey     string
+		GpgProgram string
 	}
 	// Slack integration
 	Slack struct {
 		TargetUrl string
+		BotToken  string
+		// SigningSecret verifies the requests from Slack
+		SigningSecret string
+		// LinkSecret signs the links that connect Slack
+		// users to GitHub accounts
+		LinkSecret string
+	}
+	// Microsoft Teams integration
+	Teams struct {
+		TargetUrl string
+	}
+	// Notification outbox
+	Notify struct {
+		Workers     int
+		MaxAttempts int
+		Backoff     time.Duration
+	}
+	// Webhook processing
+	Hook struct {
+		Workers     int
+		MaxAttempts int
+		Backoff     time.Duration
+		Retention   time.Duration
+		// Archive of the incoming webhooks
+		ArchiveRetention  time.Duration
+		ArchiveMaxPayload int
+	}
+	// Pull request locking
+	Lock struct {
+		Lease    bool
+		LeaseTTL time.Duration
+		Wait     time.Duration
+	}
+	// Periodic reconciliation of open pull requests
+	Reconcile struct {
+		Interval time.Duration
+		Workers  int
+		// RateReserve is the remaining rate limit of a token
+		// that is left for webhooks
+		RateReserve int
+	}
+	// Email (SMTP) integration
+	Email struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+		TLS      string
+	}
+	// Outbound webhook integration
+	Webhook struct {
+		TargetUrl string
+		Secret    string
 	}
 	// Logging/debug config
 	Monitor struct {
@@ -72,11 +142,17 @@ type EnvValues struct {
 		UaList    string
 		LogPeriod time.Duration
 		DocsUrl   string
+		// MetricsToken protects the metrics endpoint
+		MetricsToken string
 	}
 	// Caching config
 	Cache struct {
 		CacheTTL     time.Duration
 		LongCacheTTL time.Duration
+		// Backend is either memory or redis
+		Backend   string
+		RedisUrl  string
+		Namespace string
 	}
 	// Github testing config
 	Test struct {
@@ -130,17 +206,56 @@ func configure() {
 	envflag.StringVar(&Env.Github.Scope, "GITHUB_SCOPE", "read:org,repo:status,admin:repo_hook,admin:org_hook", "Permission scope")
 	envflag.StringVar(&Env.Github.AdminOrg, "GITHUB_ADMIN_ORG", "", "GitHub organization with admin privileges")
 	envflag.IntVar(&Env.Github.RequestsHz, "GITHUB_BATCH_PER_SECOND", 10, "GitHub batch access rate limiter")
+	envflag.IntVar(&Env.Github.RateLow, "GITHUB_RATE_LOW", 500, "Remaining GitHub rate limit below which the requests of a token are paced")
+	envflag.DurationVar(&Env.Github.RateMaxWait, "GITHUB_RATE_MAX_WAIT", 30*time.Second, "Longest wait of a GitHub request for the rate limit")
+	envflag.IntVar(&Env.Github.AbuseRetries, "GITHUB_ABUSE_RETRIES", 3, "Retries of GitHub requests that hit a secondary rate limit")
+	envflag.BoolVar(&Env.Github.GraphQL, "GITHUB_GRAPHQL", false, "Load pull request evaluation data with GraphQL queries")
+	envflag.IntVar(&Env.Github.ETagCacheSize, "GITHUB_ETAG_CACHE_SIZE", 10000, "Number of GitHub responses cached for conditional requests")
+
+	envflag.StringVar(&Env.Signing.GpgKey, "GPG_SIGNING_KEY", "", "GPG key id for signing tags")
+	envflag.StringVar(&Env.Signing.GpgProgram, "GPG_PROGRAM", "gpg", "GPG program for signing tags")
 
 	envflag.StringVar(&Env.Slack.TargetUrl, "SLACK_TARGET_URL", "", "Slack notification url")
+	envflag.StringVar(&Env.Slack.BotToken, "SLACK_BOT_TOKEN", "", "Slack bot token for direct messages")
+	envflag.StringVar(&Env.Slack.SigningSecret, "SLACK_SIGNING_SECRET", "", "Slack signing secret for slash commands and interactive messages")
+	envflag.StringVar(&Env.Slack.LinkSecret, "SLACK_LINK_SECRET", "", "Secret that signs the links between Slack users and GitHub accounts")
+	envflag.StringVar(&Env.Teams.TargetUrl, "TEAMS_TARGET_URL", "", "Microsoft Teams notification url")
+	envflag.IntVar(&Env.Notify.Workers, "NOTIFY_WORKERS", 4, "Number of notification outbox workers; 0 delivers notifications immediately")
+	envflag.IntVar(&Env.Notify.MaxAttempts, "NOTIFY_MAX_ATTEMPTS", 8, "Delivery attempts before a notification is dead-lettered")
+	envflag.DurationVar(&Env.Notify.Backoff, "NOTIFY_BACKOFF", 5*time.Second, "Delay before the first notification retry")
+	envflag.IntVar(&Env.Hook.Workers, "HOOK_WORKERS", 4, "Number of webhook workers; 0 processes webhooks during the request")
+	envflag.IntVar(&Env.Hook.MaxAttempts, "HOOK_MAX_ATTEMPTS", 5, "Processing attempts before a webhook fails")
+	envflag.DurationVar(&Env.Hook.Backoff, "HOOK_BACKOFF", 10*time.Second, "Delay before the first webhook retry")
+	envflag.DurationVar(&Env.Hook.Retention, "HOOK_RETENTION", 72*time.Hour, "How long processed webhooks are kept for deduplication and status")
+	envflag.DurationVar(&Env.Hook.ArchiveRetention, "HOOK_ARCHIVE_RETENTION", 7*24*time.Hour, "How long incoming webhooks are archived; 0 disables the archive")
+	envflag.IntVar(&Env.Hook.ArchiveMaxPayload, "HOOK_ARCHIVE_MAX_PAYLOAD", 1<<20, "Largest webhook payload in bytes that is archived")
+	envflag.BoolVar(&Env.Lock.Lease, "LOCK_LEASE", false, "Lock pull requests with database leases when several instances share the database")
+	envflag.DurationVar(&Env.Lock.LeaseTTL, "LOCK_LEASE_TTL", 2*time.Minute, "Expiration of a pull request lease that is not renewed")
+	envflag.DurationVar(&Env.Lock.Wait, "LOCK_WAIT", 5*time.Minute, "How long to wait for a locked pull request")
+	envflag.DurationVar(&Env.Reconcile.Interval, "RECONCILE_INTERVAL", 0, "Interval between reconciliations of the open pull requests; 0 disables them")
+	envflag.IntVar(&Env.Reconcile.Workers, "RECONCILE_WORKERS", 2, "Number of repositories reconciled concurrently")
+	envflag.IntVar(&Env.Reconcile.RateReserve, "RECONCILE_RATE_RESERVE", 1000, "Remaining GitHub rate limit of a token below which reconciliation skips its repositories")
+	envflag.StringVar(&Env.Email.Host, "SMTP_HOST", "", "SMTP server hostname for email notifications")
+	envflag.IntVar(&Env.Email.Port, "SMTP_PORT", 587, "SMTP server port for email notifications")
+	envflag.StringVar(&Env.Email.Username, "SMTP_USERNAME", "", "SMTP username")
+	envflag.StringVar(&Env.Email.Password, "SMTP_PASSWORD", "", "SMTP password")
+	envflag.StringVar(&Env.Email.From, "SMTP_FROM", "", "Sender address of email notifications")
+	envflag.StringVar(&Env.Email.TLS, "SMTP_TLS", "starttls", "SMTP connection security: starttls, tls or none")
+	envflag.StringVar(&Env.Webhook.TargetUrl, "WEBHOOK_TARGET_URL", "", "Outbound webhook notification url")
+	envflag.StringVar(&Env.Webhook.Secret, "WEBHOOK_SECRET", "", "Outbound webhook signing secret")
 
 	envflag.StringVar(&Env.Monitor.LogLevel, "LOG_LEVEL", "info", "One of debug|info|warn|error|fatal|panic")
 	envflag.BoolVar(&Env.Monitor.Sunlight, "CHECKS_OUT_SUNLIGHT", false, "Exposes additional endpoints")
 	envflag.StringVar(&Env.Monitor.UaList, "BLACKLIST_USER_AGENTS", "", "Skip logging of these agents")
 	envflag.DurationVar(&Env.Monitor.LogPeriod, "LOG_STATS_PERIOD", 0, "Period logging of statistics")
+	envflag.StringVar(&Env.Monitor.MetricsToken, "METRICS_TOKEN", "", "Bearer token required to read the metrics")
 	envflag.StringVar(&Env.Monitor.DocsUrl, "CHECKS_OUT_DOCS_URL", "https://capitalone.github.com/checks-out/docs", "Provides the base URL for links to the documentation.")
 
 	envflag.DurationVar(&Env.Cache.CacheTTL, "CACHE_TTL", time.Minute*15, "Cache length for short lived entries")
 	envflag.DurationVar(&Env.Cache.LongCacheTTL, "LONG_CACHE_TTL", time.Hour*24, "Cache length for long lived entries")
+	envflag.StringVar(&Env.Cache.Backend, "CACHE_BACKEND", "memory", "Cache backend (memory or redis)")
+	envflag.StringVar(&Env.Cache.RedisUrl, "CACHE_REDIS_URL", "", "Redis url of the redis cache backend")
+	envflag.StringVar(&Env.Cache.Namespace, "CACHE_NAMESPACE", "checks-out", "Namespace of the keys in the redis cache backend")
 
 	envflag.StringVar(&Env.Test.GithubToken, "GITHUB_TEST_TOKEN", "", "GitHub integration test token")
 	envflag.BoolVar(&Env.Test.GithubTestEnable, "GITHUB_TEST_ENABLE", false, "GitHub integration testing")
@@ -198,6 +313,18 @@ func Validate() error {
 			"'debug', 'info', 'warn', 'error', 'fatal', 'panic'")
 		errs = multierror.Append(errs, err)
 	}
+	if Env.Cache.Backend != "memory" && Env.Cache.Backend != "redis" {
+		err := fmt.Errorf("Environment variable CACHE_BACKEND '%s' must be one of: 'memory', 'redis'", Env.Cache.Backend)
+		errs = multierror.Append(errs, err)
+	}
+	if Env.Cache.Backend == "redis" && Env.Cache.RedisUrl == "" {
+		err := errors.New("Missing required environment variable CACHE_REDIS_URL for the redis cache backend")
+		errs = multierror.Append(errs, err)
+	}
+	if Env.Cache.Backend == "redis" && (Env.Cache.CacheTTL <= 0 || Env.Cache.LongCacheTTL <= 0) {
+		err := errors.New("Environment variables CACHE_TTL and LONG_CACHE_TTL must be positive for the redis cache backend")
+		errs = multierror.Append(errs, err)
+	}
 	if !strings.HasPrefix(Env.Github.Url, "https://") {
 		err := errors.New("GITHUB_URL must have prefix 'https://'")
 		errs = multierror.Append(errs, err)
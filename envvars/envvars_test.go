@@ -21,6 +21,7 @@ package envvars
 import (
 	"flag"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ianschenck/envflag"
@@ -118,3 +119,24 @@ func TestLogLevel(t *testing.T) {
 	os.Setenv("LOG_LEVEL", logLevel)
 	configure()
 }
+
+func TestRedisCacheTTL(t *testing.T) {
+	setup()
+	required()
+	os.Setenv("CACHE_BACKEND", "redis")
+	os.Setenv("CACHE_REDIS_URL", "redis://localhost")
+	os.Setenv("CACHE_TTL", "0s")
+	configure()
+
+	err := Validate()
+	exp := "Environment variables CACHE_TTL and LONG_CACHE_TTL must be positive for the redis cache backend"
+	if err == nil || !strings.Contains(err.Error(), exp) {
+		t.Error("Cache ttl error not reported ", err)
+	}
+
+	teardown()
+	os.Unsetenv("CACHE_BACKEND")
+	os.Unsetenv("CACHE_REDIS_URL")
+	os.Unsetenv("CACHE_TTL")
+	configure()
+}
@@ -1,45 +1,52 @@
 module github.com/capitalone/checks-out
 
+go 1.27.1
+
 require (
-	github.com/BurntSushi/toml v0.3.0 // indirect
 	github.com/Sirupsen/logrus v0.11.5
-	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/dgrijalva/jwt-go v3.1.0+incompatible
 	github.com/elazarl/go-bindata-assetfs v1.0.0
 	github.com/franela/goblin v0.0.0-20170821161553-b962efd4bb2a
-	github.com/gin-contrib/sse v0.0.0-20170109093832-22d885f9ecc7 // indirect
 	github.com/gin-gonic/gin v0.0.0-20170702092826-d459835d2b07
 	github.com/go-sql-driver/mysql v1.3.0
-	github.com/golang/protobuf v0.0.0-20170920220647-130e6b02ab05 // indirect
 	github.com/google/go-github v0.0.0-20180723152927-e1be32f26e66
-	github.com/google/go-querystring v0.0.0-20170111101155-53e6ce116135 // indirect
-	github.com/hashicorp/errwrap v0.0.0-20141028054710-7554cd9344ce // indirect
 	github.com/hashicorp/go-version v0.0.0-20170914154128-fc61389e27c7
 	github.com/hjson/hjson-go v0.2.3
 	github.com/ianschenck/envflag v0.0.0-20140720210342-9111d830d133
 	github.com/joho/godotenv v1.2.0
 	github.com/koding/cache v0.0.0-20161222233015-e8a81b0b3f20
-	github.com/kr/pretty v0.1.0 // indirect
 	github.com/lib/pq v0.0.0-20171019223007-456514e2defe
-	github.com/mailru/easyjson v0.0.0-20180823135443-60711f1a8329 // indirect
-	github.com/mattn/go-isatty v0.0.3 // indirect
 	github.com/mattn/go-jsonpointer v0.0.0-20170427002117-a39417dc2a77
 	github.com/mattn/go-sqlite3 v1.2.0
 	github.com/mspiegel/go-multierror v0.0.0-20170309222903-065aa648c994
 	github.com/pelletier/go-toml v1.0.1
-	github.com/philhofer/fwd v1.0.0 // indirect
 	github.com/pkg/errors v0.8.0
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/pquerna/ffjson v0.0.0-20180717144149-af8b230fcd20 // indirect
 	github.com/rubenv/sql-migrate v0.0.0-20170824124545-79fe99e24311
 	github.com/russross/meddler v0.0.0-20170319163028-9515f6b01c15
-	github.com/stretchr/objx v0.0.0-20150928122152-1a9d0bb9f541 // indirect
 	github.com/stretchr/testify v1.1.4
+	golang.org/x/oauth2 v0.0.0-20170928010508-bb50c06baba3
+)
+
+require (
+	github.com/BurntSushi/toml v0.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/gin-contrib/sse v0.0.0-20170109093832-22d885f9ecc7 // indirect
+	github.com/golang/protobuf v0.0.0-20170920220647-130e6b02ab05 // indirect
+	github.com/google/go-querystring v0.0.0-20170111101155-53e6ce116135 // indirect
+	github.com/hashicorp/errwrap v0.0.0-20141028054710-7554cd9344ce // indirect
+	github.com/kr/pretty v0.1.0 // indirect
+	github.com/kr/pty v1.1.1 // indirect
+	github.com/kr/text v0.1.0 // indirect
+	github.com/mailru/easyjson v0.0.0-20180823135443-60711f1a8329 // indirect
+	github.com/mattn/go-isatty v0.0.3 // indirect
+	github.com/philhofer/fwd v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/pquerna/ffjson v0.0.0-20180717144149-af8b230fcd20 // indirect
+	github.com/stretchr/objx v0.0.0-20150928122152-1a9d0bb9f541 // indirect
 	github.com/tinylib/msgp v1.0.2 // indirect
 	github.com/ugorji/go v0.0.0-20171019201919-bdcc60b419d1 // indirect
 	github.com/ziutek/mymysql v1.5.4 // indirect
 	golang.org/x/net v0.0.0-20171019164906-aabf50738bcd // indirect
-	golang.org/x/oauth2 v0.0.0-20170928010508-bb50c06baba3
 	golang.org/x/sync v0.0.0-20180314180146-1d60e4601c6f // indirect
 	golang.org/x/sys v0.0.0-20171017063910-8dbc5d05d6ed // indirect
 	google.golang.org/appengine v1.0.0 // indirect
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/set"
 	"github.com/capitalone/checks-out/store/datastore"
@@ -80,6 +81,9 @@ func writeLog() {
 		log.Error("Periodic logging unable to fetch repository list", err)
 	} else {
 		users, orgs := usersAndOrgs(repos)
+		metrics.Repositories.Set(float64(len(repos)))
+		metrics.Owners.Set(float64(len(users)), "user")
+		metrics.Owners.Set(float64(len(orgs)), "org")
 		log.Infof("Monitoring %d repositories", len(repos))
 		log.Infof("Monitoring %d users", len(users))
 		log.Infof("Monitoring %d organizations", len(orgs))
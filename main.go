@@ -19,21 +19,31 @@ See the License for the specific language governing permissions and limitations
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/capitalone/checks-out/cache"
 	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/logstats"
 	"github.com/capitalone/checks-out/migration"
+	"github.com/capitalone/checks-out/notifier"
+	_ "github.com/capitalone/checks-out/notifier/email"
 	_ "github.com/capitalone/checks-out/notifier/github"
 	_ "github.com/capitalone/checks-out/notifier/slack"
+	_ "github.com/capitalone/checks-out/notifier/teams"
+	_ "github.com/capitalone/checks-out/notifier/webhook"
 	"github.com/capitalone/checks-out/remote"
 	"github.com/capitalone/checks-out/router"
+	"github.com/capitalone/checks-out/store"
 	"github.com/capitalone/checks-out/store/datastore"
-	"github.com/capitalone/checks-out/usage"
 	"github.com/capitalone/checks-out/version"
+	"github.com/capitalone/checks-out/web"
 
 	"github.com/Sirupsen/logrus"
 	_ "github.com/joho/godotenv/autoload"
@@ -68,7 +78,6 @@ func startService() {
 	setLogLevel(envvars.Env.Monitor.LogLevel)
 
 	logstats.Start()
-	usage.Start()
 
 	r := remote.Get()
 	ds := datastore.Get()
@@ -79,20 +88,70 @@ func startService() {
 		logrus.Fatal(err)
 	}
 
-	handler := router.Load()
+	// the outbox, hook workers and reconciler run outside of
+	// the webhook requests so they carry their own context. It is
+	// not cancelled on shutdown so that the work in progress can
+	// finish; closing stop ends the dispatch of new work.
+	ctx := context.Background()
+	ctx = store.AddToContext(ctx, ds)
+	ctx = remote.AddToContext(ctx, r)
+	ctx = cache.AddToContext(ctx, cache.Shared())
+	stop := make(chan struct{})
+	workers := []<-chan struct{}{
+		notifier.StartOutbox(ctx, stop, envvars.Env.Notify.Workers),
+		web.StartHookWorkers(ctx, stop, envvars.Env.Hook.Workers),
+		web.StartReconciler(ctx, stop, envvars.Env.Reconcile.Interval, envvars.Env.Reconcile.Workers),
+	}
+
+	server := &http.Server{
+		Addr:    envvars.Env.Server.Addr,
+		Handler: router.Load(),
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		shutdownOnSignal(server, stop, workers)
+	}()
 
 	logrus.Infof("Starting %s service on %s", envvars.Env.Branding.ShortName, time.Now().Format(time.RFC1123))
 
 	if envvars.Env.Server.Cert != "" {
-		logrus.Fatal(
-			http.ListenAndServeTLS(envvars.Env.Server.Addr, envvars.Env.Server.Cert, envvars.Env.Server.Key, handler),
-		)
+		err = server.ListenAndServeTLS(envvars.Env.Server.Cert, envvars.Env.Server.Key)
 	} else {
-		logrus.Fatal(
-			http.ListenAndServe(envvars.Env.Server.Addr, handler),
-		)
+		err = server.ListenAndServe()
+	}
+	if err != http.ErrServerClosed {
+		logrus.Fatal(err)
 	}
+	// the server returns as soon as the shutdown starts
+	<-stopped
+	logrus.Infof("Stopped %s service", envvars.Env.Branding.ShortName)
+}
 
+// shutdownOnSignal stops the background workers and the server on
+// SIGINT or SIGTERM. The outbox, the hook workers and the reconciler
+// finish the work in progress before the server stops.
+func shutdownOnSignal(server *http.Server, stop chan<- struct{}, workers []<-chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	sig := <-signals
+	logrus.Infof("Received %s, shutting down", sig)
+	close(stop)
+	timeout := time.After(30 * time.Second)
+wait:
+	for _, done := range workers {
+		select {
+		case <-done:
+		case <-timeout:
+			logrus.Warn("Timed out waiting for the background workers to stop")
+			break wait
+		}
+	}
+	ctx, done := context.WithTimeout(context.Background(), 10*time.Second)
+	defer done()
+	if err := server.Shutdown(ctx); err != nil {
+		logrus.Warnf("Unable to shut down the server: %s", err)
+	}
 }
 
 func main() {
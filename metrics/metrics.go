@@ -0,0 +1,92 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package metrics
+
+// The metrics of the service. Every metric is exposed on /metrics
+// and the usage statistics of the admin API are read from them.
+var (
+	// HooksReceived counts the incoming webhooks by event.
+	HooksReceived = NewCounterVec("checks_out_webhooks_received_total",
+		"Incoming GitHub webhooks.", "event")
+	// HooksProcessed counts the processed webhooks by event and
+	// outcome (success or error).
+	HooksProcessed = NewCounterVec("checks_out_webhooks_processed_total",
+		"Processed GitHub webhooks.", "event", "outcome")
+	// EvaluationSeconds is the latency of pull request evaluations.
+	EvaluationSeconds = NewHistogramVec("checks_out_evaluation_duration_seconds",
+		"Duration of pull request approval evaluations.", nil)
+	// GitHubRequests counts the GitHub API calls by endpoint and
+	// response status.
+	GitHubRequests = NewCounterVec("checks_out_github_requests_total",
+		"GitHub API requests.", "endpoint", "status")
+	// GitHubRequestsByUser counts the GitHub API calls by the user
+	// whose token made them.
+	GitHubRequestsByUser = NewCounterVec("checks_out_github_user_requests_total",
+		"GitHub API requests by token owner.", "user")
+	// GitHubRequestsByEvent counts the GitHub API calls by the
+	// webhook event that caused them.
+	GitHubRequestsByEvent = NewCounterVec("checks_out_github_event_requests_total",
+		"GitHub API requests by webhook event.", "event")
+	// GitHubRateLimitRemaining is the remaining GitHub API rate
+	// limit of each token owner and rate limit resource.
+	GitHubRateLimitRemaining = NewGaugeVec("checks_out_github_rate_limit_remaining",
+		"Remaining GitHub API requests of the rate limit window.", "user", "resource")
+	// GitHubRateLimited counts the GitHub API calls that were
+	// delayed, rejected or retried because of the rate limit.
+	GitHubRateLimited = NewCounterVec("checks_out_github_rate_limited_total",
+		"GitHub API requests delayed, rejected or retried because of the rate limit.", "user", "action")
+	// Merges counts the pull requests merges by outcome.
+	Merges = NewCounterVec("checks_out_merges_total",
+		"Pull request merges.", "outcome")
+	// Tags counts the tags created after merges by outcome.
+	Tags = NewCounterVec("checks_out_tags_total",
+		"Tags created after merges.", "outcome")
+	// Notifications counts the notification delivery attempts by
+	// target and outcome (delivered or failed).
+	Notifications = NewCounterVec("checks_out_notifications_total",
+		"Notification delivery attempts.", "target", "outcome")
+	// NotificationsDead counts the notifications that ran out of
+	// delivery attempts.
+	NotificationsDead = NewCounterVec("checks_out_notifications_dead_total",
+		"Notifications that ran out of delivery attempts.", "target")
+	// Reconciled counts the pull requests visited by the
+	// reconciler by result (unchanged, repaired, merged, skipped
+	// or error).
+	Reconciled = NewCounterVec("checks_out_reconciled_total",
+		"Pull requests visited by the reconciler.", "result")
+	// CacheRequests counts the lookups of the short and long term
+	// caches by result (hit or miss).
+	CacheRequests = NewCounterVec("checks_out_cache_requests_total",
+		"Cache lookups.", "cache", "result")
+	// Repositories is the number of enabled repositories.
+	Repositories = NewGaugeVec("checks_out_repositories",
+		"Enabled repositories.")
+	// Owners is the number of users and organizations with
+	// enabled repositories.
+	Owners = NewGaugeVec("checks_out_owners",
+		"Users and organizations with enabled repositories.", "type")
+)
+
+// Outcome returns the outcome label of an error.
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
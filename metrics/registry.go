@@ -0,0 +1,396 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+// Package metrics is a small registry of counters, gauges and
+// histograms that are exposed in the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefBuckets are the default histogram buckets in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Sample is the value of a metric for a combination of labels.
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds the metrics that are exposed together.
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]collector{}}
+}
+
+// DefaultRegistry is the registry that is served on /metrics.
+var DefaultRegistry = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.collectors[c.name()]; ok {
+		panic(fmt.Sprintf("metric %s is already registered", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// Write writes the metrics in the Prometheus text format,
+// sorted by name.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, len(names))
+	for i, name := range names {
+		collectors[i] = r.collectors[name]
+	}
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the metrics of the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// desc describes a metric and stores its values by label values.
+type desc struct {
+	metric string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d *desc) name() string {
+	return d.metric
+}
+
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric %s has labels %v, got values %v", d.metric, d.labels, values))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (d *desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metric, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metric, d.kind)
+}
+
+func (d *desc) writeSample(w *bufio.Writer, suffix string, values []string, extra string, v float64) {
+	w.WriteString(d.metric)
+	w.WriteString(suffix)
+	pairs := make([]string, 0, len(values)+1)
+	for i, l := range d.labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", l, escapeLabel(values[i])))
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) > 0 {
+		w.WriteString("{")
+		w.WriteString(strings.Join(pairs, ","))
+		w.WriteString("}")
+	}
+	w.WriteString(" ")
+	w.WriteString(formatFloat(v))
+	w.WriteString("\n")
+}
+
+// vec stores a float value for each combination of labels.
+type vec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*Sample
+}
+
+func newVec(metric, help, kind string, labels []string) vec {
+	return vec{
+		desc:   desc{metric: metric, help: help, kind: kind, labels: labels},
+		values: map[string]*Sample{},
+	}
+}
+
+func (v *vec) add(delta float64, values []string) {
+	k := v.key(values)
+	v.mu.Lock()
+	s, ok := v.values[k]
+	if !ok {
+		s = &Sample{Labels: append([]string(nil), values...)}
+		v.values[k] = s
+	}
+	s.Value += delta
+	v.mu.Unlock()
+}
+
+func (v *vec) set(value float64, values []string) {
+	k := v.key(values)
+	v.mu.Lock()
+	s, ok := v.values[k]
+	if !ok {
+		s = &Sample{Labels: append([]string(nil), values...)}
+		v.values[k] = s
+	}
+	s.Value = value
+	v.mu.Unlock()
+}
+
+// Get returns the value for the labels.
+func (v *vec) Get(values ...string) float64 {
+	k := v.key(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok := v.values[k]; ok {
+		return s.Value
+	}
+	return 0
+}
+
+// Values returns a copy of the values sorted by labels.
+func (v *vec) Values() []Sample {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]Sample, len(keys))
+	for i, k := range keys {
+		s := v.values[k]
+		out[i] = Sample{Labels: s.Labels, Value: s.Value}
+	}
+	v.mu.Unlock()
+	return out
+}
+
+func (v *vec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, s := range v.Values() {
+		v.writeSample(w, "", s.Labels, "", s.Value)
+	}
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	vec
+}
+
+// NewCounterVec registers a counter in the default registry.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return DefaultRegistry.NewCounterVec(name, help, labels...)
+}
+
+// NewCounterVec registers a counter in the registry.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{newVec(name, help, "counter", labels)}
+	r.register(c)
+	return c
+}
+
+// Inc increments the counter of the labels.
+func (c *CounterVec) Inc(values ...string) {
+	c.add(1, values)
+}
+
+// Add adds a non-negative value to the counter of the labels.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.metric))
+	}
+	c.add(delta, values)
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	vec
+}
+
+// NewGaugeVec registers a gauge in the default registry.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return DefaultRegistry.NewGaugeVec(name, help, labels...)
+}
+
+// NewGaugeVec registers a gauge in the registry.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{newVec(name, help, "gauge", labels)}
+	r.register(g)
+	return g
+}
+
+// Set sets the gauge of the labels.
+func (g *GaugeVec) Set(value float64, values ...string) {
+	g.set(value, values)
+}
+
+// Add adds a value to the gauge of the labels.
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	g.add(delta, values)
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogram
+}
+
+type histogram struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram in the default registry.
+// DefBuckets are used when buckets is nil.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return DefaultRegistry.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// NewHistogramVec registers a histogram in the registry.
+// DefBuckets are used when buckets is nil.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &HistogramVec{
+		desc:    desc{metric: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		values:  map[string]*histogram{},
+	}
+	r.register(h)
+	return h
+}
+
+// Observe records a value in the histogram of the labels.
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	k := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.values[k]
+	if !ok {
+		s = &histogram{
+			labels: append([]string(nil), values...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.values[k] = s
+	}
+	for i, b := range h.buckets {
+		if value <= b {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *HistogramVec) ObserveSince(start time.Time, values ...string) {
+	h.Observe(time.Since(start).Seconds(), values...)
+}
+
+// Count returns the number of observations of the labels.
+func (h *HistogramVec) Count(values ...string) uint64 {
+	k := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.values[k]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.writeHeader(w)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.values))
+	for k := range h.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.values[k]
+		for i, b := range h.buckets {
+			h.writeSample(w, "_bucket", s.labels, fmt.Sprintf("le=\"%s\"", formatFloat(b)), float64(s.counts[i]))
+		}
+		h.writeSample(w, "_bucket", s.labels, "le=\"+Inf\"", float64(s.count))
+		h.writeSample(w, "_sum", s.labels, "", s.sum)
+		h.writeSample(w, "_count", s.labels, "", float64(s.count))
+	}
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,104 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	hooks := r.NewCounterVec("test_hooks_total", "Incoming hooks.", "event", "outcome")
+	remaining := r.NewGaugeVec("test_remaining", "Remaining requests.\nPer user.", "user")
+	latency := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{1, 0.1})
+
+	hooks.Inc("status", "success")
+	hooks.Add(2, "status", "success")
+	hooks.Inc("issue_comment", "error")
+	remaining.Set(4999, `octo"cat`)
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(5)
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP test_hooks_total Incoming hooks.
+# TYPE test_hooks_total counter
+test_hooks_total{event="issue_comment",outcome="error"} 1
+test_hooks_total{event="status",outcome="success"} 3
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 5.55
+test_latency_seconds_count 3
+# HELP test_remaining Remaining requests.\nPer user.
+# TYPE test_remaining gauge
+test_remaining{user="octo\"cat"} 4999
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+	if v := hooks.Get("status", "success"); v != 3 {
+		t.Errorf("Expected 3, got %v", v)
+	}
+	if n := latency.Count(); n != 3 {
+		t.Errorf("Expected 3 observations, got %d", n)
+	}
+}
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("test_total", "Test.").Inc()
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("Unexpected content type %s", ct)
+	}
+	if w.Body.String() != "# HELP test_total Test.\n# TYPE test_total counter\ntest_total 1\n" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
+func TestDuplicateRegistration(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("test_total", "Test.")
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a duplicate metric")
+		}
+	}()
+	r.NewGaugeVec("test_total", "Test.")
+}
+
+func TestLabelMismatch(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_total", "Test.", "event")
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for missing label values")
+		}
+	}()
+	c.Inc()
+}
@@ -23,6 +23,8 @@ import (
 
 	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/set"
+	"github.com/capitalone/checks-out/shared/token"
 	"github.com/capitalone/checks-out/store"
 
 	log "github.com/Sirupsen/logrus"
@@ -40,6 +42,9 @@ func Migrate(r remote.Remote, s store.Store) error {
 	if ids.Contains("005_oauth_scope.sql") {
 		errs = multierror.Append(errs, insertDefaultScope(s))
 	}
+	if ids.Contains("017_hook_events.sql") {
+		errs = multierror.Append(errs, upgradeHooks(r, s))
+	}
 	return errs
 }
 
@@ -93,3 +98,62 @@ func insertRepoOrgs(r remote.Remote, s store.Store) error {
 	log.Infof("Applied 002_org.sql migrations to %d out of a total %d repos", count, len(repos))
 	return nil
 }
+
+func upgradeHooks(r remote.Remote, s store.Store) error {
+	log.Info("Applying 017_hook_events.sql migrations")
+	count := 0
+	repos, err := s.GetAllRepos()
+	if err != nil {
+		return err
+	}
+	owners := set.Empty()
+	for _, repo := range repos {
+		if repo.Org {
+			owners.Add(repo.Owner)
+		}
+		user, err := s.GetUser(repo.UserID)
+		if err != nil {
+			log.Warnf("Unable to retrieve user %s (id %d) for repo %s: %s",
+				repo.Owner, repo.UserID, repo.Slug, err)
+			continue
+		}
+		sig, err := token.New(token.HookToken, repo.Slug).Sign(repo.Secret)
+		if err != nil {
+			log.Warnf("Unable to sign the hook token of repo %s: %s", repo.Slug, err)
+			continue
+		}
+		err = r.UpgradeHook(context.Background(), user, repo, sig)
+		if err != nil {
+			log.Warnf("Unable to update the hook of repo %s: %s", repo.Slug, err)
+			continue
+		}
+		count++
+	}
+	log.Infof("Applied 017_hook_events.sql migrations to %d out of a total %d repos", count, len(repos))
+	count = 0
+	for owner := range owners {
+		org, err := s.GetOrgByName(owner)
+		if err != nil {
+			// organizations without an organization hook
+			continue
+		}
+		user, err := s.GetUser(org.UserID)
+		if err != nil {
+			log.Warnf("Unable to retrieve user (id %d) for org %s: %s", org.UserID, owner, err)
+			continue
+		}
+		sig, err := token.New(token.HookToken, org.Owner).Sign(org.Secret)
+		if err != nil {
+			log.Warnf("Unable to sign the hook token of org %s: %s", owner, err)
+			continue
+		}
+		err = r.UpgradeOrgHook(context.Background(), user, org, sig)
+		if err != nil {
+			log.Warnf("Unable to update the hook of org %s: %s", owner, err)
+			continue
+		}
+		count++
+	}
+	log.Infof("Applied 017_hook_events.sql migrations to %d out of a total %d orgs", count, len(owners))
+	return nil
+}
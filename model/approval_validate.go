@@ -66,6 +66,9 @@ func (a *ApprovalPolicy) Validate() error {
 	if a.Tag != nil {
 		errs = multierror.Append(errs, a.Tag.Compile())
 	}
+	if a.Merge != nil {
+		errs = multierror.Append(errs, a.Merge.Compile())
+	}
 	if len(a.Scope.Paths) > 0 && len(a.Scope.PathRegexp) > 0 {
 		err := errors.New("'paths' and 'regexpaths' cannot be used together")
 		errs = multierror.Append(errs, err)
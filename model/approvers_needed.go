@@ -0,0 +1,99 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"github.com/capitalone/checks-out/set"
+)
+
+// candidateMatcher is implemented by the matchers that
+// restrict approval to a known set of people.
+type candidateMatcher interface {
+	candidates(req *ApprovalRequest) (set.Set, error)
+}
+
+// NeededApprovers returns the people who can still satisfy
+// the approval policy. These are the candidates of the matchers
+// that have not been satisfied, excluding the author of the
+// pull request (unless self approval is allowed) and the
+// people who have already approved.
+func NeededApprovers(req *ApprovalRequest, policy *ApprovalPolicy) (set.Set, error) {
+	needed := set.Empty()
+	if req.Maintainer == nil || policy.Match.Matcher == nil {
+		return needed, nil
+	}
+	err := addNeededApprovers(req, policy.Match.Matcher, needed)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range req.ApprovalComments {
+		if f.IsApproval(req) {
+			needed.Remove(f.GetAuthor().String())
+		}
+	}
+	return needed, nil
+}
+
+func addNeededApprovers(req *ApprovalRequest, m Matcher, needed set.Set) error {
+	ok, err := m.Match(req, func(Feedback, ApprovalOp) {}, approvalAction, req.ApprovalComments)
+	if err != nil || ok {
+		return err
+	}
+	var children []MatcherHolder
+	switch match := m.(type) {
+	case *AndMatch:
+		children = match.And
+	case *OrMatch:
+		children = match.Or
+	case *AtLeastMatch:
+		children = match.Choose
+	case candidateMatcher:
+		people, err := match.candidates(req)
+		if err != nil {
+			return err
+		}
+		if !allowSelf(m) {
+			people.Remove(req.PullRequest.Author.String())
+		}
+		needed.AddAll(people)
+	}
+	for _, child := range children {
+		err := addNeededApprovers(req, child.Matcher, needed)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func allowSelf(m Matcher) bool {
+	switch match := m.(type) {
+	case *MaintainerMatch:
+		return match.Self
+	case *AnonymousMatch:
+		return match.Self
+	case *EntityMatch:
+		return match.Self
+	case *UsMatch:
+		return match.Self
+	case *ThemMatch:
+		return match.Self
+	}
+	return false
+}
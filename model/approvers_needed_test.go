@@ -0,0 +1,85 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/capitalone/checks-out/set"
+	"github.com/capitalone/checks-out/strings/lowercase"
+)
+
+func entityMatch(name string, approvals int) MatcherHolder {
+	match := &EntityMatch{Entity: lowercase.Create(name)}
+	match.Approvals = approvals
+	return MatcherHolder{match}
+}
+
+func TestNeededApprovers(t *testing.T) {
+	request := createRequest()
+	request.ApprovalComments = []Feedback{
+		&Comment{Author: lowercase.Create("bob"), Body: "I approve"},
+	}
+	request.Config.Approvals[0].Match = MatcherHolder{&AndMatch{
+		And: []MatcherHolder{entityMatch("guelph", 1), entityMatch("ghibelline", 1)},
+	}}
+	policy := FindApprovalPolicy(request)
+	needed, err := NeededApprovers(request, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := set.New("carol", "dan")
+	if !reflect.DeepEqual(needed, expected) {
+		t.Errorf("Expected %v but was %v", expected, needed)
+	}
+}
+
+func TestNeededApproversSatisfied(t *testing.T) {
+	request := createRequest()
+	request.ApprovalComments = []Feedback{
+		&Comment{Author: lowercase.Create("bob"), Body: "I approve"},
+	}
+	request.Config.Approvals[0].Match = MatcherHolder{&OrMatch{
+		Or: []MatcherHolder{entityMatch("guelph", 1), entityMatch("ghibelline", 1)},
+	}}
+	policy := FindApprovalPolicy(request)
+	needed, err := NeededApprovers(request, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(needed) != 0 {
+		t.Errorf("Expected no needed approvers but was %v", needed)
+	}
+}
+
+func TestNeededApproversSelf(t *testing.T) {
+	request := createRequest()
+	request.ApprovalComments = nil
+	request.Config.Approvals[0].Match = entityMatch("guelph", 2)
+	policy := FindApprovalPolicy(request)
+	needed, err := NeededApprovers(request, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := set.New("bob")
+	if !reflect.DeepEqual(needed, expected) {
+		t.Errorf("Expected %v but was %v", expected, needed)
+	}
+}
@@ -22,6 +22,7 @@ type PullRequest struct {
 	Issue
 	Branch Branch
 	Body   string
+	Labels []string
 }
 
 type Branch struct {
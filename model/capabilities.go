@@ -32,23 +32,35 @@ type Capabilities struct {
 	}
 	Repo struct {
 		Tag              bool
+		SignTag          bool
+		Release          bool
 		Merge            bool
 		DeleteBranch     bool
 		CommitStatus     bool
 		PRWriteComment   bool
 		DeploymentStatus bool
 	}
+	Notify struct {
+		Teams   bool
+		Webhook bool
+		Email   bool
+	}
 }
 
 func AllowAll() *Capabilities {
 	caps := new(Capabilities)
 	caps.Org.Read = true
 	caps.Repo.Tag = true
+	caps.Repo.SignTag = true
+	caps.Repo.Release = true
 	caps.Repo.Merge = true
 	caps.Repo.DeleteBranch = true
 	caps.Repo.CommitStatus = true
 	caps.Repo.PRWriteComment = true
 	caps.Repo.DeploymentStatus = true
+	caps.Notify.Teams = true
+	caps.Notify.Webhook = true
+	caps.Notify.Email = true
 	return caps
 }
 
@@ -61,6 +73,12 @@ func validateCapabilities(c *Config, caps *Capabilities) error {
 	if c.Tag.Enable && !caps.Repo.Tag {
 		errMsgs.Add("unable to git tag with provided OAuth scopes")
 	}
+	if c.Tag.Enable && c.Tag.Sign && !caps.Repo.SignTag {
+		errMsgs.Add("unable to sign git tag without a server signing key")
+	}
+	if c.Tag.Enable && c.Tag.Release.Enable && !caps.Repo.Release {
+		errMsgs.Add("unable to create release with provided OAuth scopes")
+	}
 	if c.Merge.Enable && !caps.Repo.Merge {
 		errMsgs.Add("unable to git merge with provided OAuth scopes")
 	}
@@ -71,6 +89,12 @@ func validateCapabilities(c *Config, caps *Capabilities) error {
 		if policy.Tag != nil && policy.Tag.Enable && !caps.Repo.Tag {
 			errMsgs.Add("unable to git tag with provided OAuth scopes")
 		}
+		if policy.Tag != nil && policy.Tag.Enable && policy.Tag.Sign && !caps.Repo.SignTag {
+			errMsgs.Add("unable to sign git tag without a server signing key")
+		}
+		if policy.Tag != nil && policy.Tag.Enable && policy.Tag.Release.Enable && !caps.Repo.Release {
+			errMsgs.Add("unable to create release with provided OAuth scopes")
+		}
 		if policy.Merge != nil && policy.Merge.Enable && !caps.Repo.Merge {
 			errMsgs.Add("unable to git merge with provided OAuth scopes")
 		}
@@ -83,6 +107,15 @@ func validateCapabilities(c *Config, caps *Capabilities) error {
 			if target.Target == Github.String() && !caps.Repo.PRWriteComment {
 				errMsgs.Add("unable to add PR comment with provided OAuth scopes")
 			}
+			if target.Target == Teams.String() && target.Url == "" && !caps.Notify.Teams {
+				errMsgs.Add("unable to send Teams messages without a server webhook url")
+			}
+			if target.Target == Webhook.String() && target.Url == "" && !caps.Notify.Webhook {
+				errMsgs.Add("unable to send webhook events without a server webhook url and secret")
+			}
+			if target.Target == Email.String() && !caps.Notify.Email {
+				errMsgs.Add("unable to send email without a server SMTP configuration")
+			}
 		}
 	}
 	for msg := range errMsgs {
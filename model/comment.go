@@ -29,6 +29,10 @@ import (
 
 var CommentPrefix = fmt.Sprintf("Message from %s --", envvars.Env.Branding.ShortName)
 
+// StickyMarker identifies the sticky status comment of a pull request.
+// It is hidden when the comment is rendered.
+var StickyMarker = fmt.Sprintf("<!-- %s:sticky -->", envvars.Env.Branding.ShortName)
+
 type Comment struct {
 	Author      lowercase.String
 	Body        string
@@ -21,6 +21,9 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"net/mail"
+
+	"github.com/mspiegel/go-multierror"
 )
 
 type CommentMessage int
@@ -131,18 +134,27 @@ const (
 	_ CommentTarget = iota
 	Github
 	Slack
+	Teams
+	Webhook
+	Email
 )
 
 // CommentTarget enum maps.
 var (
 	strMapCommentTarget = map[string]CommentTarget{
-		"github": Github,
-		"slack":  Slack,
+		"github":  Github,
+		"slack":   Slack,
+		"teams":   Teams,
+		"webhook": Webhook,
+		"email":   Email,
 	}
 
 	intMapCommentTarget = map[CommentTarget]string{
-		Github: "github",
-		Slack:  "slack",
+		Github:  "github",
+		Slack:   "slack",
+		Teams:   "teams",
+		Webhook: "webhook",
+		Email:   "email",
 	}
 )
 
@@ -184,3 +196,36 @@ func (s CommentTarget) MarshalJSON() ([]byte, error) {
 	name := intMapCommentTarget[s]
 	return json.Marshal(name)
 }
+
+// ApproversNeeded is the name of the email recipient that is
+// replaced by the people who can still approve the pull request.
+const ApproversNeeded = "approvers-needed"
+
+// Validate checks the names of the comment targets.
+func (c *CommentConfig) Validate() error {
+	var errs error
+	for _, target := range c.Targets {
+		if err := validateMessageTemplates(target); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		if target.Sticky && target.Target != Github.String() {
+			errs = multierror.Append(errs, fmt.Errorf("Sticky comments are not supported by the %s target", target.Target))
+		}
+		// slack targets may be named by the hostname of the webhook
+		if t := ToCommentTarget(target.Target); target.Direct && t.Known() && t != Slack {
+			errs = multierror.Append(errs, fmt.Errorf("Direct messages are not supported by the %s target", target.Target))
+		}
+		if target.Target != Email.String() {
+			continue
+		}
+		for _, name := range target.Names {
+			if name == ApproversNeeded {
+				continue
+			}
+			if _, err := mail.ParseAddress(name); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("Invalid email address %s: %v", name, err))
+			}
+		}
+	}
+	return errs
+}
@@ -20,6 +20,7 @@ package model
 
 import (
 	"regexp"
+	"text/template"
 
 	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/hjson"
@@ -61,6 +62,11 @@ type MergeConfig struct {
 	UpToDate bool   `json:"uptodate"`
 	Method   string `json:"method"`
 	Delete   bool   `json:"delete"`
+	// golang text/template for producing the merge commit message.
+	// Container struct is TemplateMerge. If empty then the
+	// message lists the approval comment and the approvers.
+	TemplateRaw string             `json:"template,omitempty"`
+	Template    *template.Template `json:"-"`
 }
 
 type FeedbackConfig struct {
@@ -78,13 +84,30 @@ type TargetConfig struct {
 	Pattern *rxserde.RegexSerde `json:"pattern"`
 	Types   []CommentMessage    `json:"types"`
 	Names   []string            `json:"names"`
-	Url     string              `json:"-"`
+	Sticky  bool                `json:"sticky"`
+	Direct  bool                `json:"direct"`
+	// Templates maps message types to text/template
+	// strings that replace the default messages.
+	Templates map[string]string `json:"templates"`
+	Url       string            `json:"-"`
+	Secret    string            `json:"-"`
+	// Host and User identify the registered webhook target
+	// whose Url and Secret are used. Host is empty for the
+	// server default webhook.
+	Host string `json:"-"`
+	User string `json:"-"`
 }
 
 type DeployConfig struct {
-	Enable        bool              `json:"enable"`
-	Path          string            `json:"path"`
-	DeploymentMap DeploymentConfigs `json:"-"`
+	Enable bool   `json:"enable"`
+	Path   string `json:"path"`
+	// Approvals maps an environment to the approval
+	// match that must succeed before deployment.
+	Approvals map[string]MatcherHolder `json:"approvals,omitempty"`
+	// Pattern matches the comments that approve a deployment.
+	// The "environment" capture group names the environment.
+	Pattern       *rxserde.RegexSerde `json:"pattern,omitempty"`
+	DeploymentMap DeploymentConfigs   `json:"-"`
 }
 
 const (
@@ -106,6 +129,7 @@ func DefaultConfig() *Config {
 	c.Tag = DefaultTag()
 	c.Audit = DefaultAudit()
 	_ = c.Tag.Compile()
+	_ = c.Merge.Compile()
 	return c
 }
 
@@ -121,6 +145,8 @@ func (c *Config) Validate(caps *Capabilities) error {
 	errs = multierror.Append(errs, validateCapabilities(c, caps))
 	errs = multierror.Append(errs, validateApprovals(c.Approvals))
 	errs = multierror.Append(errs, validateMaintainerConfig(&c.Maintainers))
+	errs = multierror.Append(errs, c.Deployment.Validate())
+	errs = multierror.Append(errs, c.Comment.Validate())
 	return errs
 }
 
@@ -147,6 +173,7 @@ func ParseConfig(data []byte, caps *Capabilities) (*Config, error) {
 	}
 	var errs error
 	errs = multierror.Append(errs, c.Tag.Compile())
+	errs = multierror.Append(errs, c.Merge.Compile())
 	if errs != nil {
 		return nil, errs
 	}
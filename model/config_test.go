@@ -256,3 +256,62 @@ func TestOldConfigConvert(t *testing.T) {
 		t.Fatalf("Expected\n%v\ngot\n%v\n", expected, string(out))
 	}
 }
+
+func TestCommentValidateEmail(t *testing.T) {
+	c := CommentConfig{
+		Enable: true,
+		Targets: []TargetConfig{
+			{
+				Target: "email",
+				Names:  []string{"alice@example.com", "Bob <bob@example.com>", ApproversNeeded},
+			},
+			{
+				Target: "slack",
+				Names:  []string{"#channel"},
+			},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	c.Targets[0].Names = append(c.Targets[0].Names, "not an address")
+	if err := c.Validate(); err == nil {
+		t.Error("Expected error for invalid email address")
+	}
+}
+
+func TestCommentValidateSticky(t *testing.T) {
+	c := CommentConfig{
+		Enable: true,
+		Targets: []TargetConfig{
+			{
+				Target: "github",
+				Sticky: true,
+			},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	c.Targets = append(c.Targets, TargetConfig{Target: "slack", Sticky: true})
+	if err := c.Validate(); err == nil {
+		t.Error("Expected error for sticky slack target")
+	}
+}
+
+func TestCommentValidateDirect(t *testing.T) {
+	c := CommentConfig{
+		Enable: true,
+		Targets: []TargetConfig{
+			{Target: "slack", Direct: true},
+			{Target: "foobar.slack.com", Direct: true},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	c.Targets = append(c.Targets, TargetConfig{Target: "teams", Direct: true})
+	if err := c.Validate(); err == nil {
+		t.Error("Expected error for direct teams target")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+var conventionalRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*\S`)
+
+// ConventionalCommit is the header of a commit message
+// or pull request title that follows the conventional
+// commits specification https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	// Type is the lowercase type such as "feat" or "fix"
+	Type string
+	// Scope is the optional scope inside parentheses
+	Scope string
+	// Breaking is true if the header has a "!" before the colon
+	// or the message has a "BREAKING CHANGE:" footer
+	Breaking bool
+}
+
+var breakingRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+
+// ParseConventional parses the conventional commit header
+// of the message. Returns false if the message does not
+// follow the specification.
+func ParseConventional(message string) (ConventionalCommit, bool) {
+	match := conventionalRegex.FindStringSubmatch(message)
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	result := ConventionalCommit{
+		Type:     strings.ToLower(match[1]),
+		Scope:    match[2],
+		Breaking: len(match[3]) > 0 || breakingRegex.MatchString(message),
+	}
+	return result, true
+}
+
+// Increment returns the semver increment requested by the
+// conventional commit. Breaking changes are a major increment,
+// features are a minor increment, and fixes are a patch increment.
+// Returns false for all other types.
+func (cc ConventionalCommit) Increment() (Semver, bool) {
+	switch {
+	case cc.Breaking:
+		return Major, true
+	case cc.Type == "feat":
+		return Minor, true
+	case cc.Type == "fix":
+		return Patch, true
+	}
+	return None, false
+}
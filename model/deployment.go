@@ -19,22 +19,75 @@ See the License for the specific language governing permissions and limitations
 package model
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/capitalone/checks-out/hjson"
+
+	multierror "github.com/mspiegel/go-multierror"
 )
 
 type DeploymentConfigs map[string]DeploymentConfig
 
+// DeploymentConfig describes the deployments that are created
+// when a pull request is merged into a branch or a tag is placed
+// on the merge. The key of the deployment map is a branch pattern
+// or a tag pattern with the prefix "refs/tags/".
 type DeploymentConfig struct {
-	Tasks       []string `json:"tasks"`
-	Environment *string  `json:"env"`
+	Tasks            []string          `json:"tasks"`
+	Environment      *string           `json:"env"`
+	Payload          json.RawMessage   `json:"payload,omitempty"`
+	RequiredContexts *[]string         `json:"required_contexts,omitempty"`
+	Promote          *DeploymentConfig `json:"promote,omitempty"`
 }
 
 type DeploymentInfo struct {
+	Ref              string
+	Task             string
+	Environment      string
+	Payload          string
+	RequiredContexts *[]string
+	PullRequest      int
+}
+
+// DeploymentEvent is a deployment that has reached a final state.
+type DeploymentEvent struct {
+	SHA         string
 	Ref         string
 	Task        string
 	Environment string
+	State       string
+	PullRequest int
+}
+
+const (
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+)
+
+var deploymentDescription = regexp.MustCompile(`^Pull request #(\d+)$`)
+
+// DeploymentDescription records the pull request
+// that is the origin of a deployment.
+func DeploymentDescription(pr int) string {
+	return fmt.Sprintf("Pull request #%d", pr)
+}
+
+// ParseDeploymentDescription returns the pull request that is the
+// origin of a deployment or zero if the pull request is unknown.
+func ParseDeploymentDescription(desc string) int {
+	match := deploymentDescription.FindStringSubmatch(desc)
+	if match == nil {
+		return 0
+	}
+	pr, _ := strconv.Atoi(match[1])
+	return pr
 }
 
 func (c *Config) LoadDeploymentMap(deployData []byte) error {
@@ -42,5 +95,145 @@ func (c *Config) LoadDeploymentMap(deployData []byte) error {
 		return errors.New("No content in deployment map")
 	}
 	err := hjson.Unmarshal(deployData, &c.Deployment.DeploymentMap)
-	return err
+	if err != nil {
+		return err
+	}
+	return c.Deployment.DeploymentMap.Validate()
+}
+
+func (d DeploymentConfigs) Validate() error {
+	var errs error
+	for _, key := range d.keys() {
+		_, pattern := parseDeploymentKey(key)
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Deployment pattern %s is invalid: %s", key, err))
+		}
+		dc := d[key]
+		envs := map[string]bool{}
+		for link := &dc; link != nil; link = link.Promote {
+			env := link.env()
+			if link.Promote != nil && len(env) == 0 {
+				errs = multierror.Append(errs, fmt.Errorf("Deployment %s must specify the env of each promoted deployment", key))
+				break
+			}
+			if envs[env] {
+				errs = multierror.Append(errs, fmt.Errorf("Deployment %s promotes to environment %s more than once", key, env))
+				break
+			}
+			envs[env] = true
+		}
+	}
+	return errs
+}
+
+// Deployments returns the deployments whose pattern matches
+// the branch or the tag. The tag may be empty.
+func (d DeploymentConfigs) Deployments(branch string, tag string) []DeploymentInfo {
+	var result []DeploymentInfo
+	for _, key := range d.keys() {
+		isTag, pattern := parseDeploymentKey(key)
+		name := branch
+		if isTag {
+			name = tag
+		}
+		if len(name) == 0 {
+			continue
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			dc := d[key]
+			result = append(result, dc.deployments(name)...)
+		}
+	}
+	return result
+}
+
+// Promotions returns the deployments that are scheduled
+// after the successful completion of a deployment. The promoted
+// deployments use the SHA of the completed deployment so that
+// the same commit advances through each environment. A deployment
+// of a SHA was created by an earlier promotion and is matched
+// against every promotion chain.
+func (d DeploymentConfigs) Promotions(event DeploymentEvent) []DeploymentInfo {
+	var result []DeploymentInfo
+	if event.State != "success" {
+		return result
+	}
+	seen := map[DeploymentEvent]bool{}
+	for _, key := range d.keys() {
+		_, pattern := parseDeploymentKey(key)
+		ok, _ := path.Match(pattern, event.Ref)
+		if !ok && event.Ref != event.SHA {
+			continue
+		}
+		dc := d[key]
+		for link := &dc; link.Promote != nil; link = link.Promote {
+			if link.env() != event.Environment || !link.triggers(event.Task) {
+				continue
+			}
+			for _, di := range link.Promote.deployments(event.SHA) {
+				di.PullRequest = event.PullRequest
+				id := DeploymentEvent{Ref: di.Ref, Task: di.Task, Environment: di.Environment}
+				if !seen[id] {
+					seen[id] = true
+					result = append(result, di)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func (d DeploymentConfigs) keys() []string {
+	var keys []string
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func parseDeploymentKey(key string) (bool, string) {
+	if strings.HasPrefix(key, tagRefPrefix) {
+		return true, strings.TrimPrefix(key, tagRefPrefix)
+	}
+	return false, strings.TrimPrefix(key, branchRefPrefix)
+}
+
+func (dc *DeploymentConfig) env() string {
+	if dc.Environment == nil {
+		return ""
+	}
+	return *dc.Environment
+}
+
+// triggers returns true when the completion of the task
+// should promote the deployment. With multiple tasks
+// the first task controls the promotion.
+func (dc *DeploymentConfig) triggers(task string) bool {
+	if len(dc.Tasks) == 0 {
+		return true
+	}
+	return dc.Tasks[0] == task
+}
+
+func (dc *DeploymentConfig) deployments(ref string) []DeploymentInfo {
+	var result []DeploymentInfo
+	env := dc.env()
+	di := DeploymentInfo{
+		Ref:              ref,
+		Environment:      env,
+		Payload:          string(dc.Payload),
+		RequiredContexts: dc.RequiredContexts,
+	}
+	if len(dc.Tasks) == 0 {
+		if len(env) > 0 {
+			result = append(result, di)
+		}
+		return result
+	}
+	for _, task := range dc.Tasks {
+		di.Task = task
+		result = append(result, di)
+	}
+	return result
 }
@@ -0,0 +1,206 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	multierror "github.com/mspiegel/go-multierror"
+)
+
+var defaultDeployPattern = regexp.MustCompile(`(?i)\bapprove deploy(?:ment)? to (?P<environment>[\w.-]+)`)
+
+// PendingDeployment is a deployment that is waiting
+// for approval before it is sent to the remote system.
+type PendingDeployment struct {
+	ID               int64     `json:"id"                meddler:"pending_id,pk"`
+	RepoID           int64     `json:"-"                 meddler:"pending_repo_id"`
+	PullRequest      int       `json:"pull_request"      meddler:"pending_pr"`
+	Ref              string    `json:"ref"               meddler:"pending_ref"`
+	Task             string    `json:"task,omitempty"    meddler:"pending_task"`
+	Environment      string    `json:"env"               meddler:"pending_env"`
+	Payload          string    `json:"payload,omitempty" meddler:"pending_payload"`
+	RequiredContexts *[]string `json:"required_contexts,omitempty" meddler:"pending_contexts,json"`
+	Created          int64     `json:"created"           meddler:"pending_created"`
+}
+
+// DeploymentApproval is an approval of the deployments
+// of a pull request to an environment that was submitted
+// through the API.
+type DeploymentApproval struct {
+	ID          int64  `json:"id"           meddler:"approval_id,pk"`
+	RepoID      int64  `json:"-"            meddler:"approval_repo_id"`
+	PullRequest int    `json:"pull_request" meddler:"approval_pr"`
+	Environment string `json:"env"          meddler:"approval_env"`
+	Login       string `json:"login"        meddler:"approval_login"`
+	Created     int64  `json:"created"      meddler:"approval_created"`
+}
+
+func NewPendingDeployment(repoID int64, di DeploymentInfo, created int64) *PendingDeployment {
+	return &PendingDeployment{
+		RepoID:           repoID,
+		PullRequest:      di.PullRequest,
+		Ref:              di.Ref,
+		Task:             di.Task,
+		Environment:      di.Environment,
+		Payload:          di.Payload,
+		RequiredContexts: di.RequiredContexts,
+		Created:          created,
+	}
+}
+
+func (p *PendingDeployment) DeploymentInfo() DeploymentInfo {
+	return DeploymentInfo{
+		Ref:              p.Ref,
+		Task:             p.Task,
+		Environment:      p.Environment,
+		Payload:          p.Payload,
+		RequiredContexts: p.RequiredContexts,
+		PullRequest:      p.PullRequest,
+	}
+}
+
+// deployApproval is feedback that has been
+// accepted by the deployment approval pattern.
+type deployApproval struct {
+	Feedback
+}
+
+func (d deployApproval) IsApproval(req *ApprovalRequest) bool {
+	return true
+}
+
+func (d deployApproval) IsDisapproval(req *ApprovalRequest) bool {
+	return false
+}
+
+func (d *DeployConfig) Validate() error {
+	var errs error
+	envs := map[string]bool{}
+	for env := range d.Approvals {
+		if len(strings.TrimSpace(env)) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("Deployment approval environment must not be empty"))
+		}
+		key := NormalizeEnvironment(env)
+		if envs[key] {
+			errs = multierror.Append(errs, fmt.Errorf("Deployment approval environment %s is specified more than once", env))
+		}
+		envs[key] = true
+	}
+	if d.Pattern != nil && d.Pattern.Regex != nil {
+		if getSubexpIndex(d.Pattern.Regex, "environment") == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("Deployment approval pattern %s is missing the 'environment' capture group",
+				d.Pattern.Regex.String()))
+		}
+	}
+	return errs
+}
+
+// RequiresApproval returns true when deployments to
+// the environment must be approved.
+func (d *DeployConfig) RequiresApproval(env string) bool {
+	_, ok := d.approvalMatch(env)
+	return ok
+}
+
+// NormalizeEnvironment returns the form of the environment
+// name that is used to compare and store approvals. Environment
+// names are case-insensitive.
+func NormalizeEnvironment(env string) string {
+	return strings.ToLower(env)
+}
+
+func (d *DeployConfig) approvalMatch(env string) (MatcherHolder, bool) {
+	for name, matcher := range d.Approvals {
+		if NormalizeEnvironment(name) == NormalizeEnvironment(env) {
+			return matcher, true
+		}
+	}
+	return MatcherHolder{}, false
+}
+
+// ApprovalEnvironment returns the environment that is
+// approved by the comment or the empty string.
+func (d *DeployConfig) ApprovalEnvironment(body string) string {
+	regex := defaultDeployPattern
+	if d.Pattern != nil && d.Pattern.Regex != nil {
+		regex = d.Pattern.Regex
+	}
+	match := regex.FindStringSubmatch(body)
+	idx := getSubexpIndex(regex, "environment")
+	if match == nil || idx == 0 {
+		return ""
+	}
+	return match[idx]
+}
+
+// DeploymentFeedback selects the comments that approve
+// deployment to the environment.
+func (d *DeployConfig) DeploymentFeedback(comments []*Comment, env string) []Feedback {
+	var feedback []Feedback
+	for _, c := range comments {
+		if strings.HasPrefix(c.Body, CommentPrefix) {
+			continue
+		}
+		if NormalizeEnvironment(d.ApprovalEnvironment(c.Body)) == NormalizeEnvironment(env) {
+			feedback = append(feedback, c)
+		}
+	}
+	return feedback
+}
+
+// ApprovalFeedback converts the approvals submitted
+// through the API into feedback.
+func ApprovalFeedback(approvals []*DeploymentApproval) []Feedback {
+	var feedback []Feedback
+	for _, a := range approvals {
+		feedback = append(feedback, &Comment{Author: lowercase.Create(a.Login)})
+	}
+	return feedback
+}
+
+// ApproveDeployment applies the approval match of the
+// environment to the deployment feedback.
+func (d *DeployConfig) ApproveDeployment(req *ApprovalRequest, env string, feedback []Feedback, p Processor) (bool, error) {
+	matcher, ok := d.approvalMatch(env)
+	if !ok {
+		return true, nil
+	}
+	if matcher.Matcher == nil {
+		return false, fmt.Errorf("Missing deployment approval match for environment %s", env)
+	}
+	approvals := make([]Feedback, len(feedback))
+	for i, f := range feedback {
+		approvals[i] = deployApproval{f}
+	}
+	return matcher.Match(req, p, approvalAction, approvals)
+}
+
+func getSubexpIndex(re *regexp.Regexp, name string) int {
+	for i, n := range re.SubexpNames() {
+		if n == name {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/capitalone/checks-out/strings/lowercase"
+)
+
+func TestDeploymentApprovalConfig(t *testing.T) {
+	input := []byte(`{
+		approvals: [ { match: "universe[count=1]" } ]
+		deploy: {
+			enable: true
+			approvals: {
+				production: "universe[count=2]"
+			}
+		}
+	}`)
+	config, err := ParseConfig(input, AllowAll())
+	if err != nil {
+		t.Fatal(err)
+	}
+	deploy := &config.Deployment
+	if !deploy.RequiresApproval("production") || deploy.RequiresApproval("staging") {
+		t.Error("Only production deployments should require approval")
+	}
+	if !deploy.RequiresApproval("Production") {
+		t.Error("Environment names should be case-insensitive")
+	}
+	input = []byte(`{
+		approvals: [ { match: "universe[count=1]" } ]
+		deploy: {
+			approvals: {
+				production: "universe[count=2]"
+			}
+			pattern: "(?i)ship it"
+		}
+	}`)
+	_, err = ParseConfig(input, AllowAll())
+	if err == nil {
+		t.Error("Pattern without environment capture group should not pass validation")
+	} else if !strings.Contains(err.Error(), "environment") {
+		t.Error(err)
+	}
+}
+
+func TestDeploymentApprovalMatch(t *testing.T) {
+	config := NonEmptyConfig()
+	universe := UniverseMatch{}
+	universe.Approvals = 2
+	config.Deployment.Approvals = map[string]MatcherHolder{
+		"production": {Matcher: &universe},
+	}
+	deploy := &config.Deployment
+	if deploy.ApprovalEnvironment("I approve deploy to production!") != "production" {
+		t.Error("Default pattern should capture the environment")
+	}
+	if deploy.ApprovalEnvironment("I approve") != "" {
+		t.Error("Merge approval should not approve deployment")
+	}
+	comments := []*Comment{
+		{Author: lowercase.Create("alice"), Body: "approve deployment to production"},
+		{Author: lowercase.Create("bob"), Body: "approve deployment to staging"},
+		{Author: lowercase.Create("carol"), Body: CommentPrefix + " approve deployment to production"},
+		{Author: lowercase.Create("author"), Body: "approve deployment to production"},
+	}
+	feedback := deploy.DeploymentFeedback(comments, "production")
+	if len(feedback) != 2 {
+		t.Fatalf("Expected 2 approvals of production, found %d", len(feedback))
+	}
+	req := &ApprovalRequest{
+		Config:      config,
+		Maintainer:  &MaintainerSnapshot{},
+		PullRequest: &PullRequest{Issue: Issue{Author: lowercase.Create("author")}},
+	}
+	noop := func(Feedback, ApprovalOp) {}
+	approved, err := deploy.ApproveDeployment(req, "production", feedback, noop)
+	if err != nil || approved {
+		t.Error("Author should not approve their own deployment", err)
+	}
+	feedback = append(feedback, ApprovalFeedback([]*DeploymentApproval{{Login: "dan"}})...)
+	approved, err = deploy.ApproveDeployment(req, "production", feedback, noop)
+	if err != nil || !approved {
+		t.Error("Comment and API approvals should approve deployment", err)
+	}
+	approved, err = deploy.ApproveDeployment(req, "Production", feedback, noop)
+	if err != nil || !approved {
+		t.Error("Environment names should be case-insensitive", err)
+	}
+	if len(deploy.DeploymentFeedback(comments, "PRODUCTION")) != 2 {
+		t.Error("Comment approvals should match the environment case-insensitively")
+	}
+	approved, err = deploy.ApproveDeployment(req, "staging", nil, noop)
+	if err != nil || !approved {
+		t.Error("Environment without approval match should be approved", err)
+	}
+}
@@ -99,3 +99,110 @@ func TestDeploymentHJSON(t *testing.T) {
 		t.Fatalf("should have had 1 entry in tasks, had %d", len(preprod2.Tasks))
 	}
 }
+
+var promoteFile = `
+{
+  master:
+  {
+    env: staging
+    tasks: [ "deploy" ]
+    payload: { replicas: 2 }
+    required_contexts: [ "ci/build" ]
+    promote:
+    {
+      env: production
+      required_contexts: []
+    }
+  }
+  "release/*":
+  {
+    env: qa
+  }
+  "refs/tags/v*":
+  {
+    env: production
+  }
+}
+`
+
+func TestDeploymentPatterns(t *testing.T) {
+	c := DefaultConfig()
+	err := c.LoadDeploymentMap([]byte(promoteFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := c.Deployment.DeploymentMap
+	deployments := d.Deployments("master", "v1.0.0")
+	if len(deployments) != 2 {
+		t.Fatalf("Should have 2 deployments, had %d", len(deployments))
+	}
+	staging := deployments[0]
+	if staging.Ref != "master" || staging.Task != "deploy" || staging.Environment != "staging" {
+		t.Errorf("Unexpected branch deployment %v", staging)
+	}
+	if staging.Payload != `{"replicas":2}` {
+		t.Errorf("Unexpected payload %s", staging.Payload)
+	}
+	if staging.RequiredContexts == nil || len(*staging.RequiredContexts) != 1 {
+		t.Errorf("Unexpected required contexts %v", staging.RequiredContexts)
+	}
+	if deployments[1].Ref != "v1.0.0" || deployments[1].Environment != "production" {
+		t.Errorf("Unexpected tag deployment %v", deployments[1])
+	}
+	deployments = d.Deployments("release/1.0", "")
+	if len(deployments) != 1 || deployments[0].Environment != "qa" {
+		t.Errorf("Unexpected release deployments %v", deployments)
+	}
+	if len(d.Deployments("develop", "1.0.0")) != 0 {
+		t.Error("Should not match develop branch or 1.0.0 tag")
+	}
+}
+
+func TestDeploymentPromotions(t *testing.T) {
+	c := DefaultConfig()
+	err := c.LoadDeploymentMap([]byte(promoteFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := c.Deployment.DeploymentMap
+	event := DeploymentEvent{
+		SHA:         "abc",
+		Ref:         "master",
+		Task:        "deploy",
+		Environment: "staging",
+		State:       "success",
+	}
+	promotions := d.Promotions(event)
+	if len(promotions) != 1 {
+		t.Fatalf("Should have 1 promotion, had %d", len(promotions))
+	}
+	prod := promotions[0]
+	if prod.Ref != "abc" || prod.Environment != "production" || prod.Task != "" {
+		t.Errorf("Unexpected promotion %v", prod)
+	}
+	if prod.RequiredContexts == nil || len(*prod.RequiredContexts) != 0 {
+		t.Errorf("Unexpected required contexts %v", prod.RequiredContexts)
+	}
+	event.State = "failure"
+	if len(d.Promotions(event)) != 0 {
+		t.Error("Failed deployments should not be promoted")
+	}
+	event.State = "success"
+	event.Environment = "production"
+	if len(d.Promotions(event)) != 0 {
+		t.Error("End of the promotion chain should not be promoted")
+	}
+}
+
+func TestDeploymentValidate(t *testing.T) {
+	c := DefaultConfig()
+	if c.LoadDeploymentMap([]byte(`{ "[": { env: qa } }`)) == nil {
+		t.Error("Invalid pattern should not pass validation")
+	}
+	if c.LoadDeploymentMap([]byte(`{ master: { tasks: ["t1"], promote: { env: qa } } }`)) == nil {
+		t.Error("Promotion without environment should not pass validation")
+	}
+	if c.LoadDeploymentMap([]byte(`{ master: { env: qa, promote: { env: qa } } }`)) == nil {
+		t.Error("Repeated environment should not pass validation")
+	}
+}
@@ -0,0 +1,46 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+// HookDelivery is an incoming GitHub webhook that is archived for
+// debugging and replay. The payload and headers are redacted.
+type HookDelivery struct {
+	ID          int64  `json:"id"                meddler:"hook_delivery_id,pk"`
+	Delivery    string `json:"delivery"          meddler:"hook_delivery_guid"`
+	Event       string `json:"event"             meddler:"hook_delivery_event"`
+	Action      string `json:"action,omitempty"  meddler:"hook_delivery_action"`
+	Repo        string `json:"repo,omitempty"    meddler:"hook_delivery_repo"`
+	PullRequest int    `json:"pr,omitempty"      meddler:"hook_delivery_pr"`
+	BaseURL     string `json:"-"                 meddler:"hook_delivery_base_url"`
+	Headers     string `json:"headers,omitempty" meddler:"hook_delivery_headers"`
+	Payload     string `json:"payload,omitempty" meddler:"hook_delivery_payload"`
+	Result      string `json:"result"            meddler:"hook_delivery_result"`
+	Error       string `json:"error"             meddler:"hook_delivery_error"`
+	Created     int64  `json:"created"           meddler:"hook_delivery_created"`
+	Processed   int64  `json:"processed"         meddler:"hook_delivery_processed"`
+}
+
+// HookDeliveryFilter selects archived webhooks. Empty fields
+// match every delivery.
+type HookDeliveryFilter struct {
+	Repo        string
+	PullRequest int
+	Event       string
+	Limit       int
+}
@@ -0,0 +1,47 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+// Hook job states.
+const (
+	// HookJobPending jobs are waiting to be processed.
+	HookJobPending = "pending"
+	// HookJobDone jobs were processed successfully.
+	HookJobDone = "done"
+	// HookJobFailed jobs have run out of attempts.
+	HookJobFailed = "failed"
+)
+
+// HookJob is a GitHub webhook delivery that is queued for
+// processing. Deliveries are identified by the X-GitHub-Delivery
+// header so that redeliveries are not processed twice.
+type HookJob struct {
+	ID          int64  `json:"id"           meddler:"hook_job_id,pk"`
+	Delivery    string `json:"delivery"     meddler:"hook_job_delivery"`
+	Event       string `json:"event"        meddler:"hook_job_event"`
+	BaseURL     string `json:"-"            meddler:"hook_job_base_url"`
+	Payload     string `json:"-"            meddler:"hook_job_payload"`
+	State       string `json:"state"        meddler:"hook_job_state"`
+	Attempts    int    `json:"attempts"     meddler:"hook_job_attempts"`
+	NextAttempt int64  `json:"next_attempt" meddler:"hook_job_next_attempt"`
+	LastError   string `json:"last_error"   meddler:"hook_job_last_error"`
+	Result      string `json:"result"       meddler:"hook_job_result"`
+	Created     int64  `json:"created"      meddler:"hook_job_created"`
+	Updated     int64  `json:"updated"      meddler:"hook_job_updated"`
+}
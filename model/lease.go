@@ -0,0 +1,29 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+// Lease grants one instance exclusive use of a key until it
+// expires. Leases serialize the processing of a pull request
+// when several instances share a database.
+type Lease struct {
+	ID      int64  `json:"id"      meddler:"lease_id,pk"`
+	Key     string `json:"key"     meddler:"lease_key"`
+	Owner   string `json:"owner"   meddler:"lease_owner"`
+	Expires int64  `json:"expires" meddler:"lease_expires"`
+}
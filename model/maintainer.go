@@ -29,6 +29,8 @@ type Person struct {
 	Name  string `json:"name"  toml:"name"`
 	Email string `json:"email" toml:"email"`
 	Login string `json:"login" toml:"login"`
+	// Slack is the Slack user id that is mentioned in notifications.
+	Slack string `json:"slack,omitempty" toml:"slack"`
 }
 
 // Org represents a group, team or subset of users.
@@ -92,88 +92,121 @@ func (match *UniverseMatch) Match(req *ApprovalRequest, proc Processor, a MatchA
 }
 
 func (match *MaintainerMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
+	candidates, err := match.candidates(req)
+	if err != nil {
+		return false, err
+	}
+	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+}
+
+func (match *MaintainerMatch) candidates(req *ApprovalRequest) (set.Set, error) {
 	candidates := set.Empty()
 	for k := range req.Maintainer.People {
 		candidates.Add(k)
 	}
-	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+	return candidates, nil
 }
 
 func (match *AnonymousMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
+	candidates, err := match.candidates(req)
+	if err != nil {
+		return false, err
+	}
+	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+}
+
+func (match *AnonymousMatch) candidates(req *ApprovalRequest) (set.Set, error) {
 	candidates := set.Empty()
 	for entity := range match.Entities {
 		ent := entity.String()
 		if org, ok := req.Maintainer.Org[ent]; ok {
 			people, err := org.GetPeople()
 			if err != nil {
-				return false, err
+				return nil, err
 			}
 			candidates.AddAll(people)
 		} else {
 			candidates.Add(ent)
 		}
 	}
-	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+	return candidates, nil
 }
 
 func (match *EntityMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
+	candidates, err := match.candidates(req)
+	if err != nil {
+		return false, err
+	}
+	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+}
+
+func (match *EntityMatch) candidates(req *ApprovalRequest) (set.Set, error) {
 	candidates := set.Empty()
 	ent := match.Entity.String()
 	if org, ok := req.Maintainer.Org[ent]; ok {
 		people, err := org.GetPeople()
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		candidates.AddAll(people)
 	} else {
 		candidates.Add(ent)
 	}
-	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+	return candidates, nil
 }
 
 func (match *UsMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
-	candidates := set.Empty()
-	mapping, err := req.Maintainer.PersonToOrg()
+	candidates, err := match.candidates(req)
 	if err != nil {
 		return false, err
 	}
-	if orgs, ok := mapping[req.PullRequest.Author.String()]; ok {
-		for name := range orgs {
-			if org, ok := req.Maintainer.Org[name]; ok {
-				people, err := org.GetPeople()
-				if err != nil {
-					return false, err
-				}
-				candidates.AddAll(people)
-			}
-		}
-	}
 	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
 }
 
-func (match *ThemMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
+func (match *UsMatch) candidates(req *ApprovalRequest) (set.Set, error) {
+	return authorOrgPeople(req)
+}
+
+// authorOrgPeople returns the people who share
+// an org with the author of the pull request.
+func authorOrgPeople(req *ApprovalRequest) (set.Set, error) {
 	us := set.Empty()
 	mapping, err := req.Maintainer.PersonToOrg()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	if orgs, ok := mapping[req.PullRequest.Author.String()]; ok {
 		for name := range orgs {
 			if org, ok := req.Maintainer.Org[name]; ok {
 				people, err := org.GetPeople()
 				if err != nil {
-					return false, err
+					return nil, err
 				}
 				us.AddAll(people)
 			}
 		}
 	}
+	return us, nil
+}
+
+func (match *ThemMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
+	candidates, err := match.candidates(req)
+	if err != nil {
+		return false, err
+	}
+	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+}
+
+func (match *ThemMatch) candidates(req *ApprovalRequest) (set.Set, error) {
+	us, err := authorOrgPeople(req)
+	if err != nil {
+		return nil, err
+	}
 	all := set.Empty()
 	for k := range req.Maintainer.People {
 		all.Add(k)
 	}
-	candidates := all.Difference(us)
-	return doMatch(candidates, match.Self, match.Approvals, req, proc, a, feedback)
+	return all.Difference(us), nil
 }
 
 func (match *AtLeastMatch) Match(req *ApprovalRequest, proc Processor, a MatchAction, feedback []Feedback) (bool, error) {
@@ -18,7 +18,55 @@ See the License for the specific language governing permissions and limitations
 */
 package model
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/strings/lowercase"
+)
+
+// TemplateMerge is the container struct for
+// the merge commit message template.
+type TemplateMerge struct {
+	// Title of the pull request
+	Title string
+	// Body of the pull request
+	Body string
+	// Number of the pull request
+	Number int
+	// Author of the pull request
+	Author string
+	// Policy is the name of the approval policy
+	// or the policy position if the policy is unnamed.
+	Policy string
+	// Comment is the text captured by the "comment"
+	// group of the approval pattern, if any.
+	Comment string
+	// MergedBy is the short name of this application
+	MergedBy string
+	// Approvers of the pull request sorted by login
+	Approvers []*Person
+	// Issues referenced by the pull request
+	Issues []*Issue
+	// CoAuthors are the commit authors of the pull request
+	// other than the pull request author, sorted by login
+	CoAuthors []*Person
+}
+
+const defaultMergeTemplate = `{{if .Comment}}{{.Comment}}
+{{end}}Merged by {{.MergedBy}}
+{{if .Approvers}}Approved by:
+{{range .Approvers}}{{person .}}
+{{end}}{{end}}`
+
+var mergeFuncs = template.FuncMap{
+	"person":   FormatPerson,
+	"coauthor": FormatCoAuthor,
+}
 
 func DefaultMerge() MergeConfig {
 	return MergeConfig{
@@ -41,3 +89,95 @@ func (m *MergeConfig) UnmarshalJSON(text []byte) error {
 	*m = MergeConfig(dummy)
 	return nil
 }
+
+func (m *MergeConfig) execute(body interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := m.Template.Execute(&buffer, body)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (m *MergeConfig) Compile() error {
+	err := m.build()
+	if err != nil {
+		return err
+	}
+	return m.validate()
+}
+
+func (m *MergeConfig) build() error {
+	var err error
+	raw := m.TemplateRaw
+	if len(raw) == 0 {
+		raw = defaultMergeTemplate
+	}
+	m.Template, err = template.New("merge").Funcs(mergeFuncs).Parse(raw)
+	return err
+}
+
+func (m *MergeConfig) validate() error {
+	sample := TemplateMerge{
+		Title:     "title",
+		Body:      "body",
+		Number:    1,
+		Author:    "author",
+		Policy:    "policy",
+		Comment:   "comment",
+		MergedBy:  envvars.Env.Branding.ShortName,
+		Approvers: []*Person{{Name: "name", Email: "email", Login: "login"}},
+		Issues:    []*Issue{{Number: 1, Title: "title", Author: lowercase.Create("author")}},
+		CoAuthors: []*Person{{Name: "name", Email: "email", Login: "login"}},
+	}
+	_, err := m.execute(sample)
+	if err != nil {
+		return fmt.Errorf("Illegal merge template %s: %s", m.TemplateRaw, err)
+	}
+	return nil
+}
+
+func (m *MergeConfig) GenerateMessage(body TemplateMerge) (string, error) {
+	if m.Template == nil {
+		err := m.build()
+		if err != nil {
+			return "", err
+		}
+	}
+	buffer, err := m.execute(body)
+	if err != nil {
+		return "", err
+	}
+	return string(buffer), nil
+}
+
+// FormatPerson formats as "Brad Rydzewski <brad.rydzewski@mail.com> (@bradrydzewski)"
+// omitting any fields that are empty.
+func FormatPerson(p *Person) string {
+	var parts []string
+	if len(p.Name) > 0 {
+		parts = append(parts, p.Name)
+	}
+	if len(p.Email) > 0 {
+		parts = append(parts, fmt.Sprintf("<%s>", p.Email))
+	}
+	if len(p.Login) > 0 {
+		parts = append(parts, fmt.Sprintf("(@%s)", p.Login))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatCoAuthor formats a git "Co-authored-by" trailer.
+// If the email address is unknown then the GitHub
+// noreply address for the login is used.
+func FormatCoAuthor(p *Person) string {
+	name := p.Name
+	if len(name) == 0 {
+		name = p.Login
+	}
+	email := p.Email
+	if len(email) == 0 {
+		email = fmt.Sprintf("%s@users.noreply.github.com", p.Login)
+	}
+	return fmt.Sprintf("Co-authored-by: %s <%s>", name, email)
+}
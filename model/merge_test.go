@@ -0,0 +1,74 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTemplateDefault(t *testing.T) {
+	cfg := DefaultMerge()
+	assert.Nil(t, cfg.Compile())
+	msg, err := cfg.GenerateMessage(TemplateMerge{
+		Comment:  "Hello",
+		MergedBy: "checks-out",
+		Approvers: []*Person{
+			{Name: "Brad Rydzewski", Email: "brad.rydzewski@mail.com", Login: "bradrydzewski"},
+			{Login: "alice"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello\nMerged by checks-out\nApproved by:\n"+
+		"Brad Rydzewski <brad.rydzewski@mail.com> (@bradrydzewski)\n(@alice)\n", msg)
+	msg, err = cfg.GenerateMessage(TemplateMerge{MergedBy: "checks-out"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Merged by checks-out\n", msg)
+}
+
+func TestMergeTemplateCustom(t *testing.T) {
+	cfg := MergeConfig{TemplateRaw: `{{.Title}} (#{{.Number}})
+{{range .Issues}}
+Closes #{{.Number}}{{end}}
+{{range .CoAuthors}}
+{{coauthor .}}{{end}}`}
+	assert.Nil(t, cfg.Compile())
+	msg, err := cfg.GenerateMessage(TemplateMerge{
+		Title:     "Fix the thing",
+		Number:    12,
+		Issues:    []*Issue{{Number: 3}},
+		CoAuthors: []*Person{{Login: "bob"}, {Name: "Carol", Email: "carol@mail.com", Login: "carol"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Fix the thing (#12)\n\nCloses #3\n\n"+
+		"Co-authored-by: bob <bob@users.noreply.github.com>\n"+
+		"Co-authored-by: Carol <carol@mail.com>", msg)
+}
+
+func TestMergeTemplateInvalid(t *testing.T) {
+	cfg := MergeConfig{TemplateRaw: "{{.Title"}
+	if cfg.Compile() == nil {
+		t.Error("unterminated action should not pass validation")
+	}
+	cfg = MergeConfig{TemplateRaw: "{{.Unknown}}"}
+	if cfg.Compile() == nil {
+		t.Error("unknown field should not pass validation")
+	}
+}
@@ -0,0 +1,147 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/mspiegel/go-multierror"
+)
+
+// Message reasons distinguish the variants of a message type.
+const (
+	ReasonComment  = "comment"
+	ReasonAuthor   = "author"
+	ReasonTitle    = "title"
+	ReasonAudit    = "audit"
+	ReasonOpened   = "opened"
+	ReasonReopened = "reopened"
+	ReasonBlocked  = "blocked"
+)
+
+// MessageData is the data of the notification message templates.
+type MessageData struct {
+	PullRequest MessagePullRequest
+	Slug        string
+	// Author is the author of the pull request.
+	Author string
+	// Actor is the author of the comment that caused the message.
+	Actor     string
+	Approvers []string
+	Policy    string
+	Tag       string
+	Release   string
+	Reason    string
+	// Default is the default text of the message.
+	Default string
+}
+
+type MessagePullRequest struct {
+	Number int
+	Title  string
+	Branch string
+}
+
+// defaultMessageTemplates are the templates of the messages
+// that are not configured by the comment target. Messages
+// without a default template use the text of the message.
+var defaultMessageTemplates = map[CommentMessage]string{
+	CommentApprove: "approval added by {{.Actor}}.",
+	CommentBlock: `{{if eq .Reason "author"}}blocked because it was created by unapproved author {{.Author}}.` +
+		`{{else if eq .Reason "title"}}blocked because its title indicates that it should not be merged` +
+		`{{else if eq .Reason "audit"}}blocked by gap in audit chain` +
+		`{{else}}blocked by {{.Actor}}.{{end}}`,
+	CommentOpen: `{{if eq .Reason "reopened"}}reopened` +
+		`{{else}}opened{{if .Policy}} Applying approval policy {{.Policy}}{{end}}{{end}}`,
+	CommentClose:      "closed without being merged",
+	CommentAccept:     "merged",
+	CommentReset:      "updated. No comments before this one will count for approval.",
+	CommentPushIgnore: "merged through the user interface. Merge commit ignored.",
+	CommentMerge:      "merged",
+	CommentTag:        "Tag {{.Tag}} has been added{{if .Release}} with release {{.Release}}{{end}}",
+	CommentDelete:     "Branch {{.PullRequest.Branch}} has been deleted",
+}
+
+// sampleMessageData is used to validate the message templates.
+var sampleMessageData = MessageData{
+	PullRequest: MessagePullRequest{
+		Number: 1,
+		Title:  "title",
+		Branch: "branch",
+	},
+	Slug:      "owner/repo",
+	Author:    "author",
+	Actor:     "actor",
+	Approvers: []string{"approver"},
+	Policy:    "policy",
+	Tag:       "1.0.0",
+	Release:   "release",
+	Reason:    ReasonComment,
+	Default:   "message",
+}
+
+// RenderMessage renders the message template with the data.
+func RenderMessage(text string, data MessageData) (string, error) {
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = tmpl.Execute(&b, data)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DefaultMessage renders the default text of a message. If the
+// data has a default text then it is used instead of the template.
+func DefaultMessage(t CommentMessage, data MessageData) (string, error) {
+	if data.Default != "" {
+		return data.Default, nil
+	}
+	text, ok := defaultMessageTemplates[t]
+	if !ok {
+		return "", fmt.Errorf("No default template for %s messages", t)
+	}
+	return RenderMessage(text, data)
+}
+
+func validateMessageTemplates(target TargetConfig) error {
+	var errs error
+	var names []string
+	for name := range target.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := strMapCommentMessage[name]; !ok {
+			errs = multierror.Append(errs, fmt.Errorf("Unknown message type %s in templates of the %s target", name, target.Target))
+			continue
+		}
+		_, err := RenderMessage(target.Templates[name], sampleMessageData)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Invalid %s template of the %s target: %v", name, target.Target, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,86 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"testing"
+)
+
+func TestDefaultMessage(t *testing.T) {
+	data := []struct {
+		msgType  CommentMessage
+		data     MessageData
+		expected string
+	}{
+		{CommentApprove, MessageData{Actor: "alice"}, "approval added by alice."},
+		{CommentBlock, MessageData{Actor: "bob", Reason: ReasonComment}, "blocked by bob."},
+		{CommentBlock, MessageData{Author: "carol", Reason: ReasonAuthor}, "blocked because it was created by unapproved author carol."},
+		{CommentBlock, MessageData{Reason: ReasonTitle}, "blocked because its title indicates that it should not be merged"},
+		{CommentBlock, MessageData{Reason: ReasonAudit}, "blocked by gap in audit chain"},
+		{CommentOpen, MessageData{Reason: ReasonOpened}, "opened"},
+		{CommentOpen, MessageData{Reason: ReasonOpened, Policy: "default"}, "opened Applying approval policy default"},
+		{CommentOpen, MessageData{Reason: ReasonReopened, Policy: "default"}, "reopened"},
+		{CommentReset, MessageData{}, "updated. No comments before this one will count for approval."},
+		{CommentTag, MessageData{Tag: "1.0.0"}, "Tag 1.0.0 has been added"},
+		{CommentTag, MessageData{Tag: "1.0.0", Release: "url"}, "Tag 1.0.0 has been added with release url"},
+		{CommentDelete, MessageData{PullRequest: MessagePullRequest{Branch: "feature"}}, "Branch feature has been deleted"},
+		{CommentError, MessageData{Default: "failed"}, "failed"},
+	}
+	for _, d := range data {
+		actual, err := DefaultMessage(d.msgType, d.data)
+		if err != nil {
+			t.Error(err)
+		}
+		if actual != d.expected {
+			t.Errorf("Expected %q but was %q", d.expected, actual)
+		}
+	}
+	if _, err := DefaultMessage(CommentError, MessageData{}); err == nil {
+		t.Error("Expected error for message without default template")
+	}
+}
+
+func TestValidateMessageTemplates(t *testing.T) {
+	c := CommentConfig{
+		Enable: true,
+		Targets: []TargetConfig{
+			{
+				Target: "slack",
+				Templates: map[string]string{
+					"approve": ":+1: {{.Actor}} approved {{.PullRequest.Title}}",
+					"merge":   "{{.Default}} by {{range .Approvers}}{{.}} {{end}}",
+				},
+			},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	invalid := []map[string]string{
+		{"approve": "{{.Actor"},
+		{"approve": "{{.Unknown}}"},
+		{"unknown": "message"},
+	}
+	for _, templates := range invalid {
+		c.Targets[0].Templates = templates
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected error for templates %v", templates)
+		}
+	}
+}
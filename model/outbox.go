@@ -0,0 +1,48 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+// Outbox message states.
+const (
+	// OutboxPending messages are waiting to be delivered.
+	OutboxPending = "pending"
+	// OutboxDead messages have failed and will not be
+	// retried until they are retried through the API.
+	OutboxDead = "dead"
+)
+
+// OutboxMessage is a notification that is queued for
+// delivery to a comment target. Delivered messages are
+// removed from the outbox.
+type OutboxMessage struct {
+	ID          int64    `json:"id"           meddler:"outbox_id,pk"`
+	Target      string   `json:"target"       meddler:"outbox_target"`
+	Url         string   `json:"-"            meddler:"outbox_url"`
+	Host        string   `json:"-"            meddler:"outbox_host"`
+	User        string   `json:"-"            meddler:"outbox_user"`
+	Names       []string `json:"names"        meddler:"outbox_names,json"`
+	Slug        string   `json:"slug"         meddler:"outbox_slug"`
+	Payload     string   `json:"payload"      meddler:"outbox_payload"`
+	State       string   `json:"state"        meddler:"outbox_state"`
+	Attempts    int      `json:"attempts"     meddler:"outbox_attempts"`
+	NextAttempt int64    `json:"next_attempt" meddler:"outbox_next_attempt"`
+	LastError   string   `json:"last_error"   meddler:"outbox_last_error"`
+	Created     int64    `json:"created"      meddler:"outbox_created"`
+	Updated     int64    `json:"updated"      meddler:"outbox_updated"`
+}
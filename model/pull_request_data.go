@@ -0,0 +1,39 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import "time"
+
+// PullRequestData holds the inputs of a pull request evaluation
+// that are loaded from the remote in one go.
+type PullRequestData struct {
+	Files    []CommitFile
+	Commits  []Commit
+	Comments []*Comment
+	Reviews  []*Review
+	// HeadComments and HeadReviews are the feedback since the
+	// head commit, as returned by GetCommentsSinceHead and
+	// GetReviewsSinceHead.
+	HeadComments []*Comment
+	HeadReviews  []*Review
+	// HeadDate is the committer date of the head commit. When
+	// merges from the user interface are ignored it is the date
+	// of the first commit that is not such a merge.
+	HeadDate time.Time
+}
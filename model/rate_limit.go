@@ -0,0 +1,44 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+// RateLimit is the GitHub API rate limit state of a token
+// for a resource as seen in the latest responses.
+type RateLimit struct {
+	Login string `json:"login"`
+	// Resource is the rate limit bucket, such as core or search
+	Resource  string `json:"resource"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	// Reset is when the rate limit window resets, in unix seconds
+	Reset int64 `json:"reset"`
+	// BlockedUntil is when requests resume after a secondary
+	// (abuse) rate limit, in unix seconds
+	BlockedUntil int64 `json:"blocked_until,omitempty"`
+	Requests     int64 `json:"requests"`
+	// Delayed counts the requests that waited for the rate limit
+	Delayed int64 `json:"delayed"`
+	// Rejected counts the requests that were not sent because
+	// the wait was too long
+	Rejected int64 `json:"rejected"`
+	// Retried counts the requests that were retried after a
+	// secondary rate limit
+	Retried int64 `json:"retried"`
+	Updated int64 `json:"updated"`
+}
@@ -0,0 +1,320 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/capitalone/checks-out/set"
+)
+
+type ReleaseConfig struct {
+	// enable creation of a release for each new tag. Default is false.
+	Enable bool `json:"enable"`
+	// identify the release as a prerelease. Default is false.
+	Prerelease bool `json:"prerelease"`
+	// create an unpublished draft release. Default is false.
+	Draft bool `json:"draft"`
+	// grouping of pull requests in the release notes. Allowed
+	// values are "none", "label", and "prefix". Default is "prefix".
+	Group string `json:"group"`
+	// golang text/template for producing the release notes.
+	// Container struct is TemplateRelease.
+	TemplateRaw string             `json:"template,omitempty"`
+	Template    *template.Template `json:"-"`
+}
+
+// Release is a named release that is attached to a tag.
+type Release struct {
+	Tag        string
+	Name       string
+	Body       string
+	Draft      bool
+	Prerelease bool
+}
+
+// TemplateRelease is the container struct for
+// the release notes template.
+type TemplateRelease struct {
+	// Tag is the name of the new tag
+	Tag string
+	// PreviousTag is the name of the previous tag
+	// or empty if there is no previous tag
+	PreviousTag string
+	// Groups of the pull requests in display order
+	Groups []*ReleaseGroup
+	// PullRequests merged since the previous tag
+	PullRequests []*ReleaseEntry
+}
+
+// ReleaseGroup is a heading in the release notes.
+type ReleaseGroup struct {
+	Name         string
+	PullRequests []*ReleaseEntry
+}
+
+// ReleaseEntry is a pull request in the release notes.
+type ReleaseEntry struct {
+	Number int
+	Title  string
+	Author string
+	Labels []string
+	// Type and Scope are populated when the title
+	// follows the conventional commits specification.
+	Type     string
+	Scope    string
+	Breaking bool
+}
+
+const defaultReleaseTemplate = `{{range .Groups}}## {{.Name}}
+
+{{range .PullRequests}}* {{.Title}} (#{{.Number}}) @{{.Author}}
+{{end}}
+{{end}}`
+
+const (
+	releaseBreaking = "Breaking Changes"
+	releaseOther    = "Other Changes"
+)
+
+var releaseGroups = set.New("none", "label", "prefix")
+
+// conventional commit types in the order they appear in the release notes
+var releaseTypes = []struct {
+	Type string
+	Name string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"refactor", "Code Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"style", "Styles"},
+	{"chore", "Chores"},
+}
+
+func DefaultRelease() ReleaseConfig {
+	return ReleaseConfig{
+		Enable:     false,
+		Prerelease: false,
+		Draft:      false,
+		Group:      "prefix",
+	}
+}
+
+// Used to avoid recursion in UnmarshalJSON
+type shadowReleaseConfig ReleaseConfig
+
+func (r *ReleaseConfig) UnmarshalJSON(text []byte) error {
+	dummy := shadowReleaseConfig(DefaultRelease())
+	err := json.Unmarshal(text, &dummy)
+	if err != nil {
+		return err
+	}
+	*r = ReleaseConfig(dummy)
+	return nil
+}
+
+func (r *ReleaseConfig) execute(body interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := r.Template.Execute(&buffer, body)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (r *ReleaseConfig) Compile() error {
+	err := r.build()
+	if err != nil {
+		return err
+	}
+	return r.validate()
+}
+
+func (r *ReleaseConfig) build() error {
+	var err error
+	raw := r.TemplateRaw
+	if len(raw) == 0 {
+		raw = defaultReleaseTemplate
+	}
+	r.Template, err = template.New("release").Parse(raw)
+	return err
+}
+
+func (r *ReleaseConfig) validate() error {
+	if len(r.Group) > 0 && !releaseGroups.Contains(r.Group) {
+		return fmt.Errorf("%s is not one of the permitted release groups %s",
+			r.Group, releaseGroups.Keys())
+	}
+	entry := &ReleaseEntry{
+		Number: 1,
+		Title:  "feat: title",
+		Author: "author",
+		Labels: []string{"label"},
+		Type:   "feat",
+	}
+	sample := TemplateRelease{
+		Tag:          "1.0.1",
+		PreviousTag:  "1.0.0",
+		Groups:       []*ReleaseGroup{{Name: "Features", PullRequests: []*ReleaseEntry{entry}}},
+		PullRequests: []*ReleaseEntry{entry},
+	}
+	_, err := r.execute(sample)
+	if err != nil {
+		return fmt.Errorf("Illegal release template %s: %s", r.TemplateRaw, err)
+	}
+	return nil
+}
+
+// GenerateNotes produces the release notes for the pull requests.
+func (r *ReleaseConfig) GenerateNotes(tag, previous string, prs []PullRequest) (string, error) {
+	if r.Template == nil {
+		err := r.build()
+		if err != nil {
+			return "", err
+		}
+	}
+	entries := make([]*ReleaseEntry, len(prs))
+	for i, pr := range prs {
+		entries[i] = newReleaseEntry(pr)
+	}
+	body := TemplateRelease{
+		Tag:          tag,
+		PreviousTag:  previous,
+		Groups:       r.group(entries),
+		PullRequests: entries,
+	}
+	buffer, err := r.execute(body)
+	if err != nil {
+		return "", err
+	}
+	return string(buffer), nil
+}
+
+func newReleaseEntry(pr PullRequest) *ReleaseEntry {
+	entry := &ReleaseEntry{
+		Number: pr.Number,
+		Title:  pr.Title,
+		Author: pr.Author.String(),
+		Labels: pr.Labels,
+	}
+	if cc, ok := ParseConventional(pr.Title); ok {
+		entry.Type = cc.Type
+		entry.Scope = cc.Scope
+		entry.Breaking = cc.Breaking
+	}
+	return entry
+}
+
+func (r *ReleaseConfig) group(entries []*ReleaseEntry) []*ReleaseGroup {
+	if len(entries) == 0 {
+		return nil
+	}
+	switch r.Group {
+	case "label":
+		return groupByLabel(entries)
+	case "prefix":
+		return groupByPrefix(entries)
+	default:
+		return []*ReleaseGroup{{Name: "Changes", PullRequests: entries}}
+	}
+}
+
+// groupByLabel places each pull request in the group of
+// its first label in alphabetical order. Groups are sorted
+// alphabetically and unlabeled pull requests are listed last.
+func groupByLabel(entries []*ReleaseEntry) []*ReleaseGroup {
+	groups := map[string]*ReleaseGroup{}
+	var names []string
+	var other []*ReleaseEntry
+	for _, entry := range entries {
+		if len(entry.Labels) == 0 {
+			other = append(other, entry)
+			continue
+		}
+		labels := append([]string(nil), entry.Labels...)
+		sort.Strings(labels)
+		g, ok := groups[labels[0]]
+		if !ok {
+			g = &ReleaseGroup{Name: labels[0]}
+			groups[labels[0]] = g
+			names = append(names, labels[0])
+		}
+		g.PullRequests = append(g.PullRequests, entry)
+	}
+	sort.Strings(names)
+	var result []*ReleaseGroup
+	for _, name := range names {
+		result = append(result, groups[name])
+	}
+	if len(other) > 0 {
+		result = append(result, &ReleaseGroup{Name: releaseOther, PullRequests: other})
+	}
+	return result
+}
+
+// groupByPrefix places each pull request in the group of
+// its conventional commit type. Breaking changes are listed
+// first and pull requests with an unknown type are listed last.
+func groupByPrefix(entries []*ReleaseEntry) []*ReleaseGroup {
+	groups := map[string]*ReleaseGroup{}
+	var breaking, other []*ReleaseEntry
+	for _, entry := range entries {
+		if entry.Breaking {
+			breaking = append(breaking, entry)
+			continue
+		}
+		g, ok := groups[entry.Type]
+		if !ok {
+			g = &ReleaseGroup{}
+			groups[entry.Type] = g
+		}
+		g.PullRequests = append(g.PullRequests, entry)
+	}
+	var result []*ReleaseGroup
+	if len(breaking) > 0 {
+		result = append(result, &ReleaseGroup{Name: releaseBreaking, PullRequests: breaking})
+	}
+	for _, t := range releaseTypes {
+		if g, ok := groups[t.Type]; ok {
+			g.Name = t.Name
+			result = append(result, g)
+			delete(groups, t.Type)
+		}
+	}
+	for _, g := range groups {
+		other = append(other, g.PullRequests...)
+	}
+	if len(other) > 0 {
+		sort.Slice(other, func(i, j int) bool {
+			return other[i].Number < other[j].Number
+		})
+		result = append(result, &ReleaseGroup{Name: releaseOther, PullRequests: other})
+	}
+	return result
+}
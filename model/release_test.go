@@ -0,0 +1,123 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+import (
+	"testing"
+
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var releasePullRequests = []PullRequest{
+	{Issue: Issue{Number: 1, Title: "fix: off by one", Author: lowercase.Create("alice")}},
+	{Issue: Issue{Number: 2, Title: "feat(api)!: remove v1", Author: lowercase.Create("bob")}, Labels: []string{"breaking"}},
+	{Issue: Issue{Number: 3, Title: "feat: add v2", Author: lowercase.Create("carol")}, Labels: []string{"enhancement"}},
+	{Issue: Issue{Number: 4, Title: "Update readme", Author: lowercase.Create("dan")}, Labels: []string{"docs", "chore"}},
+	{Issue: Issue{Number: 5, Title: "wip: something", Author: lowercase.Create("erin")}},
+}
+
+func TestParseConventional(t *testing.T) {
+	cc, ok := ParseConventional("feat(api)!: remove v1")
+	assert.True(t, ok)
+	assert.Equal(t, ConventionalCommit{Type: "feat", Scope: "api", Breaking: true}, cc)
+	cc, ok = ParseConventional("Fix: lowercase the type\n\nBREAKING CHANGE: it changed")
+	assert.True(t, ok)
+	assert.Equal(t, ConventionalCommit{Type: "fix", Breaking: true}, cc)
+	_, ok = ParseConventional("Update readme")
+	assert.False(t, ok)
+	_, ok = ParseConventional("feat:")
+	assert.False(t, ok)
+}
+
+func TestReleaseNotesPrefix(t *testing.T) {
+	cfg := DefaultRelease()
+	assert.Nil(t, cfg.Compile())
+	notes, err := cfg.GenerateNotes("1.1.0", "1.0.0", releasePullRequests)
+	assert.Nil(t, err)
+	assert.Equal(t, `## Breaking Changes
+
+* feat(api)!: remove v1 (#2) @bob
+
+## Features
+
+* feat: add v2 (#3) @carol
+
+## Bug Fixes
+
+* fix: off by one (#1) @alice
+
+## Other Changes
+
+* Update readme (#4) @dan
+* wip: something (#5) @erin
+
+`, notes)
+}
+
+func TestReleaseNotesLabel(t *testing.T) {
+	cfg := ReleaseConfig{Group: "label", TemplateRaw: `{{.PreviousTag}}..{{.Tag}}
+{{range .Groups}}{{.Name}}:{{range .PullRequests}} #{{.Number}}{{end}}
+{{end}}`}
+	assert.Nil(t, cfg.Compile())
+	notes, err := cfg.GenerateNotes("1.1.0", "1.0.0", releasePullRequests)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0..1.1.0\nbreaking: #2\nchore: #4\nenhancement: #3\nOther Changes: #1 #5\n", notes)
+}
+
+func TestReleaseNotesEmpty(t *testing.T) {
+	cfg := DefaultRelease()
+	notes, err := cfg.GenerateNotes("1.1.0", "1.0.0", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", notes)
+}
+
+func TestReleaseValidate(t *testing.T) {
+	cfg := ReleaseConfig{Group: "author"}
+	if cfg.Compile() == nil {
+		t.Error("unknown group should not pass validation")
+	}
+	cfg = ReleaseConfig{TemplateRaw: "{{.Version}}"}
+	if cfg.Compile() == nil {
+		t.Error("unknown field should not pass validation")
+	}
+	tag := DefaultTag()
+	tag.Release.TemplateRaw = "{{range .Groups}}"
+	if tag.Compile() == nil {
+		t.Error("release template should be validated with the tag template")
+	}
+}
+
+func TestConventionalIncrement(t *testing.T) {
+	cases := map[string]Semver{
+		"feat!: remove v1": Major,
+		"feat: add v2":     Minor,
+		"fix(api): typo":   Patch,
+	}
+	for message, expected := range cases {
+		cc, _ := ParseConventional(message)
+		inc, ok := cc.Increment()
+		assert.True(t, ok, message)
+		assert.Equal(t, expected, inc, message)
+	}
+	cc, _ := ParseConventional("docs: readme")
+	_, ok := cc.Increment()
+	assert.False(t, ok)
+}
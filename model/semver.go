@@ -19,6 +19,8 @@ See the License for the specific language governing permissions and limitations
 */
 package model
 
+import "strings"
+
 type Semver int
 
 const (
@@ -27,3 +29,26 @@ const (
 	Minor
 	None
 )
+
+// semverRank orders the increments from smallest to largest
+var semverRank = map[Semver]int{
+	None:  0,
+	Patch: 1,
+	Minor: 2,
+	Major: 3,
+}
+
+// Max returns the larger of the two increments.
+func (s Semver) Max(other Semver) Semver {
+	if semverRank[other] > semverRank[s] {
+		return other
+	}
+	return s
+}
+
+// ParseSemver converts a case-insensitive increment name
+// such as "major" into its enum value.
+func ParseSemver(str string) (Semver, bool) {
+	s, ok := strMapSemver[strings.ToLower(strings.TrimSpace(str))]
+	return s, ok
+}
@@ -0,0 +1,29 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package model
+
+// SlackLink links a Slack user to the GitHub user
+// who runs the Slack commands of the Slack user.
+type SlackLink struct {
+	ID      int64  `json:"id"      meddler:"slack_link_id,pk"`
+	Team    string `json:"team"    meddler:"slack_link_team"`
+	User    string `json:"user"    meddler:"slack_link_user"`
+	Login   string `json:"login"   meddler:"slack_link_login"`
+	Created int64  `json:"created" meddler:"slack_link_created"`
+}
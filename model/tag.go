@@ -21,9 +21,13 @@ package model
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"text/template"
+
+	"github.com/capitalone/checks-out/set"
 )
 
 type Tag string
@@ -47,10 +51,60 @@ type TagConfig struct {
 	// Allowed values are "major", "minor", "patch", and "none".
 	// Default is "patch".
 	Increment Semver `json:"increment"`
+	// Sources of a per pull request version increment for
+	// the "semver" algorithm. Allowed values are "label",
+	// "commit", and "comment". The largest increment that is
+	// requested is used. If no increment is requested then
+	// the 'increment' field is used. Default is empty.
+	Bump []string `json:"bump,omitempty"`
+	// Only tags that begin with the prefix are considered
+	// when searching for the previous version of the "semver"
+	// algorithm. The template must produce tags that begin
+	// with the prefix. Default is empty.
+	Prefix string `json:"prefix,omitempty"`
+	// golang text/template for producing the prerelease
+	// identifier of the "semver" algorithm. The identifier
+	// "rc" produces versions "1.0.0-rc.1", "1.0.0-rc.2", etc.
+	// Container struct is TemplateTag. Default is empty.
+	PrereleaseRaw string             `json:"prerelease,omitempty"`
+	Prerelease    *template.Template `json:"-"`
+	// If true then create an annotated tag object whose message
+	// records the pull request, policy and approvers. If false
+	// then create a lightweight tag. Default is true.
+	Annotated bool `json:"annotated"`
+	// If true then sign the annotated tag with the GPG key
+	// of the server. Default is false.
+	Sign bool `json:"sign"`
+	// Optional release that is created for each new tag.
+	Release ReleaseConfig `json:"release"`
+}
+
+// AnnotatedTag is the content of an annotated tag object.
+type AnnotatedTag struct {
+	Name    string
+	SHA     string
+	Message string
+	Sign    bool
 }
 
 type TemplateTag struct {
 	Version string
+	// Branch is the base branch of the pull request
+	Branch string
+	// PullRequest is the number of the pull request
+	PullRequest int
+	// ShortSHA is the abbreviated merge commit SHA
+	ShortSHA string
+	// Date is the UTC date of the tag in YYYYMMDD format
+	Date string
+}
+
+var sampleTag = TemplateTag{
+	Version:     "1.0.0",
+	Branch:      "master",
+	PullRequest: 1,
+	ShortSHA:    "0123456",
+	Date:        "20170101",
 }
 
 func DefaultTag() TagConfig {
@@ -60,9 +114,16 @@ func DefaultTag() TagConfig {
 		TemplateRaw: "{{.Version}}",
 		Docker:      false,
 		Increment:   Patch,
+		Annotated:   true,
+		Sign:        false,
+		Release:     DefaultRelease(),
 	}
 }
 
+var bumpSources = set.New("label", "commit", "comment")
+
+var prereleaseRegex = regexp.MustCompile(`[^0-9A-Za-z\-.]+`)
+
 var dockerRegex = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
 
 var illegalRegex = regexp.MustCompile(`[[:cntrl:]]|[ ~^:?*\\\]]`)
@@ -107,21 +168,54 @@ func (t *TagConfig) Compile() error {
 	if err != nil {
 		return err
 	}
-	return t.validate()
+	err = t.validate()
+	if err != nil {
+		return err
+	}
+	return t.Release.Compile()
 }
 
 func (t *TagConfig) build() error {
 	var err error
 	t.Template, err = template.New("tag").Parse(t.TemplateRaw)
+	if err != nil {
+		return err
+	}
+	if len(t.PrereleaseRaw) > 0 {
+		t.Prerelease, err = template.New("prerelease").Parse(t.PrereleaseRaw)
+	}
 	return err
 }
 
 func (t *TagConfig) validate() error {
-	buffer, err := t.execute(TemplateTag{Version:"1.0.0"})
+	if t.Sign && !t.Annotated {
+		return errors.New("Only annotated tags can be signed")
+	}
+	for _, source := range t.Bump {
+		if !bumpSources.Contains(source) {
+			return fmt.Errorf("%s is not one of the permitted bump sources %s",
+				source, bumpSources.Keys())
+		}
+	}
+	buffer, err := t.execute(sampleTag)
 	if err != nil {
 		return err
 	}
 	tpl := string(buffer)
+	if !strings.HasPrefix(tpl, t.Prefix) {
+		err := fmt.Errorf(`Illegal template tag %s:
+			the tag %s does not begin with the prefix %s`, t.TemplateRaw, tpl, t.Prefix)
+		return err
+	}
+	if t.Prerelease != nil {
+		ident, err := t.GeneratePrerelease(sampleTag)
+		if err != nil {
+			return err
+		}
+		if len(ident) == 0 {
+			return fmt.Errorf("Illegal prerelease template %s: identifier is empty", t.PrereleaseRaw)
+		}
+	}
 	if t.Docker && !dockerRegex.MatchString(tpl) {
 		err := fmt.Errorf(`Illegal template tag %s with Docker validation enabled:
 			only [A-Za-z0-9_.-] characters are allowed`, t.TemplateRaw)
@@ -137,3 +231,19 @@ func (t *TagConfig) GenerateTag(body TemplateTag) (string, error) {
 	}
 	return string(buffer), nil
 }
+
+// GeneratePrerelease produces the prerelease identifier.
+// Characters that are not permitted in a semantic version
+// prerelease identifier are replaced with a hyphen.
+func (t *TagConfig) GeneratePrerelease(body TemplateTag) (string, error) {
+	if t.Prerelease == nil {
+		return "", nil
+	}
+	var buffer bytes.Buffer
+	err := t.Prerelease.Execute(&buffer, body)
+	if err != nil {
+		return "", err
+	}
+	ident := prereleaseRegex.ReplaceAllString(buffer.String(), "-")
+	return strings.Trim(ident, "-."), nil
+}
@@ -43,3 +43,80 @@ func TestTagValidateDocker(t *testing.T) {
 		t.Error("This should work")
 	}
 }
+
+func TestTagValidateBump(t *testing.T) {
+	cfg := DefaultTag()
+	cfg.Bump = []string{"label", "commit", "comment"}
+	if cfg.Compile() != nil {
+		t.Error("This should work")
+	}
+	cfg.Bump = []string{"title"}
+	if cfg.Compile() == nil {
+		t.Error("unknown bump source should not pass validation")
+	}
+}
+
+func TestSemverMax(t *testing.T) {
+	if None.Max(Patch) != Patch || Patch.Max(Minor) != Minor || Major.Max(Minor) != Major {
+		t.Error("larger increment should be returned")
+	}
+	if s, ok := ParseSemver(" Major"); !ok || s != Major {
+		t.Error("increment should be parsed case-insensitive")
+	}
+	if _, ok := ParseSemver("huge"); ok {
+		t.Error("unknown increment should not be parsed")
+	}
+}
+
+func TestTagValidatePrefix(t *testing.T) {
+	cfg := TagConfig{TemplateRaw: "api/v{{.Version}}", Prefix: "api/"}
+	if cfg.Compile() != nil {
+		t.Error("This should work")
+	}
+	cfg = TagConfig{TemplateRaw: "v{{.Version}}", Prefix: "api/"}
+	if cfg.Compile() == nil {
+		t.Error("template without the prefix should not pass validation")
+	}
+}
+
+func TestGeneratePrerelease(t *testing.T) {
+	cfg := TagConfig{TemplateRaw: "{{.Version}}", PrereleaseRaw: "rc.{{.Branch}}"}
+	if cfg.Compile() != nil {
+		t.Error("This should work")
+	}
+	ident, err := cfg.GeneratePrerelease(TemplateTag{Branch: "release/1.2_x"})
+	if err != nil || ident != "rc.release-1.2-x" {
+		t.Error("Expected rc.release-1.2-x, got", ident, err)
+	}
+	cfg = TagConfig{TemplateRaw: "{{.Version}}", PrereleaseRaw: "{{.Foo}}"}
+	if cfg.Compile() == nil {
+		t.Error("unknown field should not pass validation")
+	}
+	cfg = TagConfig{TemplateRaw: "{{.Version}}"}
+	if ident, _ := cfg.GeneratePrerelease(TemplateTag{}); ident != "" {
+		t.Error("Expected empty identifier, got", ident)
+	}
+}
+
+func TestTagValidateSign(t *testing.T) {
+	cfg := DefaultTag()
+	cfg.Sign = true
+	if cfg.Compile() != nil {
+		t.Error("This should work")
+	}
+	cfg.Annotated = false
+	if cfg.Compile() == nil {
+		t.Error("lightweight tags cannot be signed")
+	}
+	config := NonEmptyConfig()
+	config.Tag.Enable = true
+	config.Tag.Sign = true
+	caps := AllowAll()
+	if validateCapabilities(config, caps) != nil {
+		t.Error("This should work")
+	}
+	caps.Repo.SignTag = false
+	if validateCapabilities(config, caps) == nil {
+		t.Error("signing requires a server signing key")
+	}
+}
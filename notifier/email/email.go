@@ -0,0 +1,340 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// SMTP connection security modes.
+const (
+	ModeStartTLS = "starttls"
+	ModeTLS      = "tls"
+	ModeNone     = "none"
+)
+
+var (
+	githubUrl   = envvars.Env.Github.Url
+	dialTimeout = 30 * time.Second
+	// rootCAs is nil to use the host root certificates.
+	rootCAs *x509.CertPool
+	now     = time.Now
+)
+
+func init() {
+	notifier.Register(model.Email, &MySender{})
+}
+
+type MySender struct{}
+
+// Server is the configuration of the SMTP server.
+type Server struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      string
+}
+
+func envServer() Server {
+	return Server{
+		Host:     envvars.Env.Email.Host,
+		Port:     envvars.Env.Email.Port,
+		Username: envvars.Env.Email.Username,
+		Password: envvars.Env.Email.Password,
+		From:     envvars.Env.Email.From,
+		TLS:      envvars.Env.Email.TLS,
+	}
+}
+
+// Prefix is empty because the pull request
+// is rendered by the email templates.
+func (ms *MySender) Prefix(mw notifier.MessageWrapper) string {
+	return ""
+}
+
+func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) error {
+	server := envServer()
+	if server.Host == "" || server.From == "" {
+		return notifier.Permanent(errors.New("SMTP_HOST and SMTP_FROM are not configured"))
+	}
+	to := Recipients(header, names)
+	if len(to) == 0 {
+		return nil
+	}
+	err := server.Send(header, message, to)
+	if err != nil {
+		log.Warnf("Error while sending email to %v: %v", to, err)
+	}
+	return err
+}
+
+// Recipients resolves the names of the target into email
+// addresses. Each name is either an email address, optionally
+// with a display name, or the special approvers-needed name.
+func Recipients(header notifier.MessageHeader, names []string) []*mail.Address {
+	var result []*mail.Address
+	seen := make(map[string]bool)
+	add := func(addr *mail.Address) {
+		key := strings.ToLower(addr.Address)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, addr)
+		}
+	}
+	for _, name := range names {
+		if name == model.ApproversNeeded {
+			for _, p := range header.NeededApprovers {
+				if p.Email != "" {
+					add(&mail.Address{Name: p.Name, Address: p.Email})
+				}
+			}
+			continue
+		}
+		addr, err := mail.ParseAddress(name)
+		if err != nil {
+			log.Warnf("Skipping invalid email address %s: %v", name, err)
+			continue
+		}
+		add(addr)
+	}
+	return result
+}
+
+// Send delivers the message to the recipients.
+func (s Server) Send(header notifier.MessageHeader, message string, to []*mail.Address) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+	msg, err := BuildMessage(from, to, header, message)
+	if err != nil {
+		return err
+	}
+	client, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if s.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host))
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(from.Address)
+	if err != nil {
+		return err
+	}
+	for _, addr := range to {
+		err = client.Rcpt(addr.Address)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s Server) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	tlsConfig := &tls.Config{ServerName: s.Host, RootCAs: rootCAs}
+	var conn net.Conn
+	var err error
+	switch s.TLS {
+	case ModeTLS:
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", addr, tlsConfig)
+	case ModeStartTLS, ModeNone:
+		conn, err = net.DialTimeout("tcp", addr, dialTimeout)
+	default:
+		return nil, fmt.Errorf("Unknown SMTP_TLS mode %s", s.TLS)
+	}
+	if err != nil {
+		return nil, err
+	}
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.TLS == ModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("SMTP server does not support STARTTLS")
+		}
+		err = client.StartTLS(tlsConfig)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+type templateData struct {
+	Title    string
+	Lines    []string
+	Url      string
+	RepoUrl  string
+	Slug     string
+	Policy   string
+	Needed   []string
+	Branding string
+}
+
+const textTemplate = `{{.Title}}
+{{range .Lines}}
+{{.}}{{end}}
+
+{{.Url}}
+{{if .Policy}}
+Approval policy: {{.Policy}}
+{{end}}{{if .Needed}}
+Approval is still needed from one of:
+{{range .Needed}}- {{.}}
+{{end}}{{end}}
+--
+Sent by {{.Branding}} for {{.Slug}}
+`
+
+const htmlTemplate = `<html>
+<body>
+<h3><a href="{{.Url}}">{{.Title}}</a></h3>
+{{range .Lines}}<p>{{.}}</p>
+{{end}}{{if .Policy}}<p>Approval policy: {{.Policy}}</p>
+{{end}}{{if .Needed}}<p>Approval is still needed from one of:</p>
+<ul>
+{{range .Needed}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}<p>Sent by {{.Branding}} for <a href="{{.RepoUrl}}">{{.Slug}}</a></p>
+</body>
+</html>
+`
+
+var (
+	textBody = template.Must(template.New("text").Parse(textTemplate))
+	htmlBody = htmltemplate.Must(htmltemplate.New("html").Parse(htmlTemplate))
+)
+
+func newTemplateData(header notifier.MessageHeader, message string) templateData {
+	data := templateData{
+		Title:    fmt.Sprintf("Pull Request %s (#%d) in %s", header.PrName, header.PrNumber, header.Slug),
+		Lines:    strings.Split(message, "\n"),
+		Url:      fmt.Sprintf("%s/%s/pull/%d", githubUrl, header.Slug, header.PrNumber),
+		RepoUrl:  fmt.Sprintf("%s/%s", githubUrl, header.Slug),
+		Slug:     header.Slug,
+		Policy:   header.Policy,
+		Branding: envvars.Env.Branding.ShortName,
+	}
+	for _, p := range header.NeededApprovers {
+		data.Needed = append(data.Needed, model.FormatPerson(p))
+	}
+	return data
+}
+
+// BuildMessage renders a multipart message
+// with text and HTML alternatives.
+func BuildMessage(from *mail.Address, to []*mail.Address, header notifier.MessageHeader, message string) ([]byte, error) {
+	data := newTemplateData(header, message)
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	var recipients []string
+	for _, addr := range to {
+		recipients = append(recipients, addr.String())
+	}
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", fmt.Sprintf("[%s] %s", header.Slug, data.Title)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	err := writePart(mw, "text/plain", func(b *bytes.Buffer) error {
+		return textBody.Execute(b, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = writePart(mw, "text/html", func(b *bytes.Buffer) error {
+		return htmlBody.Execute(b, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = mw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writePart(mw *multipart.Writer, contentType string, render func(b *bytes.Buffer) error) error {
+	var body bytes.Buffer
+	err := render(&body)
+	if err != nil {
+		return err
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Type", contentType+"; charset=utf-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	_, err = qp.Write(body.Bytes())
+	if err != nil {
+		return err
+	}
+	return qp.Close()
+}
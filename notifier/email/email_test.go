@@ -0,0 +1,328 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package email
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+)
+
+// fakeServer is a minimal in-process SMTP server
+// that records the last message it received.
+type fakeServer struct {
+	listener net.Listener
+	tls      *tls.Config
+	implicit bool
+	done     chan struct{}
+	secure   bool
+	auth     string
+	from     string
+	rcpts    []string
+	data     string
+}
+
+func newFakeServer(t *testing.T, tlsConfig *tls.Config, implicit bool) *fakeServer {
+	var l net.Listener
+	var err error
+	if implicit {
+		l, err = tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	} else {
+		l, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := &fakeServer{listener: l, tls: tlsConfig, implicit: implicit, done: make(chan struct{})}
+	go fs.serve()
+	return fs
+}
+
+func (fs *fakeServer) port() int {
+	return fs.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (fs *fakeServer) serve() {
+	defer close(fs.done)
+	conn, err := fs.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	fs.secure = fs.implicit
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP fake")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, line[:len(verb)]))
+		switch verb {
+		case "EHLO", "HELO":
+			lines := []string{"localhost"}
+			if fs.tls != nil && !fs.secure {
+				lines = append(lines, "STARTTLS")
+			}
+			lines = append(lines, "AUTH PLAIN")
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				tp.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 ready")
+			tlsConn := tls.Server(conn, fs.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn = tlsConn
+			tp = textproto.NewConn(conn)
+			fs.secure = true
+		case "AUTH":
+			decoded, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			fs.auth = string(decoded)
+			tp.PrintfLine("235 ok")
+		case "MAIL":
+			fs.from = arg
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			fs.rcpts = append(fs.rcpts, arg)
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			fs.data = string(data)
+			tp.PrintfLine("250 ok")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 unknown")
+		}
+	}
+}
+
+func (fs *fakeServer) wait(t *testing.T) {
+	select {
+	case <-fs.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for fake SMTP server")
+	}
+	fs.listener.Close()
+}
+
+// testCertificate borrows the certificate of the httptest
+// package which is valid for 127.0.0.1.
+func testCertificate() (*tls.Config, *x509.CertPool) {
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	return &tls.Config{Certificates: ts.TLS.Certificates}, pool
+}
+
+var testHeader = notifier.MessageHeader{
+	PrName:   "Add widgets",
+	PrNumber: 42,
+	Slug:     "octocat/hello-world",
+	Policy:   "default",
+	NeededApprovers: []*model.Person{
+		{Name: "Carol", Email: "carol@example.com", Login: "carol"},
+		{Name: "Dan", Login: "dan"},
+	},
+}
+
+func TestRecipients(t *testing.T) {
+	to := Recipients(testHeader, []string{
+		"alice@example.com",
+		"Bob <bob@example.com>",
+		"not an address",
+		model.ApproversNeeded,
+		"CAROL@example.com",
+	})
+	var actual []string
+	for _, addr := range to {
+		actual = append(actual, addr.String())
+	}
+	expected := []string{
+		"<alice@example.com>",
+		"\"Bob\" <bob@example.com>",
+		"\"Carol\" <carol@example.com>",
+	}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func testSend(t *testing.T, mode string, tlsConfig *tls.Config, pool *x509.CertPool) *fakeServer {
+	githubUrl = "https://github.com"
+	rootCAs = pool
+	fs := newFakeServer(t, tlsConfig, mode == ModeTLS)
+	server := Server{
+		Host:     "127.0.0.1",
+		Port:     fs.port(),
+		Username: "checks-out",
+		Password: "hunter2",
+		From:     "Checks Out <checks-out@example.com>",
+		TLS:      mode,
+	}
+	to := Recipients(testHeader, []string{"alice@example.com", model.ApproversNeeded})
+	err := server.Send(testHeader, "approval added by bob.\nmerged", to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.wait(t)
+	if fs.from != "FROM:<checks-out@example.com>" {
+		t.Errorf("Unexpected sender %s", fs.from)
+	}
+	if strings.Join(fs.rcpts, ",") != "TO:<alice@example.com>,TO:<carol@example.com>" {
+		t.Errorf("Unexpected recipients %v", fs.rcpts)
+	}
+	if fs.auth != "\x00checks-out\x00hunter2" {
+		t.Errorf("Unexpected auth %q", fs.auth)
+	}
+	return fs
+}
+
+func TestSendNone(t *testing.T) {
+	fs := testSend(t, ModeNone, nil, nil)
+	if fs.secure {
+		t.Error("Expected an insecure connection")
+	}
+}
+
+func TestSendStartTLS(t *testing.T) {
+	tlsConfig, pool := testCertificate()
+	fs := testSend(t, ModeStartTLS, tlsConfig, pool)
+	if !fs.secure {
+		t.Error("Expected STARTTLS to be used")
+	}
+}
+
+func TestSendTLS(t *testing.T) {
+	tlsConfig, pool := testCertificate()
+	fs := testSend(t, ModeTLS, tlsConfig, pool)
+	if !fs.secure {
+		t.Error("Expected TLS to be used")
+	}
+}
+
+func TestSendStartTLSUnsupported(t *testing.T) {
+	fs := newFakeServer(t, nil, false)
+	defer fs.listener.Close()
+	server := Server{
+		Host: "127.0.0.1",
+		Port: fs.port(),
+		From: "checks-out@example.com",
+		TLS:  ModeStartTLS,
+	}
+	to := []*mail.Address{{Address: "alice@example.com"}}
+	err := server.Send(testHeader, "merged", to)
+	if err == nil {
+		t.Error("Expected error when STARTTLS is not supported")
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	githubUrl = "https://github.com"
+	from := &mail.Address{Name: "Checks Out", Address: "checks-out@example.com"}
+	to := []*mail.Address{{Address: "alice@example.com"}}
+	header := testHeader
+	header.PrName = "Add <widgets>"
+	raw, err := BuildMessage(from, to, header, "approval added by bob.\nmerged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "[octocat/hello-world] Pull Request Add <widgets> (#42) in octocat/hello-world" {
+		t.Errorf("Unexpected subject %s", subject)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		body, _ := ioutil.ReadAll(quotedprintable.NewReader(part))
+		parts[strings.SplitN(part.Header.Get("Content-Type"), ";", 2)[0]] = strings.Replace(string(body), "\r\n", "\n", -1)
+	}
+	expectedText := `Pull Request Add <widgets> (#42) in octocat/hello-world
+
+approval added by bob.
+merged
+
+https://github.com/octocat/hello-world/pull/42
+
+Approval policy: default
+
+Approval is still needed from one of:
+- Carol <carol@example.com> (@carol)
+- Dan (@dan)
+
+--
+Sent by checks-out for octocat/hello-world
+`
+	if parts["text/plain"] != expectedText {
+		t.Errorf("Unexpected text body:\n%s", parts["text/plain"])
+	}
+	html := parts["text/html"]
+	for _, expected := range []string{
+		`<a href="https://github.com/octocat/hello-world/pull/42">Pull Request Add &lt;widgets&gt; (#42) in octocat/hello-world</a>`,
+		`<p>merged</p>`,
+		`<li>Carol &lt;carol@example.com&gt; (@carol)</li>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %s in html body:\n%s", expected, html)
+		}
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"context"
 	"fmt"
 
-	log "github.com/Sirupsen/logrus"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/notifier"
 	"github.com/capitalone/checks-out/remote"
@@ -35,24 +34,24 @@ func (ms *MySender) Prefix(mw notifier.MessageWrapper) string {
 	return fmt.Sprintf("Pull Request %s in repo %s: ", mw.MessageHeader.PrName, mw.MessageHeader.Slug)
 }
 
-func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) {
+func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) error {
 	if header.PrNumber <= 0 {
-		return
+		return nil
 	}
 	repo, user, caps, err := web.GetRepoAndUser(c, header.Slug)
 	if err != nil {
-		log.Warnf("Error retrieving GitHub information: %v", err)
-		return
+		return fmt.Errorf("Error retrieving GitHub information: %v", err)
 	}
 	if !caps.Repo.PRWriteComment {
-		return
+		return nil
 	}
 
 	//ignore names
 	err = remote.WriteComment(c, user, repo, header.PrNumber, message)
 	if err != nil {
-		log.Warnf("Error sending GitHub notification: %v", err)
+		return fmt.Errorf("Error sending GitHub notification: %v", err)
 	}
+	return nil
 }
 
 func init() {
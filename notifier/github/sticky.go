@@ -0,0 +1,170 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/web"
+)
+
+const (
+	statusHeading = "#### Status"
+	eventsHeading = "#### Latest events"
+	// maxEvents is the number of events listed
+	// in the sticky comment.
+	maxEvents = 10
+)
+
+var now = time.Now
+
+// SendSticky writes the messages into the sticky status comment of the
+// pull request. The comment is created when it does not exist yet.
+func (ms *MySender) SendSticky(c context.Context, header notifier.MessageHeader, messages []notifier.MessageInfo) error {
+	if header.PrNumber <= 0 {
+		return nil
+	}
+	repo, user, caps, err := web.GetRepoAndUser(c, header.Slug)
+	if err != nil {
+		return fmt.Errorf("Error retrieving GitHub information: %v", err)
+	}
+	if !caps.Repo.PRWriteComment {
+		return nil
+	}
+	id, previous, err := remote.GetStickyComment(c, user, repo, header.PrNumber, model.StickyMarker)
+	if err != nil {
+		return fmt.Errorf("Error retrieving GitHub sticky comment: %v", err)
+	}
+	message := renderSticky(header, messages, previous)
+	if id == 0 {
+		err = remote.WriteComment(c, user, repo, header.PrNumber, message)
+	} else {
+		err = remote.EditComment(c, user, repo, id, message)
+	}
+	if err != nil {
+		return fmt.Errorf("Error writing GitHub sticky comment: %v", err)
+	}
+	return nil
+}
+
+// renderSticky renders the body of the sticky comment. The status
+// of the previous comment is kept when the header has no policy
+// requirements. New events are listed before the previous events.
+func renderSticky(header notifier.MessageHeader, messages []notifier.MessageInfo, previous string) string {
+	var b bytes.Buffer
+	b.WriteString(model.StickyMarker)
+	b.WriteString("\n")
+	status := section(previous, statusHeading, eventsHeading)
+	if len(header.Requirements) > 0 {
+		status = renderStatus(header)
+	}
+	if status != "" {
+		b.WriteString("\n" + statusHeading + "\n\n")
+		b.WriteString(status)
+	}
+	var events []string
+	stamp := now().UTC().Format("2006-01-02 15:04 MST")
+	for i := len(messages) - 1; i >= 0; i-- {
+		mi := messages[i]
+		name := mi.Type.String()
+		if name == "" {
+			name = "message"
+		}
+		text := strings.Join(strings.Fields(mi.Message), " ")
+		events = append(events, fmt.Sprintf("- %s **%s** %s", stamp, name, text))
+	}
+	for _, line := range strings.Split(section(previous, eventsHeading, ""), "\n") {
+		if strings.HasPrefix(line, "- ") {
+			events = append(events, line)
+		}
+	}
+	if len(events) > maxEvents {
+		events = events[:maxEvents]
+	}
+	if len(events) > 0 {
+		b.WriteString("\n" + eventsHeading + "\n\n")
+		b.WriteString(strings.Join(events, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderStatus(header notifier.MessageHeader) string {
+	var b bytes.Buffer
+	if header.Policy != "" {
+		fmt.Fprintf(&b, "Approval policy: `%s`\n\n", header.Policy)
+	}
+	for _, r := range header.Requirements {
+		check := " "
+		if r.Met {
+			check = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", check, r.Description)
+	}
+	var needed []string
+	for _, p := range header.NeededApprovers {
+		if p.Login != "" {
+			needed = append(needed, p.Login)
+		}
+	}
+	people := []struct {
+		title  string
+		logins []string
+	}{
+		{"Approved by", header.Approvers},
+		{"Blocked by", header.Disapprovers},
+		{"Can still approve", needed},
+	}
+	for _, p := range people {
+		if len(p.logins) == 0 {
+			continue
+		}
+		// logins are not mentioned so that each
+		// update does not notify the same people
+		fmt.Fprintf(&b, "\n%s: %s\n", p.title, strings.Join(p.logins, ", "))
+	}
+	return strings.TrimSpace(b.String()) + "\n"
+}
+
+// section returns the text of the comment between
+// the start heading and the end heading.
+func section(body string, start string, end string) string {
+	i := strings.Index(body, start+"\n")
+	if i < 0 {
+		return ""
+	}
+	body = body[i+len(start)+1:]
+	if end != "" {
+		if j := strings.Index(body, end+"\n"); j >= 0 {
+			body = body[:j]
+		}
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return ""
+	}
+	return body + "\n"
+}
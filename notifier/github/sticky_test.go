@@ -0,0 +1,115 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+)
+
+func setupSticky() {
+	now = func() time.Time {
+		return time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC)
+	}
+}
+
+func TestRenderStickyNew(t *testing.T) {
+	setupSticky()
+	header := notifier.MessageHeader{
+		PrName:       "Add widgets",
+		PrNumber:     42,
+		Slug:         "octocat/hello-world",
+		Policy:       "default",
+		Approvers:    []string{"alice"},
+		Disapprovers: []string{"carol"},
+		NeededApprovers: []*model.Person{
+			{Login: "bob"},
+			{Login: "dave"},
+		},
+		Requirements: []notifier.Requirement{
+			{Description: "audit chain is approved", Met: true},
+			{Description: "approval policy is satisfied", Met: false},
+		},
+	}
+	messages := []notifier.MessageInfo{
+		{Message: "approved by alice", Type: model.CommentApprove},
+		{Message: "blocked by\ncarol", Type: model.CommentBlock},
+	}
+	expected := model.StickyMarker + `
+
+#### Status
+
+Approval policy: ` + "`default`" + `
+
+- [x] audit chain is approved
+- [ ] approval policy is satisfied
+
+Approved by: alice
+
+Blocked by: carol
+
+Can still approve: bob, dave
+
+#### Latest events
+
+- 2017-06-01 12:30 UTC **block** blocked by carol
+- 2017-06-01 12:30 UTC **approve** approved by alice
+`
+	actual := renderSticky(header, messages, "")
+	if actual != expected {
+		t.Errorf("Expected\n%s\nbut was\n%s", expected, actual)
+	}
+}
+
+func TestRenderStickyUpdate(t *testing.T) {
+	setupSticky()
+	header := notifier.MessageHeader{
+		Requirements: []notifier.Requirement{
+			{Description: "approval policy is satisfied", Met: true},
+		},
+	}
+	previous := renderSticky(header, []notifier.MessageInfo{
+		{Message: "opened", Type: model.CommentOpen},
+	}, "")
+	// the status is kept when it is not known
+	actual := renderSticky(notifier.MessageHeader{}, []notifier.MessageInfo{
+		{Message: "failed", Type: model.CommentError},
+	}, model.CommentPrefix+" "+previous)
+	if !strings.Contains(actual, "- [x] approval policy is satisfied") {
+		t.Errorf("Expected the previous status to be kept\n%s", actual)
+	}
+	if !strings.Contains(actual, "**error** failed\n- 2017-06-01 12:30 UTC **open** opened\n") {
+		t.Errorf("Expected the new event before the previous event\n%s", actual)
+	}
+	for i := 0; i < maxEvents+5; i++ {
+		actual = renderSticky(header, []notifier.MessageInfo{
+			{Message: "reset", Type: model.CommentReset},
+		}, actual)
+	}
+	if count := strings.Count(actual, "\n- 2017"); count != maxEvents {
+		t.Errorf("Expected %d events but was %d", maxEvents, count)
+	}
+	if strings.Count(actual, model.StickyMarker) != 1 {
+		t.Errorf("Expected a single marker\n%s", actual)
+	}
+}
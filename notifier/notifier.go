@@ -20,22 +20,48 @@ package notifier
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
 )
 
 var (
-	senders = map[model.CommentTarget]Sender{}
+	senders    = map[model.CommentTarget]Sender{}
+	deliveryID = randomID
 )
 
+// Sender delivers messages to a comment target. Send is called
+// by the outbox workers. It returns an error when the delivery
+// failed and should be retried.
 type Sender interface {
-	Send(c context.Context, header MessageHeader, message string, names []string, url string)
+	Send(c context.Context, header MessageHeader, message string, names []string, url string) error
 	Prefix(mw MessageWrapper) string
 }
 
+// EventSender is implemented by senders that deliver each
+// message as a structured event instead of a single text message.
+type EventSender interface {
+	SendEvents(c context.Context, header MessageHeader, messages []MessageInfo, target model.TargetConfig) error
+}
+
+// StickySender is implemented by senders that can keep
+// a single status message of each pull request up to date.
+type StickySender interface {
+	SendSticky(c context.Context, header MessageHeader, messages []MessageInfo) error
+}
+
+// RateLimiter is implemented by senders whose targets limit
+// how often messages can be delivered to the same url.
+type RateLimiter interface {
+	MinInterval() time.Duration
+}
+
 type MessageWrapper struct {
 	MessageHeader
 	Messages []MessageInfo
@@ -53,11 +79,80 @@ type MessageHeader struct {
 	PrName   string
 	PrNumber int
 	Slug     string
+	// Author is the author of the pull request.
+	Author *model.Person
+	// The remaining fields are recorded when they are
+	// known and are used by the structured event senders.
+	Policy          string
+	Approvers       []string
+	Disapprovers    []string
+	NeededApprovers []*model.Person
+	Requirements    []Requirement
+	HeadSHA         string
+	BaseSHA         string
+	MergeSHA        string
+	// Delivery identifies the delivery of the notification to a
+	// target. It is kept when the delivery is retried.
+	Delivery string
+}
+
+// Requirement is a condition of the approval
+// policy and whether it has been met.
+type Requirement struct {
+	Description string
+	Met         bool
 }
 
 type MessageInfo struct {
 	Message string
 	Type    model.CommentMessage
+	// Data is rendered by the message templates of the targets.
+	Data *model.MessageData `json:"-"`
+}
+
+// NewMessage creates a message with the default text of the message type.
+func NewMessage(t model.CommentMessage, data model.MessageData) MessageInfo {
+	text, err := model.DefaultMessage(t, data)
+	if err != nil {
+		log.Warnf("Unable to render default %s message: %v", t, err)
+	}
+	data.Default = text
+	return MessageInfo{
+		Message: text,
+		Type:    t,
+		Data:    &data,
+	}
+}
+
+// Delivery is the content of a message
+// that is sent to one comment target.
+type Delivery struct {
+	Header   MessageHeader
+	Messages []MessageInfo
+	Message  string
+	BaseURL  string `json:",omitempty"`
+	Sticky   bool   `json:",omitempty"`
+	Direct   bool   `json:",omitempty"`
+}
+
+type permanentError struct {
+	error
+}
+
+// Permanent marks an error of a delivery
+// that must not be retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent reports whether the delivery error
+// must not be retried.
+func IsPermanent(err error) bool {
+	_, ok := err.(permanentError)
+	return ok
 }
 
 // Register is called by the init methods of the notification
@@ -78,10 +173,7 @@ func BuildErrorMessage(prName string, prNumber int, slug string, message string)
 			Slug:     slug,
 		},
 		Messages: []MessageInfo{
-			{
-				Message: message,
-				Type:    model.CommentError,
-			},
+			NewMessage(model.CommentError, model.MessageData{Default: message}),
 		},
 	}
 }
@@ -116,10 +208,13 @@ func SendMessage(c context.Context, config *model.Config, mw MessageWrapper) {
 		if v.Pattern != nil && !v.Pattern.Regex.MatchString(mw.PrName) {
 			continue
 		}
+		var infos []MessageInfo
 		var messages []string
 		for _, mi := range mw.Messages {
 			//check if the message type matches
 			if hasMessageType(mi, v) {
+				mi.Message = renderMessage(v, mw.MessageHeader, mi)
+				infos = append(infos, mi)
 				messages = append(messages, mi.Message)
 			}
 		}
@@ -127,7 +222,81 @@ func SendMessage(c context.Context, config *model.Config, mw MessageWrapper) {
 			continue
 		}
 		message := strings.Join(messages, "\n")
-		message = fmt.Sprintf("%s%s", sender.Prefix(mw), message)
-		sender.Send(c, mw.MessageHeader, message, v.Names, v.Url)
+		d := &Delivery{
+			Header:   mw.MessageHeader,
+			Messages: infos,
+			Message:  fmt.Sprintf("%s%s", sender.Prefix(mw), message),
+			Sticky:   v.Sticky,
+			Direct:   v.Direct,
+		}
+		d.Header.Delivery = deliveryID()
+		if baseURL, ok := c.Value("BASE_URL").(string); ok {
+			d.BaseURL = baseURL
+		}
+		enqueue(c, v, d)
+	}
+}
+
+// renderMessage applies the message template of the target.
+// The default text is used if the target has no template.
+func renderMessage(target model.TargetConfig, header MessageHeader, mi MessageInfo) string {
+	text, ok := target.Templates[mi.Type.String()]
+	if !ok {
+		return mi.Message
+	}
+	var data model.MessageData
+	if mi.Data != nil {
+		data = *mi.Data
+	}
+	data.Slug = header.Slug
+	data.PullRequest.Number = header.PrNumber
+	data.PullRequest.Title = header.PrName
+	if data.Approvers == nil {
+		data.Approvers = header.Approvers
+	}
+	if data.Policy == "" {
+		data.Policy = header.Policy
+	}
+	if data.Default == "" {
+		data.Default = mi.Message
+	}
+	message, err := model.RenderMessage(text, data)
+	if err != nil {
+		log.Warnf("Unable to render %s template of the %s target: %v", mi.Type, target.Target, err)
+		return mi.Message
+	}
+	return message
+}
+
+func deliver(c context.Context, target model.TargetConfig, d *Delivery) error {
+	err := send(c, target, d)
+	if err != nil {
+		metrics.Notifications.Inc(target.Target, "failed")
+	} else {
+		metrics.Notifications.Inc(target.Target, "delivered")
+	}
+	return err
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func send(c context.Context, target model.TargetConfig, d *Delivery) error {
+	sender, ok := senders[model.ToCommentTarget(target.Target)]
+	if !ok {
+		return Permanent(fmt.Errorf("Unregistered sender %s", target.Target))
+	}
+	if ss, ok := sender.(StickySender); ok && d.Sticky {
+		return ss.SendSticky(c, d.Header, d.Messages)
+	}
+	if es, ok := sender.(EventSender); ok {
+		return es.SendEvents(c, d.Header, d.Messages, target)
 	}
+	return sender.Send(c, d.Header, d.Message, target.Names, target.Url)
 }
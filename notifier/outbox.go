@@ -0,0 +1,326 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+var (
+	// outboxRunning is set while the outbox workers are running.
+	// Otherwise notifications are delivered immediately.
+	outboxRunning int32
+	// wakeup signals the dispatcher that a message was queued.
+	wakeup  = make(chan struct{}, 1)
+	limiter = &rateLimiter{next: make(map[string]time.Time)}
+
+	pollInterval = time.Second
+	// leaseDuration is how long a dispatched message is hidden
+	// from the dispatcher. A message whose worker is interrupted
+	// is delivered again after the lease expires.
+	leaseDuration = 5 * time.Minute
+	maxBackoff    = time.Hour
+	now           = time.Now
+)
+
+// StartOutbox starts the dispatcher and the workers that deliver
+// the queued notifications. The context must carry the store,
+// remote and cache that are used by the senders. The dispatcher stops
+// when stop is closed. Each message is delivered on a context that is
+// not cancelled by stop and expires with its lease. The returned channel
+// is closed when the workers have finished the messages they were
+// delivering.
+func StartOutbox(c context.Context, stop <-chan struct{}, workers int) <-chan struct{} {
+	done := make(chan struct{})
+	if workers <= 0 {
+		close(done)
+		return done
+	}
+	jobs := make(chan *model.OutboxMessage)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range jobs {
+				mc, cancel := context.WithTimeout(c, leaseDuration)
+				process(mc, msg)
+				cancel()
+			}
+		}()
+	}
+	atomic.StoreInt32(&outboxRunning, 1)
+	go func() {
+		defer close(done)
+		dispatch(c, stop, jobs, workers)
+		atomic.StoreInt32(&outboxRunning, 0)
+		close(jobs)
+		wg.Wait()
+	}()
+	return done
+}
+
+func enqueue(c context.Context, target model.TargetConfig, d *Delivery) {
+	if atomic.LoadInt32(&outboxRunning) == 0 {
+		deliverNow(c, target, d)
+		return
+	}
+	msg, err := newOutboxMessage(target, d)
+	if err == nil {
+		err = store.CreateOutboxMessage(c, msg)
+	}
+	if err != nil {
+		log.Warnf("Unable to queue %s notification, delivering immediately: %v", target.Target, err)
+		deliverNow(c, target, d)
+		return
+	}
+	wake()
+}
+
+func deliverNow(c context.Context, target model.TargetConfig, d *Delivery) {
+	err := deliver(c, target, d)
+	if err != nil {
+		log.Warnf("Error sending %s notification: %v", target.Target, err)
+	}
+}
+
+func wake() {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func dispatch(c context.Context, stop <-chan struct{}, jobs chan<- *model.OutboxMessage, batch int) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		dispatchDue(c, stop, jobs, batch)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-wakeup:
+		}
+	}
+}
+
+// dispatchDue leases the messages that are due
+// and hands them to the workers.
+func dispatchDue(c context.Context, stop <-chan struct{}, jobs chan<- *model.OutboxMessage, batch int) {
+	for {
+		cur := now()
+		msgs, err := store.GetDueOutboxMessages(c, cur.Unix(), batch)
+		if err != nil {
+			log.Warnf("Unable to read the notification outbox: %v", err)
+			return
+		}
+		if len(msgs) == 0 {
+			return
+		}
+		for _, msg := range msgs {
+			lease := cur.Add(leaseDuration).Unix()
+			ok, err := store.ClaimOutboxMessage(c, msg.ID, msg.NextAttempt, lease)
+			if err != nil {
+				log.Warnf("Unable to lease notification %d: %v", msg.ID, err)
+				return
+			}
+			if !ok {
+				// leased by another dispatcher
+				continue
+			}
+			msg.NextAttempt = lease
+			select {
+			case jobs <- msg:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+// process delivers a message. Delivered messages are removed
+// from the outbox. Failed messages are retried with exponential
+// backoff until they run out of attempts.
+func process(c context.Context, msg *model.OutboxMessage) {
+	target, d, err := decodeOutboxMessage(msg)
+	if err == nil {
+		target.Secret, err = targetSecret(c, target)
+	}
+	if err == nil {
+		limiter.wait(target)
+		dc := c
+		if d.BaseURL != "" {
+			dc = context.WithValue(c, "BASE_URL", d.BaseURL)
+		}
+		err = deliver(dc, target, d)
+	}
+	cur := now()
+	if err == nil {
+		err = store.DeleteOutboxMessage(c, msg.ID)
+		if err != nil {
+			log.Warnf("Unable to remove delivered notification %d: %v", msg.ID, err)
+		}
+		return
+	}
+	msg.Attempts++
+	msg.LastError = err.Error()
+	msg.Updated = cur.Unix()
+	if IsPermanent(err) || msg.Attempts >= envvars.Env.Notify.MaxAttempts {
+		msg.State = model.OutboxDead
+		metrics.NotificationsDead.Inc(msg.Target)
+		log.Warnf("Notification %d to %s failed after %d attempts: %v", msg.ID, msg.Target, msg.Attempts, err)
+	} else {
+		msg.NextAttempt = cur.Add(backoff(msg.Attempts)).Unix()
+		log.Debugf("Notification %d to %s failed, retrying: %v", msg.ID, msg.Target, err)
+	}
+	err = store.UpdateOutboxMessage(c, msg)
+	if err != nil {
+		log.Warnf("Unable to update notification %d: %v", msg.ID, err)
+	}
+}
+
+// RetryOutboxMessage returns a dead message to the
+// outbox for immediate delivery.
+func RetryOutboxMessage(c context.Context, msg *model.OutboxMessage) error {
+	cur := now().Unix()
+	msg.State = model.OutboxPending
+	msg.Attempts = 0
+	msg.NextAttempt = cur
+	msg.Updated = cur
+	err := store.UpdateOutboxMessage(c, msg)
+	if err == nil {
+		wake()
+	}
+	return err
+}
+
+func backoff(attempts int) time.Duration {
+	d := envvars.Env.Notify.Backoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+func newOutboxMessage(target model.TargetConfig, d *Delivery) (*model.OutboxMessage, error) {
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	cur := now().Unix()
+	return &model.OutboxMessage{
+		Target:      target.Target,
+		Url:         target.Url,
+		Host:        target.Host,
+		User:        target.User,
+		Names:       target.Names,
+		Slug:        d.Header.Slug,
+		Payload:     string(payload),
+		State:       model.OutboxPending,
+		NextAttempt: cur,
+		Created:     cur,
+		Updated:     cur,
+	}, nil
+}
+
+func decodeOutboxMessage(msg *model.OutboxMessage) (model.TargetConfig, *Delivery, error) {
+	target := model.TargetConfig{
+		Target: msg.Target,
+		Names:  msg.Names,
+		Url:    msg.Url,
+		Host:   msg.Host,
+		User:   msg.User,
+	}
+	d := new(Delivery)
+	err := json.Unmarshal([]byte(msg.Payload), d)
+	if err != nil {
+		return target, nil, Permanent(err)
+	}
+	target.Sticky = d.Sticky
+	target.Direct = d.Direct
+	return target, d, nil
+}
+
+// targetSecret looks up the signing secret of a webhook target.
+// Secrets are read from the server configuration when the message
+// is delivered and are never stored in the outbox.
+func targetSecret(c context.Context, target model.TargetConfig) (string, error) {
+	if target.Target != model.Webhook.String() {
+		return "", nil
+	}
+	if target.Host == "" {
+		return envvars.Env.Webhook.Secret, nil
+	}
+	webhook, err := store.GetWebhookTarget(c, target.Host, target.User)
+	if err == nil && webhook == nil {
+		webhook, err = store.GetWebhookTarget(c, target.Host, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	if webhook == nil || webhook.Url != target.Url {
+		return "", Permanent(fmt.Errorf("Webhook %s is no longer registered with url %s", target.Host, target.Url))
+	}
+	return webhook.Secret, nil
+}
+
+// rateLimiter spaces out the deliveries to
+// each url of the rate limited senders.
+type rateLimiter struct {
+	sync.Mutex
+	next map[string]time.Time
+}
+
+func (r *rateLimiter) wait(target model.TargetConfig) {
+	rl, ok := senders[model.ToCommentTarget(target.Target)].(RateLimiter)
+	if !ok {
+		return
+	}
+	// each name is delivered as a separate message
+	count := len(target.Names)
+	if count == 0 {
+		count = 1
+	}
+	key := target.Target + " " + target.Url
+	r.Lock()
+	cur := now()
+	next := r.next[key]
+	if next.Before(cur) {
+		next = cur
+	}
+	r.next[key] = next.Add(time.Duration(count) * rl.MinInterval())
+	r.Unlock()
+	time.Sleep(next.Sub(cur))
+}
@@ -0,0 +1,327 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+)
+
+type fakeOutboxStore struct {
+	store.Store
+	sync.Mutex
+	nextID int64
+	msgs   map[int64]*model.OutboxMessage
+}
+
+func newFakeOutboxStore() *fakeOutboxStore {
+	return &fakeOutboxStore{msgs: make(map[int64]*model.OutboxMessage)}
+}
+
+func (fs *fakeOutboxStore) CreateOutboxMessage(msg *model.OutboxMessage) error {
+	fs.Lock()
+	defer fs.Unlock()
+	fs.nextID++
+	msg.ID = fs.nextID
+	cp := *msg
+	fs.msgs[msg.ID] = &cp
+	return nil
+}
+
+func (fs *fakeOutboxStore) GetDueOutboxMessages(now int64, limit int) ([]*model.OutboxMessage, error) {
+	fs.Lock()
+	defer fs.Unlock()
+	var result []*model.OutboxMessage
+	for id := int64(1); id <= fs.nextID && len(result) < limit; id++ {
+		msg, ok := fs.msgs[id]
+		if ok && msg.State == model.OutboxPending && msg.NextAttempt <= now {
+			cp := *msg
+			result = append(result, &cp)
+		}
+	}
+	return result, nil
+}
+
+func (fs *fakeOutboxStore) ClaimOutboxMessage(id int64, due int64, lease int64) (bool, error) {
+	fs.Lock()
+	defer fs.Unlock()
+	msg, ok := fs.msgs[id]
+	if !ok || msg.State != model.OutboxPending || msg.NextAttempt != due {
+		return false, nil
+	}
+	msg.NextAttempt = lease
+	return true, nil
+}
+
+func (fs *fakeOutboxStore) UpdateOutboxMessage(msg *model.OutboxMessage) error {
+	fs.Lock()
+	defer fs.Unlock()
+	cp := *msg
+	fs.msgs[msg.ID] = &cp
+	return nil
+}
+
+func (fs *fakeOutboxStore) DeleteOutboxMessage(id int64) error {
+	fs.Lock()
+	defer fs.Unlock()
+	delete(fs.msgs, id)
+	return nil
+}
+
+func (fs *fakeOutboxStore) get(id int64) *model.OutboxMessage {
+	fs.Lock()
+	defer fs.Unlock()
+	return fs.msgs[id]
+}
+
+type fakeSender struct {
+	errs []error
+	sent chan string
+}
+
+func (fs *fakeSender) Prefix(mw MessageWrapper) string {
+	return "prefix: "
+}
+
+func (fs *fakeSender) Send(c context.Context, header MessageHeader, message string, names []string, url string) error {
+	if len(fs.errs) > 0 {
+		err := fs.errs[0]
+		fs.errs = fs.errs[1:]
+		return err
+	}
+	fs.sent <- message
+	return nil
+}
+
+func setupOutbox(t *testing.T, sender *fakeSender) (*fakeOutboxStore, context.Context) {
+	old := senders
+	senders = map[model.CommentTarget]Sender{model.Slack: sender}
+	t.Cleanup(func() { senders = old })
+	envvars.Env.Notify.MaxAttempts = 3
+	envvars.Env.Notify.Backoff = time.Second
+	fs := newFakeOutboxStore()
+	return fs, store.AddToContext(context.Background(), fs)
+}
+
+func testConfig() *model.Config {
+	config := model.NonEmptyConfig()
+	config.Comment.Enable = true
+	config.Comment.Targets = []model.TargetConfig{
+		{Target: "slack", Names: []string{"general"}, Url: "https://example.com"},
+	}
+	return config
+}
+
+func TestProcessRetries(t *testing.T) {
+	sender := &fakeSender{
+		errs: []error{errors.New("unavailable")},
+		sent: make(chan string, 1),
+	}
+	fs, c := setupOutbox(t, sender)
+	msg, err := newOutboxMessage(testConfig().Comment.Targets[0], &Delivery{Message: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.CreateOutboxMessage(msg)
+
+	process(c, msg)
+	failed := fs.get(msg.ID)
+	if failed.State != model.OutboxPending || failed.Attempts != 1 || failed.LastError != "unavailable" {
+		t.Fatalf("Unexpected message after failure %+v", failed)
+	}
+	if failed.NextAttempt <= failed.Updated {
+		t.Errorf("Expected retry to be delayed")
+	}
+
+	process(c, failed)
+	if fs.get(msg.ID) != nil {
+		t.Error("Expected delivered message to be removed")
+	}
+	if sent := <-sender.sent; sent != "hello" {
+		t.Errorf("Unexpected message %s", sent)
+	}
+}
+
+func TestProcessDeadLetter(t *testing.T) {
+	sender := &fakeSender{
+		errs: []error{Permanent(errors.New("bad request"))},
+	}
+	fs, c := setupOutbox(t, sender)
+	msg, _ := newOutboxMessage(testConfig().Comment.Targets[0], &Delivery{Message: "hello"})
+	fs.CreateOutboxMessage(msg)
+	process(c, msg)
+	if dead := fs.get(msg.ID); dead.State != model.OutboxDead || dead.Attempts != 1 {
+		t.Errorf("Expected permanent error to dead-letter the message %+v", dead)
+	}
+
+	err := RetryOutboxMessage(c, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry := fs.get(msg.ID); retry.State != model.OutboxPending || retry.Attempts != 0 {
+		t.Errorf("Expected retried message to be pending %+v", retry)
+	}
+}
+
+func TestProcessMaxAttempts(t *testing.T) {
+	sender := &fakeSender{
+		errs: []error{errors.New("1"), errors.New("2"), errors.New("3")},
+	}
+	fs, c := setupOutbox(t, sender)
+	msg, _ := newOutboxMessage(testConfig().Comment.Targets[0], &Delivery{Message: "hello"})
+	fs.CreateOutboxMessage(msg)
+	for i := 0; i < 3; i++ {
+		process(c, fs.get(msg.ID))
+	}
+	if dead := fs.get(msg.ID); dead.State != model.OutboxDead || dead.Attempts != 3 {
+		t.Errorf("Expected message to be dead-lettered after 3 attempts %+v", dead)
+	}
+}
+
+// racingOutboxStore claims every due message on behalf
+// of another replica right after it has been read.
+type racingOutboxStore struct {
+	*fakeOutboxStore
+}
+
+func (rs racingOutboxStore) GetDueOutboxMessages(now int64, limit int) ([]*model.OutboxMessage, error) {
+	msgs, err := rs.fakeOutboxStore.GetDueOutboxMessages(now, limit)
+	for _, msg := range msgs {
+		rs.ClaimOutboxMessage(msg.ID, msg.NextAttempt, now+60)
+	}
+	return msgs, err
+}
+
+func TestDispatchDueClaimed(t *testing.T) {
+	fs, _ := setupOutbox(t, &fakeSender{})
+	c := store.AddToContext(context.Background(), racingOutboxStore{fs})
+	msg, _ := newOutboxMessage(testConfig().Comment.Targets[0], &Delivery{Message: "hello"})
+	fs.CreateOutboxMessage(msg)
+	jobs := make(chan *model.OutboxMessage, 1)
+	dispatchDue(c, nil, jobs, 1)
+	if len(jobs) != 0 {
+		t.Error("Message claimed by another replica should not be dispatched")
+	}
+}
+
+func TestTargetSecret(t *testing.T) {
+	fs, c := setupOutbox(t, &fakeSender{})
+	c = store.AddToContext(c, webhookStore{fs})
+	envvars.Env.Webhook.Secret = "default"
+	defer func() { envvars.Env.Webhook.Secret = "" }()
+	target := model.TargetConfig{Target: "webhook", Url: "https://example.com/hook", Host: "ci", User: "alice"}
+	msg, _ := newOutboxMessage(target, &Delivery{Message: "hello"})
+	decoded, _, _ := decodeOutboxMessage(msg)
+	if secret, err := targetSecret(c, decoded); err != nil || secret != "s3cret" {
+		t.Errorf("Expected the secret of the registered webhook but was %q %v", secret, err)
+	}
+	decoded.Host = ""
+	if secret, _ := targetSecret(c, decoded); secret != "default" {
+		t.Errorf("Expected the default webhook secret but was %q", secret)
+	}
+	decoded.Host = "ci"
+	decoded.Url = "https://example.com/other"
+	if _, err := targetSecret(c, decoded); !IsPermanent(err) {
+		t.Errorf("Expected error when the webhook url has changed but was %v", err)
+	}
+}
+
+// webhookStore has an admin-level webhook registered as ci.
+type webhookStore struct {
+	*fakeOutboxStore
+}
+
+func (ws webhookStore) GetWebhookTarget(hostname string, user string) (*model.WebhookTarget, error) {
+	if hostname == "ci" && user == "" {
+		return &model.WebhookTarget{Url: "https://example.com/hook", Secret: "s3cret"}, nil
+	}
+	return nil, nil
+}
+
+func TestBackoff(t *testing.T) {
+	envvars.Env.Notify.Backoff = time.Second
+	data := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{20, maxBackoff},
+	}
+	for _, d := range data {
+		if actual := backoff(d.attempts); actual != d.expected {
+			t.Errorf("Expected backoff %v after %d attempts but was %v", d.expected, d.attempts, actual)
+		}
+	}
+}
+
+func TestSendMessageOutbox(t *testing.T) {
+	sender := &fakeSender{sent: make(chan string, 1)}
+	_, c := setupOutbox(t, sender)
+	stop := make(chan struct{})
+	stopped := StartOutbox(c, stop, 1)
+	defer func() {
+		close(stop)
+		<-stopped
+		if atomic.LoadInt32(&outboxRunning) != 0 {
+			t.Error("Expected the outbox to be stopped")
+		}
+	}()
+
+	mw := BuildErrorMessage("feature", 1, "octocat/hello-world", "failed")
+	SendMessage(c, testConfig(), mw)
+	select {
+	case sent := <-sender.sent:
+		if sent != "prefix: failed" {
+			t.Errorf("Unexpected message %s", sent)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Message was not delivered by the outbox")
+	}
+}
+
+func TestSendMessageTemplate(t *testing.T) {
+	sender := &fakeSender{sent: make(chan string, 2)}
+	_, c := setupOutbox(t, sender)
+	config := testConfig()
+	config.Comment.Targets[0].Templates = map[string]string{
+		"approve": "{{.Actor}} approved #{{.PullRequest.Number}} in {{.Slug}}",
+	}
+	mw := MessageWrapper{
+		MessageHeader: MessageHeader{PrName: "feature", PrNumber: 1, Slug: "octocat/hello-world"},
+		Messages: []MessageInfo{
+			NewMessage(model.CommentApprove, model.MessageData{Actor: "alice"}),
+			NewMessage(model.CommentBlock, model.MessageData{Actor: "bob", Reason: model.ReasonComment}),
+		},
+	}
+	SendMessage(c, config, mw)
+	expected := "prefix: alice approved #1 in octocat/hello-world\nblocked by bob."
+	if sent := <-sender.sent; sent != expected {
+		t.Errorf("Expected %q but was %q", expected, sent)
+	}
+}
@@ -22,9 +22,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/model"
@@ -33,8 +35,13 @@ import (
 	log "github.com/Sirupsen/logrus"
 )
 
+// RecheckAction is the action id of the button that rechecks
+// the approval status of a pull request.
+const RecheckAction = "recheck"
+
 var (
 	githubUrl  = envvars.Env.Github.Url
+	slackApi   = "https://slack.com/api"
 	httpClient = &http.Client{}
 )
 
@@ -57,10 +64,19 @@ func (ms *MySender) Prefix(mw notifier.MessageWrapper) string {
 		mw.MessageHeader.Slug)
 }
 
-func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) {
+// MinInterval spaces out the messages to a Slack webhook,
+// which accepts about one message per second.
+func (ms *MySender) MinInterval() time.Duration {
+	return time.Second
+}
+
+func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) error {
+	return ms.send(c, message, nil, names, url)
+}
+
+func (ms *MySender) send(c context.Context, message string, blocks []interface{}, names []string, url string) error {
 	if url == "" {
-		log.Warn("Error sending to Slack: SLACK_TARGET_URL is not configured")
-		return
+		return notifier.Permanent(errors.New("SLACK_TARGET_URL is not configured"))
 	}
 
 	baseURL := c.Value("BASE_URL")
@@ -70,15 +86,18 @@ func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, messa
 	}
 
 	d := struct {
-		Channel  string `json:"channel"`
-		Text     string `json:"text"`
-		Username string `json:"username"`
-		IconURL  string `json:"icon_url"`
+		Channel  string        `json:"channel"`
+		Text     string        `json:"text"`
+		Blocks   []interface{} `json:"blocks,omitempty"`
+		Username string        `json:"username"`
+		IconURL  string        `json:"icon_url"`
 	}{
 		Text:     message,
+		Blocks:   blocks,
 		Username: "Meowser",
 		IconURL:  iconURL,
 	}
+	var failed error
 	for _, name := range names {
 		d.Channel = name
 		output, err := toJson(d)
@@ -86,14 +105,190 @@ func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, messa
 			log.Warnf("Unable to convert notification to JSON: %v", err)
 			continue
 		}
-		go func(msg string) {
-			//write to the slack channel
-			_, err := httpClient.Post(url, "application/json", strings.NewReader(msg))
-			if err != nil {
-				log.Warnf("Error while writing %s to %s: %v", msg, url, err)
+		//write to the slack channel
+		err = post(url, output)
+		if err != nil {
+			log.Warnf("Error while writing %s to %s: %v", output, url, err)
+			failed = err
+		}
+	}
+	return failed
+}
+
+// SendEvents sends the messages with mentions of the people who should act
+// on them. If the target sends direct messages then the message is sent
+// to each mentioned person instead of the channels of the target.
+func (ms *MySender) SendEvents(c context.Context, header notifier.MessageHeader, messages []notifier.MessageInfo, target model.TargetConfig) error {
+	var texts []string
+	for _, mi := range messages {
+		texts = append(texts, mi.Message)
+	}
+	message := ms.Prefix(notifier.MessageWrapper{MessageHeader: header}) + strings.Join(texts, "\n")
+	var ids []string
+	for _, p := range Mentions(header, messages) {
+		ids = append(ids, p.Slack)
+	}
+	// messages that mention nobody are sent to the channels of the target
+	if target.Direct && len(ids) > 0 {
+		return ms.sendDirect(c, message, Buttons(header, messages, message), ids, target.Url)
+	}
+	if len(ids) > 0 {
+		message += "\ncc <@" + strings.Join(ids, "> <@") + ">"
+	}
+	return ms.send(c, message, Buttons(header, messages, message), target.Names, target.Url)
+}
+
+// Buttons returns the Slack blocks that show the message with buttons
+// to open the pull request and to recheck its approval status. The
+// blocks are only returned for the messages that ask for approvals.
+// The recheck button requires SLACK_SIGNING_SECRET to receive the
+// interactive requests from Slack.
+func Buttons(header notifier.MessageHeader, messages []notifier.MessageInfo, text string) []interface{} {
+	asks := false
+	for _, mi := range messages {
+		if mi.Type == model.CommentOpen || mi.Type == model.CommentReset {
+			asks = true
+		}
+	}
+	if !asks || header.Slug == "" || header.PrNumber == 0 {
+		return nil
+	}
+	buttons := []interface{}{
+		map[string]interface{}{
+			"type":      "button",
+			"action_id": "open",
+			"text":      map[string]string{"type": "plain_text", "text": "Open PR"},
+			"url":       fmt.Sprintf("%s/%s/pull/%d", githubUrl, header.Slug, header.PrNumber),
+		},
+	}
+	if envvars.Env.Slack.SigningSecret != "" {
+		buttons = append(buttons, map[string]interface{}{
+			"type":      "button",
+			"action_id": RecheckAction,
+			"text":      map[string]string{"type": "plain_text", "text": "Recheck"},
+			"value":     fmt.Sprintf("%s#%d", header.Slug, header.PrNumber),
+		})
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		},
+		map[string]interface{}{
+			"type":     "actions",
+			"elements": buttons,
+		},
+	}
+}
+
+// Mentions returns the people with a Slack user id who should act on the
+// messages. The pull request author is mentioned when the pull request
+// is blocked or merged. The people who can still approve the pull
+// request are mentioned when it is opened or reset.
+func Mentions(header notifier.MessageHeader, messages []notifier.MessageInfo) []*model.Person {
+	var people []*model.Person
+	seen := make(map[string]bool)
+	add := func(p *model.Person) {
+		if p == nil || p.Slack == "" || seen[p.Slack] {
+			return
+		}
+		seen[p.Slack] = true
+		people = append(people, p)
+	}
+	for _, mi := range messages {
+		switch mi.Type {
+		case model.CommentBlock, model.CommentMerge, model.CommentAccept:
+			add(header.Author)
+		case model.CommentOpen, model.CommentReset:
+			for _, p := range header.NeededApprovers {
+				add(p)
 			}
-		}(output)
+		}
+	}
+	return people
+}
+
+// sendDirect sends the message to each Slack user. Messages are sent by the
+// bot user when SLACK_BOT_TOKEN is defined. Otherwise the webhook of the
+// target is asked to deliver the message to the user.
+func (ms *MySender) sendDirect(c context.Context, message string, blocks []interface{}, ids []string, url string) error {
+	token := envvars.Env.Slack.BotToken
+	if token == "" {
+		return ms.send(c, message, blocks, ids, url)
+	}
+	var failed error
+	for _, id := range ids {
+		err := postMessage(token, id, message, blocks)
+		if err != nil {
+			log.Warnf("Error while sending direct message to %s: %v", id, err)
+			failed = err
+		}
+	}
+	return failed
+}
+
+// postMessage sends a message with the chat.postMessage method
+// of the Slack Web API. A user id as the channel sends a direct
+// message from the bot user.
+func postMessage(token string, channel string, text string, blocks []interface{}) error {
+	msg, err := toJson(struct {
+		Channel string        `json:"channel"`
+		Text    string        `json:"text"`
+		Blocks  []interface{} `json:"blocks,omitempty"`
+	}{channel, text, blocks})
+	if err != nil {
+		return notifier.Permanent(err)
+	}
+	req, err := http.NewRequest("POST", slackApi+"/chat.postMessage", strings.NewReader(msg))
+	if err != nil {
+		return notifier.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	err = checkStatus(resp)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return err
+	}
+	switch {
+	case result.Ok:
+		return nil
+	case result.Error == "ratelimited":
+		return fmt.Errorf("Slack returned %s", result.Error)
+	default:
+		return notifier.Permanent(fmt.Errorf("Slack returned %s", result.Error))
+	}
+}
+
+func post(url string, msg string) error {
+	resp, err := httpClient.Post(url, "application/json", strings.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp)
+}
+
+func checkStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("Slack returned %s", resp.Status)
+	case resp.StatusCode >= 400:
+		return notifier.Permanent(fmt.Errorf("Slack returned %s", resp.Status))
 	}
+	return nil
 }
 
 func toJson(s interface{}) (string, error) {
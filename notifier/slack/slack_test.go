@@ -0,0 +1,197 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+)
+
+var testHeader = notifier.MessageHeader{
+	PrName:   "Add widgets",
+	PrNumber: 42,
+	Slug:     "octocat/hello-world",
+	Author:   &model.Person{Login: "alice", Slack: "U0ALICE"},
+	NeededApprovers: []*model.Person{
+		{Login: "bob", Slack: "U0BOB"},
+		{Login: "carol"},
+	},
+}
+
+type slackMessage struct {
+	Channel string                   `json:"channel"`
+	Text    string                   `json:"text"`
+	Blocks  []map[string]interface{} `json:"blocks"`
+}
+
+type received struct {
+	sync.Mutex
+	auth     []string
+	messages []slackMessage
+}
+
+func (r *received) handler(w http.ResponseWriter, req *http.Request) {
+	var msg slackMessage
+	json.NewDecoder(req.Body).Decode(&msg)
+	r.Lock()
+	r.auth = append(r.auth, req.Header.Get("Authorization"))
+	r.messages = append(r.messages, msg)
+	r.Unlock()
+	w.Write([]byte(`{"ok":true}`))
+}
+
+func TestMentions(t *testing.T) {
+	data := []struct {
+		msgType  model.CommentMessage
+		expected []string
+	}{
+		{model.CommentBlock, []string{"alice"}},
+		{model.CommentMerge, []string{"alice"}},
+		{model.CommentOpen, []string{"bob"}},
+		{model.CommentReset, []string{"bob"}},
+		{model.CommentApprove, nil},
+	}
+	for _, d := range data {
+		var actual []string
+		for _, p := range Mentions(testHeader, []notifier.MessageInfo{{Type: d.msgType}}) {
+			actual = append(actual, p.Login)
+		}
+		if strings.Join(actual, ",") != strings.Join(d.expected, ",") {
+			t.Errorf("Expected %s message to mention %v but was %v", d.msgType, d.expected, actual)
+		}
+	}
+}
+
+func TestSendEventsChannel(t *testing.T) {
+	githubUrl = "https://github.com"
+	var r received
+	ts := httptest.NewServer(http.HandlerFunc(r.handler))
+	defer ts.Close()
+	sender := &MySender{}
+	err := sender.SendEvents(context.Background(), testHeader, []notifier.MessageInfo{
+		{Message: "opened", Type: model.CommentOpen},
+		{Message: "blocked by dave.", Type: model.CommentBlock},
+	}, model.TargetConfig{Target: "slack", Names: []string{"#dev"}, Url: ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.messages) != 1 || r.messages[0].Channel != "#dev" {
+		t.Fatalf("Expected a message to #dev but was %v", r.messages)
+	}
+	if !strings.HasSuffix(r.messages[0].Text, "opened\nblocked by dave.\ncc <@U0BOB> <@U0ALICE>") {
+		t.Errorf("Unexpected message %s", r.messages[0].Text)
+	}
+	if len(r.messages[0].Blocks) != 2 {
+		t.Errorf("Expected message with buttons but was %v", r.messages[0].Blocks)
+	}
+}
+
+func TestButtons(t *testing.T) {
+	githubUrl = "https://github.com"
+	messages := []notifier.MessageInfo{{Message: "opened", Type: model.CommentOpen}}
+	blocks := Buttons(testHeader, messages, "opened")
+	if len(blocks) != 2 {
+		t.Fatalf("Expected section and actions blocks but was %v", blocks)
+	}
+	buttons := blocks[1].(map[string]interface{})["elements"].([]interface{})
+	if len(buttons) != 1 {
+		t.Errorf("Expected only the open button without a signing secret but was %v", buttons)
+	}
+	if url := buttons[0].(map[string]interface{})["url"]; url != "https://github.com/octocat/hello-world/pull/42" {
+		t.Errorf("Unexpected pull request url %v", url)
+	}
+
+	envvars.Env.Slack.SigningSecret = "secret"
+	defer func() { envvars.Env.Slack.SigningSecret = "" }()
+	blocks = Buttons(testHeader, messages, "opened")
+	buttons = blocks[1].(map[string]interface{})["elements"].([]interface{})
+	if len(buttons) != 2 || buttons[1].(map[string]interface{})["value"] != "octocat/hello-world#42" {
+		t.Errorf("Expected recheck button for octocat/hello-world#42 but was %v", buttons)
+	}
+
+	blocks = Buttons(testHeader, []notifier.MessageInfo{{Message: "merged", Type: model.CommentMerge}}, "merged")
+	if blocks != nil {
+		t.Errorf("Expected no buttons for merge message but was %v", blocks)
+	}
+}
+
+func TestSendEventsDirect(t *testing.T) {
+	var r received
+	ts := httptest.NewServer(http.HandlerFunc(r.handler))
+	defer ts.Close()
+	slackApi = ts.URL
+	envvars.Env.Slack.BotToken = "xoxb-test"
+	defer func() { envvars.Env.Slack.BotToken = "" }()
+	sender := &MySender{}
+	err := sender.SendEvents(context.Background(), testHeader, []notifier.MessageInfo{
+		{Message: "opened", Type: model.CommentOpen},
+	}, model.TargetConfig{Target: "slack", Names: []string{"#dev"}, Direct: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.messages) != 1 || r.messages[0].Channel != "U0BOB" {
+		t.Fatalf("Expected a direct message to bob but was %v", r.messages)
+	}
+	if r.auth[0] != "Bearer xoxb-test" {
+		t.Errorf("Unexpected authorization %s", r.auth[0])
+	}
+	if strings.Contains(r.messages[0].Text, "<@") {
+		t.Errorf("Direct message should not mention the recipient %s", r.messages[0].Text)
+	}
+}
+
+func TestSendEventsDirectNoMentions(t *testing.T) {
+	var r received
+	ts := httptest.NewServer(http.HandlerFunc(r.handler))
+	defer ts.Close()
+	slackApi = ts.URL
+	envvars.Env.Slack.BotToken = "xoxb-test"
+	defer func() { envvars.Env.Slack.BotToken = "" }()
+	sender := &MySender{}
+	err := sender.SendEvents(context.Background(), testHeader, []notifier.MessageInfo{
+		{Message: "approval added by bob.", Type: model.CommentApprove},
+	}, model.TargetConfig{Target: "slack", Names: []string{"#dev"}, Url: ts.URL, Direct: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.messages) != 1 || r.messages[0].Channel != "#dev" {
+		t.Fatalf("Expected a message to #dev without mentions but was %v", r.messages)
+	}
+}
+
+func TestPostMessageError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer ts.Close()
+	slackApi = ts.URL
+	err := postMessage("xoxb-test", "U0BOB", "hello", nil)
+	if !notifier.IsPermanent(err) {
+		t.Errorf("Expected permanent error but was %v", err)
+	}
+}
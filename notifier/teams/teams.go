@@ -0,0 +1,151 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const themeColor = "0076D7"
+
+var (
+	githubUrl  = envvars.Env.Github.Url
+	httpClient = &http.Client{}
+)
+
+func init() {
+	notifier.Register(model.Teams, &MySender{})
+}
+
+type MySender struct{}
+
+// MessageCard is the legacy actionable message card format
+// that is accepted by Microsoft Teams incoming webhooks.
+// https://docs.microsoft.com/en-us/outlook/actionable-messages/message-card-reference
+type MessageCard struct {
+	Type            string          `json:"@type"`
+	Context         string          `json:"@context"`
+	Summary         string          `json:"summary"`
+	ThemeColor      string          `json:"themeColor"`
+	Title           string          `json:"title"`
+	Text            string          `json:"text"`
+	PotentialAction []OpenUriAction `json:"potentialAction"`
+}
+
+type OpenUriAction struct {
+	Type    string      `json:"@type"`
+	Name    string      `json:"name"`
+	Targets []UriTarget `json:"targets"`
+}
+
+type UriTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+func (ms *MySender) Prefix(mw notifier.MessageWrapper) string {
+	// [Click here](https://alert-system.com/alerts/1234)
+	return fmt.Sprintf("[Pull Request %s](%s) in [repo %s](%s/%s): ",
+		mw.MessageHeader.PrName,
+		pullRequestUrl(mw.MessageHeader),
+		mw.MessageHeader.Slug,
+		githubUrl,
+		mw.MessageHeader.Slug)
+}
+
+func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) error {
+	if url == "" {
+		return notifier.Permanent(errors.New("TEAMS_TARGET_URL is not configured"))
+	}
+	output, err := toJson(buildCard(header, message))
+	if err != nil {
+		return notifier.Permanent(fmt.Errorf("Unable to convert notification to JSON: %v", err))
+	}
+	// incoming webhooks are bound to a single channel so
+	// the names of the target are not used
+	err = post(url, output)
+	if err != nil {
+		log.Warnf("Error while writing %s to %s: %v", output, url, err)
+	}
+	return err
+}
+
+func buildCard(header notifier.MessageHeader, message string) MessageCard {
+	title := fmt.Sprintf("Pull Request %s in %s", header.PrName, header.Slug)
+	return MessageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    title,
+		ThemeColor: themeColor,
+		Title:      title,
+		// Teams markdown requires a blank line between paragraphs
+		Text: strings.Replace(message, "\n", "\n\n", -1),
+		PotentialAction: []OpenUriAction{
+			{
+				Type: "OpenUri",
+				Name: "View Pull Request",
+				Targets: []UriTarget{
+					{OS: "default", URI: pullRequestUrl(header)},
+				},
+			},
+		},
+	}
+}
+
+func pullRequestUrl(header notifier.MessageHeader) string {
+	return fmt.Sprintf("%s/%s/pull/%d", githubUrl, header.Slug, header.PrNumber)
+}
+
+func post(url string, msg string) error {
+	resp, err := httpClient.Post(url, "application/json", strings.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("Unexpected status %s", resp.Status)
+	case resp.StatusCode >= 300:
+		return notifier.Permanent(fmt.Errorf("Unexpected status %s", resp.Status))
+	}
+	return nil
+}
+
+func toJson(s interface{}) (string, error) {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	err := e.Encode(s)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,86 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package teams
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/capitalone/checks-out/notifier"
+)
+
+func TestPrefix(t *testing.T) {
+	githubUrl = "https://github.com"
+	ms := &MySender{}
+	mw := notifier.MessageWrapper{
+		MessageHeader: notifier.MessageHeader{
+			PrName:   "fix things",
+			PrNumber: 12,
+			Slug:     "foo/bar",
+		},
+	}
+	expected := "[Pull Request fix things](https://github.com/foo/bar/pull/12) in [repo foo/bar](https://github.com/foo/bar): "
+	if actual := ms.Prefix(mw); actual != expected {
+		t.Errorf("Expected %s but was %s", expected, actual)
+	}
+}
+
+func TestPost(t *testing.T) {
+	githubUrl = "https://github.com"
+	var card MessageCard
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &card); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+	header := notifier.MessageHeader{PrName: "fix things", PrNumber: 12, Slug: "foo/bar"}
+	msg, err := toJson(buildCard(header, "approved\nmerged"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = post(ts.URL, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Type != "MessageCard" {
+		t.Errorf("Unexpected card type %s", card.Type)
+	}
+	if card.Text != "approved\n\nmerged" {
+		t.Errorf("Unexpected card text %q", card.Text)
+	}
+	if card.PotentialAction[0].Targets[0].URI != "https://github.com/foo/bar/pull/12" {
+		t.Errorf("Unexpected card action %v", card.PotentialAction)
+	}
+}
+
+func TestPostError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	}))
+	defer ts.Close()
+	if err := post(ts.URL, "{}"); err == nil {
+		t.Error("Expected error on bad request")
+	}
+}
@@ -0,0 +1,260 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Version is the version of the webhook payload schema.
+// It is incremented on any incompatible change to Payload.
+const Version = 1
+
+const (
+	EventHeader     = "X-Checks-Out-Event"
+	DeliveryHeader  = "X-Checks-Out-Delivery"
+	SignatureHeader = "X-Checks-Out-Signature"
+	// MessageEvent is the event of a message that
+	// is not associated with a CommentMessage type.
+	MessageEvent = "message"
+)
+
+var (
+	githubUrl  = envvars.Env.Github.Url
+	httpClient = &http.Client{Timeout: 30 * time.Second}
+	now        = time.Now
+	deliveryID = randomID
+)
+
+func init() {
+	notifier.Register(model.Webhook, &MySender{})
+}
+
+type MySender struct{}
+
+// Payload is the JSON body of each webhook delivery.
+type Payload struct {
+	Version     int         `json:"version"`
+	Delivery    string      `json:"delivery"`
+	Event       string      `json:"event"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Message     string      `json:"message"`
+	Repository  Repository  `json:"repository"`
+	PullRequest PullRequest `json:"pull_request"`
+	Policy      string      `json:"policy"`
+	Approvers   []string    `json:"approvers"`
+	// Disapprovers are the people whose feedback blocks the pull request.
+	Disapprovers []string `json:"disapprovers"`
+	// NeededApprovers are the people who can still satisfy the policy.
+	NeededApprovers []Person      `json:"needed_approvers"`
+	Requirements    []Requirement `json:"requirements"`
+}
+
+// Person is a maintainer. All fields are empty when the
+// person is not known for the event.
+type Person struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Requirement is a condition of the approval policy.
+type Requirement struct {
+	Description string `json:"description"`
+	Met         bool   `json:"met"`
+}
+
+type Repository struct {
+	Slug string `json:"slug"`
+	Url  string `json:"url"`
+}
+
+type PullRequest struct {
+	Name     string `json:"name"`
+	Number   int    `json:"number"`
+	Url      string `json:"url"`
+	Author   Person `json:"author"`
+	HeadSHA  string `json:"head_sha"`
+	BaseSHA  string `json:"base_sha"`
+	MergeSHA string `json:"merge_sha"`
+}
+
+// Prefix is empty because the pull request is
+// a structured field of the payload.
+func (ms *MySender) Prefix(mw notifier.MessageWrapper) string {
+	return ""
+}
+
+func (ms *MySender) Send(c context.Context, header notifier.MessageHeader, message string, names []string, url string) error {
+	return ms.send(header, []event{{name: MessageEvent, message: message}}, url, envvars.Env.Webhook.Secret)
+}
+
+func (ms *MySender) SendEvents(c context.Context, header notifier.MessageHeader, messages []notifier.MessageInfo, target model.TargetConfig) error {
+	var events []event
+	for _, mi := range messages {
+		events = append(events, event{name: mi.Type.String(), message: mi.Message})
+	}
+	return ms.send(header, events, target.Url, target.Secret)
+}
+
+type event struct {
+	name    string
+	message string
+}
+
+func (ms *MySender) send(header notifier.MessageHeader, events []event, url string, secret string) error {
+	if url == "" {
+		return notifier.Permanent(errors.New("WEBHOOK_TARGET_URL is not configured"))
+	}
+	// never send a delivery that the receiver is unable to verify
+	if secret == "" {
+		return notifier.Permanent(fmt.Errorf("No signing secret is configured for webhook %s", url))
+	}
+	// the delivery ids are kept when the notification is retried
+	if header.Delivery == "" {
+		header.Delivery = deliveryID()
+	}
+	// deliver the events of a notification in order
+	for i, e := range events {
+		p := NewPayload(header, i, e.name, e.message)
+		err := deliver(url, secret, p)
+		if err != nil {
+			log.Warnf("Error while delivering %s event %s to %s: %v", p.Event, p.Delivery, url, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// NewPayload creates the payload of the event at the index of the
+// notification. The delivery id is derived from the delivery of the
+// notification, so that a retried event keeps its id.
+func NewPayload(header notifier.MessageHeader, index int, eventName string, message string) *Payload {
+	approvers := header.Approvers
+	if approvers == nil {
+		approvers = []string{}
+	}
+	disapprovers := header.Disapprovers
+	if disapprovers == nil {
+		disapprovers = []string{}
+	}
+	needed := []Person{}
+	for _, p := range header.NeededApprovers {
+		needed = append(needed, newPerson(p))
+	}
+	requirements := []Requirement{}
+	for _, r := range header.Requirements {
+		requirements = append(requirements, Requirement{Description: r.Description, Met: r.Met})
+	}
+	return &Payload{
+		Version:   Version,
+		Delivery:  fmt.Sprintf("%s-%d", header.Delivery, index+1),
+		Event:     eventName,
+		Timestamp: now().UTC(),
+		Message:   message,
+		Repository: Repository{
+			Slug: header.Slug,
+			Url:  fmt.Sprintf("%s/%s", githubUrl, header.Slug),
+		},
+		PullRequest: PullRequest{
+			Name:     header.PrName,
+			Number:   header.PrNumber,
+			Url:      fmt.Sprintf("%s/%s/pull/%d", githubUrl, header.Slug, header.PrNumber),
+			Author:   newPerson(header.Author),
+			HeadSHA:  header.HeadSHA,
+			BaseSHA:  header.BaseSHA,
+			MergeSHA: header.MergeSHA,
+		},
+		Policy:          header.Policy,
+		Approvers:       approvers,
+		Disapprovers:    disapprovers,
+		NeededApprovers: needed,
+		Requirements:    requirements,
+	}
+}
+
+func newPerson(p *model.Person) Person {
+	if p == nil {
+		return Person{}
+	}
+	return Person{Login: p.Login, Name: p.Name, Email: p.Email}
+}
+
+// Sign returns the signature header value of the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver posts the payload. Failed deliveries are retried by
+// the notification outbox unless the error is permanent.
+func deliver(url string, secret string, p *Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return notifier.Permanent(err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return notifier.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("%s-webhook/%d", envvars.Env.Branding.ShortName, Version))
+	req.Header.Set(EventHeader, p.Event)
+	req.Header.Set(DeliveryHeader, p.Delivery)
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("Unexpected status %s", resp.Status)
+	default:
+		return notifier.Permanent(fmt.Errorf("Unexpected status %s", resp.Status))
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return fmt.Sprintf("%d", now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,228 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func setup() {
+	githubUrl = "https://github.com"
+	now = func() time.Time {
+		return time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC)
+	}
+	deliveryID = func() string {
+		return "0123456789abcdef0123456789abcdef"
+	}
+}
+
+var testHeader = notifier.MessageHeader{
+	PrName:       "Add widgets",
+	PrNumber:     42,
+	Slug:         "octocat/hello-world",
+	Author:       &model.Person{Login: "octocat", Name: "The Octocat", Email: "octocat@example.com"},
+	Policy:       "default",
+	Approvers:    []string{"alice", "bob"},
+	Disapprovers: []string{"carol"},
+	NeededApprovers: []*model.Person{
+		{Login: "dave", Name: "Dave", Email: "dave@example.com"},
+	},
+	Requirements: []notifier.Requirement{
+		{Description: "approval policy is satisfied", Met: false},
+	},
+	HeadSHA:  "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+	BaseSHA:  "9049f1265b7d61be4a8904a9a27120d2064dab3b",
+	MergeSHA: "e5bd3914e2e596debea16f433f57875b5b90bcd6",
+	Delivery: "0123456789abcdef0123456789abcdef",
+}
+
+func TestPayloadGolden(t *testing.T) {
+	setup()
+	tests := []struct {
+		event   model.CommentMessage
+		message string
+		header  notifier.MessageHeader
+	}{
+		{model.CommentApprove, "approval added by alice.", testHeader},
+		{model.CommentMerge, "merged", testHeader},
+		{model.CommentTag, "Tagged with version 1.2.0", testHeader},
+		{model.CommentError, "Unable to merge pull request", notifier.MessageHeader{
+			PrName:   "Unknown",
+			PrNumber: 0,
+			Slug:     "octocat/hello-world",
+			Delivery: "0123456789abcdef0123456789abcdef",
+		}},
+	}
+	for _, test := range tests {
+		p := NewPayload(test.header, 0, test.event.String(), test.message)
+		actual, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", test.event.String()+".golden")
+		if *update {
+			ioutil.WriteFile(golden, append(actual, '\n'), 0644)
+		}
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bytes.TrimSpace(expected), actual) {
+			t.Errorf("Payload for %s does not match %s:\n%s", test.event, golden, actual)
+		}
+	}
+}
+
+func TestDeliverSigned(t *testing.T) {
+	setup()
+	var received *http.Request
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	p := NewPayload(testHeader, 0, model.CommentApprove.String(), "approval added by alice.")
+	err := deliver(ts.URL, "s3cret", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Header.Get(EventHeader) != "approve" {
+		t.Errorf("Unexpected event header %s", received.Header.Get(EventHeader))
+	}
+	if received.Header.Get(DeliveryHeader) != p.Delivery {
+		t.Errorf("Unexpected delivery header %s", received.Header.Get(DeliveryHeader))
+	}
+	if received.Header.Get(SignatureHeader) != Sign("s3cret", body) {
+		t.Errorf("Unexpected signature header %s", received.Header.Get(SignatureHeader))
+	}
+	if Sign("other", body) == Sign("s3cret", body) {
+		t.Error("Signature does not depend on the secret")
+	}
+}
+
+func TestDeliverRetryable(t *testing.T) {
+	setup()
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		p := NewPayload(testHeader, 0, model.CommentMerge.String(), "merged")
+		err := deliver(ts.URL, "s3cret", p)
+		ts.Close()
+		if err == nil {
+			t.Errorf("Expected error on status %d", status)
+		}
+		if notifier.IsPermanent(err) {
+			t.Errorf("Expected status %d to be retried", status)
+		}
+	}
+}
+
+func TestDeliverPermanent(t *testing.T) {
+	setup()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+	p := NewPayload(testHeader, 0, model.CommentMerge.String(), "merged")
+	err := deliver(ts.URL, "s3cret", p)
+	if !notifier.IsPermanent(err) {
+		t.Errorf("Expected permanent error on bad request but was %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt but was %d", calls)
+	}
+}
+
+func TestSendUnsigned(t *testing.T) {
+	setup()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	ms := &MySender{}
+	target := model.TargetConfig{Target: model.Webhook.String(), Url: ts.URL}
+	messages := []notifier.MessageInfo{{Message: "merged", Type: model.CommentMerge}}
+	err := ms.SendEvents(context.Background(), testHeader, messages, target)
+	if !notifier.IsPermanent(err) {
+		t.Errorf("Expected permanent error without a secret but was %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no unsigned deliveries but was %d", calls)
+	}
+}
+
+func TestSendRetryKeepsDelivery(t *testing.T) {
+	setup()
+	var calls int32
+	var ids []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(DeliveryHeader))
+		// the second event fails on the first attempt
+		if atomic.AddInt32(&calls, 1) == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	ms := &MySender{}
+	target := model.TargetConfig{Target: model.Webhook.String(), Url: ts.URL, Secret: "s3cret"}
+	messages := []notifier.MessageInfo{
+		{Message: "merged", Type: model.CommentMerge},
+		{Message: "Tagged with version 1.2.0", Type: model.CommentTag},
+	}
+	if err := ms.SendEvents(context.Background(), testHeader, messages, target); err == nil {
+		t.Fatal("Expected the first attempt to fail")
+	}
+	if err := ms.SendEvents(context.Background(), testHeader, messages, target); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"0123456789abcdef0123456789abcdef-1", "0123456789abcdef0123456789abcdef-2",
+		"0123456789abcdef0123456789abcdef-1", "0123456789abcdef0123456789abcdef-2"}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %d deliveries but was %v", len(expected), ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected delivery %s but was %s", expected[i], ids[i])
+		}
+	}
+}
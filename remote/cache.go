@@ -25,6 +25,8 @@ import (
 	"github.com/capitalone/checks-out/cache"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/set"
+
+	log "github.com/Sirupsen/logrus"
 )
 
 // GetOrgs returns the list of user organizations from the cache
@@ -70,10 +72,12 @@ func ListTeams(c context.Context, user *model.User, org string) (set.Set, error)
 // GetPerm returns the user permissions repositories from the cache
 // associated with the current repository.
 func GetPerm(c context.Context, user *model.User, owner, name string) (*model.Perm, error) {
-	key := fmt.Sprintf("perms:%s:%s/%s",
-		user.Login,
+	// the repository comes first so that the permissions
+	// of a repository or an organization can be evicted
+	key := fmt.Sprintf("perms:%s/%s:%s",
 		owner,
 		name,
+		user.Login,
 	)
 	// if we fetch from the cache we can return immediately
 	val, err := cache.Get(c, key)
@@ -90,11 +94,48 @@ func GetPerm(c context.Context, user *model.User, owner, name string) (*model.Pe
 	return perm, nil
 }
 
+// EvictMember removes the cached organizations and details of the
+// member and the cached teams and permissions of the organization.
+func EvictMember(c context.Context, org string, login string) []string {
+	keys := EvictOrg(c, org)
+	if login == "" {
+		return keys
+	}
+	orgs := fmt.Sprintf("orgs:%s", login)
+	people := fmt.Sprintf("people:%s", login)
+	evict(cache.Delete(c, orgs), orgs)
+	evict(cache.Longterm().Delete(people), people)
+	return append(keys, orgs, people)
+}
+
+// EvictOrg removes the cached teams of the organization and the
+// cached permissions of the repositories in the organization.
+func EvictOrg(c context.Context, org string) []string {
+	teams := fmt.Sprintf("teams:%s", org)
+	perms := fmt.Sprintf("perms:%s/", org)
+	evict(cache.Delete(c, teams), teams)
+	evict(cache.DeletePrefix(c, perms), perms+"*")
+	return []string{teams, perms + "*"}
+}
+
+// EvictRepo removes the cached permissions of the repository.
+func EvictRepo(c context.Context, owner string, name string) []string {
+	perms := fmt.Sprintf("perms:%s/%s:", owner, name)
+	evict(cache.DeletePrefix(c, perms), perms+"*")
+	return []string{perms + "*"}
+}
+
+func evict(err error, key string) {
+	if err != nil {
+		log.Warnf("Unable to evict %s from cache: %v", key, err)
+	}
+}
+
 func getPeople(c context.Context, user *model.User, ids set.Set) ([]*model.Person, error) {
 	var people []*model.Person
 	for login := range ids {
 		key := fmt.Sprintf("people:%s", login)
-		val, err := cache.Longterm.Get(key)
+		val, err := cache.Longterm().Get(key)
 		if err == nil {
 			people = append(people, val.(*model.Person))
 		} else {
@@ -102,9 +143,11 @@ func getPeople(c context.Context, user *model.User, ids set.Set) ([]*model.Perso
 			if err != nil {
 				return nil, err
 			}
-			err = cache.Longterm.Set(key, p)
+			// a shared cache that is unavailable
+			// should not fail the request
+			err = cache.Longterm().Set(key, p)
 			if err != nil {
-				return nil, err
+				log.Warnf("Unable to cache %s: %v", key, err)
 			}
 			people = append(people, p)
 		}
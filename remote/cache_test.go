@@ -80,10 +80,10 @@ func TestHelper(t *testing.T) {
 		})
 
 		g.It("Should get permissions from cache", func() {
-			key := fmt.Sprintf("perms:%s:%s/%s",
-				fakeUser.Login,
+			key := fmt.Sprintf("perms:%s/%s:%s",
 				fakeRepo.Owner,
 				fakeRepo.Name,
+				fakeUser.Login,
 			)
 
 			cache.Set(c, key, fakePerm)
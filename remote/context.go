@@ -37,3 +37,8 @@ func FromContext(c context.Context) Remote {
 func ToContext(c Setter, client Remote) {
 	c.Set(key, client)
 }
+
+// AddToContext returns a copy of the context with the Remote client.
+func AddToContext(c context.Context, client Remote) context.Context {
+	return context.WithValue(c, key, client)
+}
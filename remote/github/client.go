@@ -146,7 +146,7 @@ func (c *Client) stream(rawurl, method string, in, out interface{}) (io.ReadClos
 	if resp.StatusCode > http.StatusPartialContent {
 		defer resp.Body.Close()
 		out, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf(string(out))
+		return nil, fmt.Errorf("%s", out)
 	}
 	return resp.Body, nil
 }
@@ -0,0 +1,171 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/capitalone/checks-out/envvars"
+)
+
+// etagMaxBody is the largest response body kept in the ETag cache.
+const etagMaxBody = 1 << 20
+
+type etagEntry struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// etagCache keeps the latest response of GET requests that
+// returned an ETag. The requests are repeated with If-None-Match
+// and GitHub answers 304 Not Modified without counting the request
+// against the rate limit when the resource did not change.
+type etagCache struct {
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+var etags = newETagCache()
+
+func newETagCache() *etagCache {
+	return &etagCache{
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+func (c *etagCache) get(key string) *etagEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*etagEntry)
+}
+
+// add stores the entry and evicts the least recently used entries
+// beyond GITHUB_ETAG_CACHE_SIZE.
+func (c *etagCache) add(entry *etagEntry, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+	} else {
+		c.items[entry.key] = c.order.PushFront(entry)
+	}
+	for c.order.Len() > size {
+		elem := c.order.Back()
+		c.order.Remove(elem)
+		delete(c.items, elem.Value.(*etagEntry).key)
+	}
+}
+
+func (c *etagCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+// roundTrip sends a conditional request when a response of the
+// request is cached. A 304 Not Modified is replaced with the cached
+// response, with the headers of the 304 so that the rate limit is
+// tracked.
+func (c *etagCache) roundTrip(token string, req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	size := envvars.Env.Github.ETagCacheSize
+	if size <= 0 || req.Method != "GET" || req.Header.Get("If-None-Match") != "" {
+		return send(req)
+	}
+	key := token + " " + req.Header.Get("Accept") + " " + req.URL.String()
+	entry := c.get(key)
+	if entry != nil {
+		clone := new(http.Request)
+		*clone = *req
+		clone.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			clone.Header[k] = v
+		}
+		clone.Header.Set("If-None-Match", entry.etag)
+		req = clone
+	}
+	resp, err := send(req)
+	if err != nil {
+		return resp, err
+	}
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		header := make(http.Header, len(entry.header))
+		for k, v := range entry.header {
+			header[k] = v
+		}
+		for k, v := range resp.Header {
+			header[k] = v
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Header = header
+		resp.ContentLength = int64(len(entry.body))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(entry.body))
+		return resp, nil
+	}
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		if entry != nil {
+			c.remove(key)
+		}
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, etagMaxBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > etagMaxBody {
+		if entry != nil {
+			c.remove(key)
+		}
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	c.add(&etagEntry{
+		key:    key,
+		etag:   etag,
+		header: resp.Header,
+		body:   body,
+	}, size)
+	return resp, nil
+}
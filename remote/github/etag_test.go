@@ -0,0 +1,88 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+)
+
+func TestETagCache(t *testing.T) {
+	size := envvars.Env.Github.ETagCacheSize
+	envvars.Env.Github.ETagCacheSize = 10
+	defer func() { envvars.Env.Github.ETagCacheSize = size }()
+
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `[{"filename": "a.go"}]`)
+	}))
+	defer server.Close()
+	client := createClient(context.Background(), server.URL+"/", "etag-token", "hubot")
+	repo := &model.Repo{Owner: "octocat", Name: "hello", Slug: "octocat/hello"}
+
+	for i := 0; i < 3; i++ {
+		files, err := getPullRequestFiles(context.Background(), client, repo, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || files[0].Filename != "a.go" {
+			t.Errorf("Unexpected files %v in request %d", files, i)
+		}
+	}
+	if requests != 3 || notModified != 2 {
+		t.Errorf("Expected 2 conditional requests out of 3, got %d out of %d", notModified, requests)
+	}
+
+	// another token does not share the cached responses
+	other := createClient(context.Background(), server.URL+"/", "other-token", "hubot")
+	if _, err := getPullRequestFiles(context.Background(), other, repo, 1); err != nil {
+		t.Fatal(err)
+	}
+	if notModified != 2 {
+		t.Errorf("Expected an unconditional request of another token")
+	}
+}
+
+func TestETagCacheEviction(t *testing.T) {
+	c := newETagCache()
+	for i := 0; i < 3; i++ {
+		c.add(&etagEntry{key: fmt.Sprint(i)}, 2)
+	}
+	c.get("1")
+	c.add(&etagEntry{key: "3"}, 2)
+	if c.get("0") != nil || c.get("2") != nil {
+		t.Errorf("Expected the least recently used entries to be evicted")
+	}
+	if c.get("1") == nil || c.get("3") == nil {
+		t.Errorf("Expected the recently used entries to be kept")
+	}
+}
@@ -88,7 +88,12 @@ func (g *Github) Capabilities(ctx context.Context, u *model.User) (*model.Capabi
 	caps.Repo.DeleteBranch = s.Contains("repo") || s.Contains("public_repo")
 	caps.Repo.Merge = s.Contains("repo") || s.Contains("public_repo")
 	caps.Repo.Tag = s.Contains("repo") || s.Contains("public_repo")
+	caps.Repo.Release = s.Contains("repo") || s.Contains("public_repo")
+	caps.Repo.SignTag = caps.Repo.Tag && len(envvars.Env.Signing.GpgKey) > 0
 	caps.Repo.PRWriteComment = s.Contains("repo") || s.Contains("public_repo")
+	caps.Notify.Teams = len(envvars.Env.Teams.TargetUrl) > 0
+	caps.Notify.Webhook = len(envvars.Env.Webhook.TargetUrl) > 0 && len(envvars.Env.Webhook.Secret) > 0
+	caps.Notify.Email = len(envvars.Env.Email.Host) > 0 && len(envvars.Env.Email.From) > 0
 	if !caps.Repo.CommitStatus {
 		errs = multierror.Append(errs, errors.New("commit status OAuth scope is required"))
 	}
@@ -664,6 +669,29 @@ func (g *Github) delHook(ctx context.Context, client *github.Client, user *model
 	return removeBranchProtection(ctx, client, repo.Owner, repo.Name, r.GetDefaultBranch())
 }
 
+func (g *Github) UpgradeHook(ctx context.Context, user *model.User, repo *model.Repo, token string) error {
+	client := setupClient(ctx, g.API, user)
+	hooks, resp, err := client.Repositories.ListHooks(ctx, repo.Owner, repo.Name, nil)
+	if err != nil {
+		return createError(resp, err)
+	}
+	hook := getTokenHook(hooks, token)
+	if hook == nil {
+		return nil
+	}
+	missing := missingEvents(hook, repoHookEvents)
+	if len(missing) == 0 {
+		return nil
+	}
+	edit := &github.Hook{Events: append(hook.Events, missing...)}
+	_, resp, err = client.Repositories.EditHook(ctx, repo.Owner, repo.Name, hook.GetID(), edit)
+	if err != nil {
+		return createError(resp, err)
+	}
+	log.Infof("Subscribed the webhook of %s to %s", repo.Slug, strings.Join(missing, ", "))
+	return nil
+}
+
 func (g *Github) SetOrgHook(ctx context.Context, user *model.User, org *model.OrgDb, link string) error {
 	client := setupClient(ctx, g.API, user)
 
@@ -698,6 +726,29 @@ func (g *Github) DelOrgHook(ctx context.Context, user *model.User, org *model.Or
 	return nil
 }
 
+func (g *Github) UpgradeOrgHook(ctx context.Context, user *model.User, org *model.OrgDb, token string) error {
+	client := setupClient(ctx, g.API, user)
+	hooks, resp, err := client.Organizations.ListHooks(ctx, org.Owner, nil)
+	if err != nil {
+		return createError(resp, err)
+	}
+	hook := getTokenHook(hooks, token)
+	if hook == nil {
+		return nil
+	}
+	missing := missingEvents(hook, orgHookEvents)
+	if len(missing) == 0 {
+		return nil
+	}
+	edit := &github.Hook{Events: append(hook.Events, missing...)}
+	_, resp, err = client.Organizations.EditHook(ctx, org.Owner, hook.GetID(), edit)
+	if err != nil {
+		return createError(resp, err)
+	}
+	log.Infof("Subscribed the webhook of organization %s to %s", org.Owner, strings.Join(missing, ", "))
+	return nil
+}
+
 // buildOtherContextSlice returns all contexts besides the one for this service
 func buildOtherContextSlice(branch *Branch) []string {
 	checks := []string{}
@@ -1182,39 +1233,16 @@ func getIssue(ctx context.Context, client *github.Client, r *model.Repo, number
 	return result, nil
 }
 
-func (g *Github) MergePR(ctx context.Context, u *model.User, r *model.Repo, pullRequest model.PullRequest, approvers []*model.Person, message string, mergeMethod string) (string, error) {
+func (g *Github) MergePR(ctx context.Context, u *model.User, r *model.Repo, pullRequest model.PullRequest, message string, mergeMethod string) (string, error) {
 	client := setupClient(ctx, g.API, u)
-	return mergePR(ctx, client, r, pullRequest, approvers, message, mergeMethod)
+	return mergePR(ctx, client, r, pullRequest, message, mergeMethod)
 }
 
-func mergePR(ctx context.Context, client *github.Client, r *model.Repo, pullRequest model.PullRequest, approvers []*model.Person, message string, mergeMethod string) (string, error) {
+func mergePR(ctx context.Context, client *github.Client, r *model.Repo, pullRequest model.PullRequest, message string, mergeMethod string) (string, error) {
 	log.Debugf("incoming message: %v", message)
-	msg := message
-	if len(msg) > 0 {
-		msg += "\n"
-	}
-	msg += fmt.Sprintf("Merged by %s\n", envvars.Env.Branding.ShortName)
-	if len(approvers) > 0 {
-		apps := "Approved by:\n"
-		for _, v := range approvers {
-			//Brad Rydzewski <brad.rydzewski@mail.com> (@bradrydzewski)
-			if len(v.Name) > 0 {
-				apps += fmt.Sprintf("%s", v.Name)
-			}
-			if len(v.Email) > 0 {
-				apps += fmt.Sprintf(" <%s>", v.Email)
-			}
-			if len(v.Login) > 0 {
-				apps += fmt.Sprintf(" (@%s)", v.Login)
-			}
-			apps += "\n"
-		}
-		msg += apps
-	}
-	log.Debugf("Constructed message: %v", msg)
 	options := github.PullRequestOptions{MergeMethod: "merge"}
 	options.MergeMethod = mergeMethod
-	result, resp, err := client.PullRequests.Merge(ctx, r.Owner, r.Name, pullRequest.Number, msg, &options)
+	result, resp, err := client.PullRequests.Merge(ctx, r.Owner, r.Name, pullRequest.Number, message, &options)
 	if err != nil {
 		return "", createError(resp, err)
 	}
@@ -1289,18 +1317,53 @@ func (g *Github) Tag(ctx context.Context, u *model.User, r *model.Repo, tag stri
 }
 
 func doTag(ctx context.Context, client *github.Client, r *model.Repo, tag string, sha string) error {
-	t := time.Now()
+	_, resp, err := client.Git.CreateRef(ctx, r.Owner, r.Name, &github.Reference{
+		Ref: github.String("refs/tags/" + tag),
+		Object: &github.GitObject{
+			SHA: github.String(sha),
+		},
+	})
+
+	if err != nil {
+		return createError(resp, err)
+	}
+
+	return nil
+}
+
+func (g *Github) CreateAnnotatedTag(ctx context.Context, u *model.User, r *model.Repo, tag model.AnnotatedTag) error {
+	client := setupClient(ctx, g.API, u)
+	return createAnnotatedTag(ctx, client, r, tag)
+}
+
+func createAnnotatedTag(ctx context.Context, client *github.Client, r *model.Repo, tag model.AnnotatedTag) error {
+	// git object timestamps have a resolution of one second
+	t := time.Now().UTC().Truncate(time.Second)
+	name := envvars.Env.Branding.ShortName
+	email := envvars.Env.Github.Email
+	message := tag.Message
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+	if tag.Sign {
+		payload := tagPayload(tag, name, email, t, message)
+		signature, err := signPayload(ctx, payload)
+		if err != nil {
+			return err
+		}
+		message += string(signature)
+	}
 	gittag, resp, err := client.Git.CreateTag(ctx, r.Owner, r.Name, &github.Tag{
-		Tag:     github.String(tag),
-		SHA:     github.String(sha),
-		Message: github.String(fmt.Sprintf("Tagged by %s", envvars.Env.Branding.ShortName)),
+		Tag:     github.String(tag.Name),
+		SHA:     github.String(tag.SHA),
+		Message: github.String(message),
 		Tagger: &github.CommitAuthor{
 			Date:  &t,
-			Name:  github.String(envvars.Env.Branding.ShortName),
-			Email: github.String(envvars.Env.Github.Email),
+			Name:  github.String(name),
+			Email: github.String(email),
 		},
 		Object: &github.GitObject{
-			SHA:  github.String(sha),
+			SHA:  github.String(tag.SHA),
 			Type: github.String("commit"),
 		},
 	})
@@ -1309,7 +1372,7 @@ func doTag(ctx context.Context, client *github.Client, r *model.Repo, tag string
 		return createError(resp, err)
 	}
 	_, resp, err = client.Git.CreateRef(ctx, r.Owner, r.Name, &github.Reference{
-		Ref: github.String("refs/tags/" + tag),
+		Ref: github.String("refs/tags/" + tag.Name),
 		Object: &github.GitObject{
 			SHA: gittag.SHA,
 		},
@@ -1322,6 +1385,130 @@ func doTag(ctx context.Context, client *github.Client, r *model.Repo, tag string
 	return nil
 }
 
+func (g *Github) ListMergedPullRequests(ctx context.Context, u *model.User, r *model.Repo, base, from, to string) ([]model.PullRequest, error) {
+	client := setupClient(ctx, g.API, u)
+	return listMergedPullRequests(ctx, client, r, base, from, to)
+}
+
+// mergedPullRequestPages bounds the number of pages of closed pull
+// requests that are listed for the release notes of a tag.
+const mergedPullRequestPages = 10
+
+// listMergedPullRequests finds the pull requests merged into the
+// base branch whose merge commit is reachable from the "to" ref
+// but not the "from" ref. If "from" is empty then the most recently
+// merged pull requests are returned. At most mergedPullRequestPages
+// pages of closed pull requests are listed. The GitHub compare API
+// returns at most 250 commits, so pull requests merged before the
+// last 250 commits are missing when more commits were added since
+// the "from" ref.
+func listMergedPullRequests(ctx context.Context, client *github.Client, r *model.Repo, base, from, to string) ([]model.PullRequest, error) {
+	var shas set.Set
+	var since time.Time
+	if len(from) > 0 {
+		comp, resp, err := client.Repositories.CompareCommits(ctx, r.Owner, r.Name, from, to)
+		if err != nil {
+			return nil, createError(resp, err)
+		}
+		if comp.GetTotalCommits() > len(comp.Commits) {
+			log.Warnf("Comparison of %s and %s in %s is truncated to %d of %d commits",
+				from, to, r.Slug, len(comp.Commits), comp.GetTotalCommits())
+		}
+		shas = set.Empty()
+		for _, commit := range comp.Commits {
+			shas.Add(commit.GetSHA())
+		}
+		since = comp.GetMergeBaseCommit().GetCommit().GetCommitter().GetDate()
+	}
+	var out []model.PullRequest
+	opts := github.PullRequestListOptions{
+		State:     "closed",
+		Base:      base,
+		Sort:      "updated",
+		Direction: "desc",
+	}
+	pages := 0
+	resp, err := buildCompleteList(func(lopts *github.ListOptions) (*github.Response, error) {
+		opts.ListOptions = *lopts
+		opts.PerPage = 100
+		prs, resp2, err2 := client.PullRequests.List(ctx, r.Owner, r.Name, &opts)
+		if err2 != nil {
+			return resp2, err2
+		}
+		pages++
+		if pages >= mergedPullRequestPages {
+			resp2.NextPage = 0
+		}
+		for _, pr := range prs {
+			// pull requests are sorted by update time so
+			// stop when reaching the previous tag
+			if pr.GetUpdatedAt().Before(since) {
+				resp2.NextPage = 0
+				break
+			}
+			if pr.MergedAt == nil {
+				continue
+			}
+			if shas != nil && !shas.Contains(pr.GetMergeCommitSHA()) {
+				continue
+			}
+			out = append(out, convertPullRequest(pr))
+		}
+		return resp2, err2
+	})
+	if err != nil {
+		return nil, createError(resp, err)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Number < out[j].Number
+	})
+	return out, nil
+}
+
+func (g *Github) ListOpenPullRequests(ctx context.Context, u *model.User, r *model.Repo) ([]model.PullRequest, error) {
+	client := setupClient(ctx, g.API, u)
+	return listOpenPullRequests(ctx, client, r)
+}
+
+func listOpenPullRequests(ctx context.Context, client *github.Client, r *model.Repo) ([]model.PullRequest, error) {
+	var out []model.PullRequest
+	opts := github.PullRequestListOptions{
+		State: "open",
+	}
+	resp, err := buildCompleteList(func(lopts *github.ListOptions) (*github.Response, error) {
+		opts.ListOptions = *lopts
+		opts.PerPage = 100
+		prs, resp2, err2 := client.PullRequests.List(ctx, r.Owner, r.Name, &opts)
+		for _, pr := range prs {
+			out = append(out, convertPullRequest(pr))
+		}
+		return resp2, err2
+	})
+	if err != nil {
+		return nil, createError(resp, err)
+	}
+	return out, nil
+}
+
+func (g *Github) CreateRelease(ctx context.Context, u *model.User, r *model.Repo, release model.Release) (string, error) {
+	client := setupClient(ctx, g.API, u)
+	return createRelease(ctx, client, r, release)
+}
+
+func createRelease(ctx context.Context, client *github.Client, r *model.Repo, release model.Release) (string, error) {
+	result, resp, err := client.Repositories.CreateRelease(ctx, r.Owner, r.Name, &github.RepositoryRelease{
+		TagName:    github.String(release.Tag),
+		Name:       github.String(release.Name),
+		Body:       github.String(release.Body),
+		Draft:      github.Bool(release.Draft),
+		Prerelease: github.Bool(release.Prerelease),
+	})
+	if err != nil {
+		return "", createError(resp, err)
+	}
+	return result.GetHTMLURL(), nil
+}
+
 func (g *Github) WriteComment(ctx context.Context, u *model.User, r *model.Repo, num int, message string) error {
 	client := setupClient(ctx, g.API, u)
 	return writeComment(ctx, client, r, num, message)
@@ -1338,17 +1525,71 @@ func writeComment(ctx context.Context, client *github.Client, r *model.Repo, num
 	return nil
 }
 
+func (g *Github) GetStickyComment(ctx context.Context, u *model.User, r *model.Repo, num int, marker string) (int64, string, error) {
+	client := setupClient(ctx, g.API, u)
+	return getStickyComment(ctx, client, r, num, marker)
+}
+
+func getStickyComment(ctx context.Context, client *github.Client, r *model.Repo, num int, marker string) (int64, string, error) {
+	lcOpts := github.IssueListCommentsOptions{Direction: "asc", Sort: "created"}
+	var comm []*github.IssueComment
+	resp, err := buildCompleteList(func(opts *github.ListOptions) (*github.Response, error) {
+		lcOpts.ListOptions = *opts
+		newCom, resp, err := client.Issues.ListComments(ctx, r.Owner, r.Name, num, &lcOpts)
+		comm = append(comm, newCom...)
+		return resp, err
+	})
+	if err != nil {
+		return 0, "", createError(resp, err)
+	}
+	for _, comment := range comm {
+		body := comment.GetBody()
+		if strings.HasPrefix(body, model.CommentPrefix) && strings.Contains(body, marker) {
+			return comment.GetID(), body, nil
+		}
+	}
+	return 0, "", nil
+}
+
+func (g *Github) EditComment(ctx context.Context, u *model.User, r *model.Repo, id int64, message string) error {
+	client := setupClient(ctx, g.API, u)
+	return editComment(ctx, client, r, id, message)
+}
+
+func editComment(ctx context.Context, client *github.Client, r *model.Repo, id int64, message string) error {
+	emsg := model.CommentPrefix + " " + message
+	_, resp, err := client.Issues.EditComment(ctx, r.Owner, r.Name, id, &github.IssueComment{
+		Body: github.String(emsg),
+	})
+	if err != nil {
+		return createError(resp, err)
+	}
+	return nil
+}
+
 func (g *Github) ScheduleDeployment(ctx context.Context, u *model.User, r *model.Repo, d model.DeploymentInfo) error {
 	client := setupClient(ctx, g.API, u)
 	return scheduleDeployment(ctx, client, r, d)
 }
 
 func scheduleDeployment(ctx context.Context, client *github.Client, r *model.Repo, d model.DeploymentInfo) error {
-	_, resp, err := client.Repositories.CreateDeployment(ctx, r.Owner, r.Name, &github.DeploymentRequest{
-		Ref:         github.String(d.Ref),
-		Task:        github.String(d.Task),
-		Environment: github.String(d.Environment),
-	})
+	req := &github.DeploymentRequest{
+		Ref:              github.String(d.Ref),
+		RequiredContexts: d.RequiredContexts,
+	}
+	if len(d.Environment) > 0 {
+		req.Environment = github.String(d.Environment)
+	}
+	if len(d.Task) > 0 {
+		req.Task = github.String(d.Task)
+	}
+	if len(d.Payload) > 0 {
+		req.Payload = github.String(d.Payload)
+	}
+	if d.PullRequest > 0 {
+		req.Description = github.String(model.DeploymentDescription(d.PullRequest))
+	}
+	_, resp, err := client.Repositories.CreateDeployment(ctx, r.Owner, r.Name, req)
 	if err != nil {
 		return createError(resp, err)
 	}
@@ -1365,14 +1606,23 @@ func doGetPullRequest(ctx context.Context, client *github.Client, r *model.Repo,
 	log.Debug("current pr ==", *pr)
 	sha := pr.Head.SHA
 
+	return convertPullRequest(pr), sha, nil
+}
+
+func convertPullRequest(pr *github.PullRequest) model.PullRequest {
 	mergeable := true
 	if pr.Mergeable != nil {
 		mergeable = *pr.Mergeable
 	}
 
+	var labels []string
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
 	result := model.PullRequest{
 		Issue: model.Issue{
-			Number: number,
+			Number: pr.GetNumber(),
 			Title:  pr.GetTitle(),
 			Author: lowercase.Create(pr.User.GetLogin()),
 		},
@@ -1388,7 +1638,8 @@ func doGetPullRequest(ctx context.Context, client *github.Client, r *model.Repo,
 			BaseName:       pr.Base.GetRef(),
 			BaseSHA:        pr.Base.GetSHA(),
 		},
-		Body: pr.GetBody(),
+		Body:   pr.GetBody(),
+		Labels: labels,
 	}
-	return result, sha, nil
+	return result
 }
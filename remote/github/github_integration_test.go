@@ -292,7 +292,7 @@ func testMergeAndDeleteInner(t *testing.T, client *github.Client, repo *github.R
 		t.Error("Unable to get latest status of pull request", err)
 	}
 	if latest.Mergeable != nil && *latest.Mergeable {
-		_, err = mergePR(ctx, client, r, p, []*model.Person{}, "", "")
+		_, err = mergePR(ctx, client, r, p, "", "")
 		if err != nil && err.(exterror.ExtError).Status != 405 {
 			t.Error("Unable to merge pull request", err)
 		}
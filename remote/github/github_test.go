@@ -0,0 +1,57 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/capitalone/checks-out/model"
+)
+
+func TestListMergedPullRequestsPages(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello/pulls" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		pages++
+		// every page links to a next page
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d>; rel="next"`, r.Host, r.URL.Path, pages+1))
+		fmt.Fprintf(w, `[{"number": %d, "merged_at": "2017-01-01T00:00:00Z", "updated_at": "2017-01-01T00:00:00Z",
+			"head": {"ref": "feature", "user": {"login": "bob"}}, "base": {"ref": "master"}}]`, pages)
+	}))
+	defer server.Close()
+	client := createClient(context.Background(), server.URL+"/", "merged-token", "hubot")
+	repo := &model.Repo{Owner: "octocat", Name: "hello", Slug: "octocat/hello"}
+
+	prs, err := listMergedPullRequests(context.Background(), client, repo, "master", "", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != mergedPullRequestPages {
+		t.Errorf("Expected %d pages, got %d", mergedPullRequestPages, pages)
+	}
+	if len(prs) != mergedPullRequestPages || prs[0].Number != 1 {
+		t.Errorf("Unexpected pull requests %v", prs)
+	}
+}
@@ -0,0 +1,340 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	"github.com/google/go-github/github"
+)
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLActor struct {
+	Login string `json:"login"`
+}
+
+type graphQLCommit struct {
+	Oid       string `json:"oid"`
+	Message   string `json:"message"`
+	Committer struct {
+		Name string    `json:"name"`
+		Date time.Time `json:"date"`
+	} `json:"committer"`
+	Author struct {
+		User *graphQLActor `json:"user"`
+	} `json:"author"`
+	Parents struct {
+		Nodes []struct {
+			Oid string `json:"oid"`
+		} `json:"nodes"`
+	} `json:"parents"`
+}
+
+type pullRequestPage struct {
+	HeadRefOid string `json:"headRefOid"`
+	Files      *struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			Path string `json:"path"`
+		} `json:"nodes"`
+	} `json:"files"`
+	Commits *struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			Commit graphQLCommit `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	Comments *struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			Author    *graphQLActor `json:"author"`
+			Body      string        `json:"body"`
+			CreatedAt time.Time     `json:"createdAt"`
+			UpdatedAt time.Time     `json:"updatedAt"`
+		} `json:"nodes"`
+	} `json:"comments"`
+	Reviews *struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			DatabaseID  int64         `json:"databaseId"`
+			Author      *graphQLActor `json:"author"`
+			Body        string        `json:"body"`
+			State       string        `json:"state"`
+			SubmittedAt time.Time     `json:"submittedAt"`
+		} `json:"nodes"`
+	} `json:"reviews"`
+}
+
+type pullRequestResponse struct {
+	Data struct {
+		Repository *struct {
+			PullRequest *pullRequestPage `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// The connections of a pull request that are loaded by
+// LoadPullRequestData, with the selection of their nodes.
+var pullRequestConnections = []struct {
+	name      string
+	selection string
+}{
+	{"files", "path"},
+	{"commits", "commit { oid message committer { name date } author { user { login } } parents(first: 2) { nodes { oid } } }"},
+	{"comments", "author { login } body createdAt updatedAt"},
+	{"reviews", "databaseId author { login } body state submittedAt"},
+}
+
+// pullRequestQuery builds the query for the next page of the
+// connections with a cursor. The first query has all the
+// connections with a nil cursor.
+func pullRequestQuery(cursors map[string]*string) string {
+	var vars, fields []string
+	for _, conn := range pullRequestConnections {
+		cursor, ok := cursors[conn.name]
+		if !ok {
+			continue
+		}
+		after := ""
+		if cursor != nil {
+			vars = append(vars, fmt.Sprintf("$%s: String", conn.name))
+			after = fmt.Sprintf(", after: $%s", conn.name)
+		}
+		fields = append(fields, fmt.Sprintf(
+			"%s(first: 100%s) { pageInfo { hasNextPage endCursor } nodes { %s } }",
+			conn.name, after, conn.selection))
+	}
+	decl := "$owner: String!, $name: String!, $number: Int!"
+	if len(vars) > 0 {
+		decl += ", " + strings.Join(vars, ", ")
+	}
+	return fmt.Sprintf("query(%s) { repository(owner: $owner, name: $name) { pullRequest(number: $number) { headRefOid %s } } }",
+		decl, strings.Join(fields, " "))
+}
+
+// graphQLURL is the GraphQL endpoint relative to the REST API.
+// GitHub Enterprise serves it at /api/graphql next to /api/v3.
+func graphQLURL(client *github.Client) string {
+	if strings.HasSuffix(client.BaseURL.Path, "/v3/") {
+		return "../graphql"
+	}
+	return "graphql"
+}
+
+func queryPullRequest(ctx context.Context, client *github.Client, r *model.Repo, number int, cursors map[string]*string) (*pullRequestPage, error) {
+	vars := map[string]interface{}{
+		"owner":  r.Owner,
+		"name":   r.Name,
+		"number": number,
+	}
+	for name, cursor := range cursors {
+		if cursor != nil {
+			vars[name] = *cursor
+		}
+	}
+	req, err := client.NewRequest("POST", graphQLURL(client), &graphQLRequest{
+		Query:     pullRequestQuery(cursors),
+		Variables: vars,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out pullRequestResponse
+	resp, err := client.Do(ctx, req, &out)
+	if err != nil {
+		return nil, createError(resp, err)
+	}
+	if len(out.Errors) > 0 {
+		status := http.StatusInternalServerError
+		if out.Errors[0].Type == "NOT_FOUND" {
+			status = http.StatusNotFound
+		}
+		return nil, exterror.Create(status, errors.New(out.Errors[0].Message))
+	}
+	if out.Data.Repository == nil || out.Data.Repository.PullRequest == nil {
+		err = fmt.Errorf("Pull request %s#%d not found", r.Slug, number)
+		return nil, exterror.Create(http.StatusNotFound, err)
+	}
+	return out.Data.Repository.PullRequest, nil
+}
+
+func (g *Github) LoadPullRequestData(ctx context.Context, u *model.User, r *model.Repo, number int, noUIMerge bool) (*model.PullRequestData, error) {
+	client := setupClient(ctx, g.API, u)
+	return loadPullRequestData(ctx, client, r, number, noUIMerge)
+}
+
+// loadPullRequestData pages through the connections of the pull
+// request. Every query fetches the next page of all the connections
+// that have more pages, so a pull request with less than a hundred
+// files, commits, comments and reviews is loaded with one query.
+func loadPullRequestData(ctx context.Context, client *github.Client, r *model.Repo, number int, noUIMerge bool) (*model.PullRequestData, error) {
+	data := &model.PullRequestData{
+		Files:    []model.CommitFile{},
+		Commits:  []model.Commit{},
+		Comments: []*model.Comment{},
+		Reviews:  []*model.Review{},
+	}
+	var updated []time.Time
+	cursors := map[string]*string{}
+	for _, conn := range pullRequestConnections {
+		cursors[conn.name] = nil
+	}
+	var head string
+	commits := map[string]*graphQLCommit{}
+	for len(cursors) > 0 {
+		page, err := queryPullRequest(ctx, client, r, number, cursors)
+		if err != nil {
+			return nil, err
+		}
+		head = page.HeadRefOid
+		next := map[string]*string{}
+		if page.Files != nil {
+			for _, f := range page.Files.Nodes {
+				data.Files = append(data.Files, model.CommitFile{Filename: f.Path})
+			}
+			nextPage(next, "files", page.Files.PageInfo)
+		}
+		if page.Commits != nil {
+			for i := range page.Commits.Nodes {
+				c := &page.Commits.Nodes[i].Commit
+				commits[c.Oid] = c
+				data.Commits = append(data.Commits, convertGraphQLCommit(c))
+			}
+			nextPage(next, "commits", page.Commits.PageInfo)
+		}
+		if page.Comments != nil {
+			for _, c := range page.Comments.Nodes {
+				if c.Author == nil {
+					continue
+				}
+				data.Comments = append(data.Comments, &model.Comment{
+					Author:      lowercase.Create(c.Author.Login),
+					Body:        c.Body,
+					SubmittedAt: c.CreatedAt,
+				})
+				updated = append(updated, c.UpdatedAt)
+			}
+			nextPage(next, "comments", page.Comments.PageInfo)
+		}
+		if page.Reviews != nil {
+			for _, rev := range page.Reviews.Nodes {
+				if rev.Author == nil {
+					continue
+				}
+				data.Reviews = append(data.Reviews, &model.Review{
+					ID:          rev.DatabaseID,
+					Author:      lowercase.Create(rev.Author.Login),
+					State:       lowercase.Create(rev.State),
+					Body:        rev.Body,
+					SubmittedAt: rev.SubmittedAt,
+				})
+			}
+			nextPage(next, "reviews", page.Reviews.PageInfo)
+		}
+		cursors = next
+	}
+	if c := headCommit(commits, head, noUIMerge); c != nil {
+		data.HeadDate = c.Committer.Date
+	}
+	// The REST API lists the comments updated since the head
+	// commit and the reviews submitted after it.
+	data.HeadComments = []*model.Comment{}
+	for i, c := range data.Comments {
+		if !updated[i].Before(data.HeadDate) {
+			data.HeadComments = append(data.HeadComments, c)
+		}
+	}
+	data.HeadReviews = []*model.Review{}
+	for _, rev := range data.Reviews {
+		if rev.SubmittedAt.After(data.HeadDate) {
+			data.HeadReviews = append(data.HeadReviews, rev)
+		}
+	}
+	return data, nil
+}
+
+func nextPage(cursors map[string]*string, name string, info pageInfo) {
+	if info.HasNextPage {
+		cursor := info.EndCursor
+		cursors[name] = &cursor
+	}
+}
+
+func convertGraphQLCommit(c *graphQLCommit) model.Commit {
+	parents := []string{}
+	for _, p := range c.Parents.Nodes {
+		parents = append(parents, p.Oid)
+	}
+	author := ""
+	if c.Author.User != nil {
+		author = c.Author.User.Login
+	}
+	return model.Commit{
+		Author:    lowercase.Create(author),
+		Committer: c.Committer.Name,
+		Message:   c.Message,
+		SHA:       c.Oid,
+		Parents:   parents,
+	}
+}
+
+// headCommit returns the head commit of the pull request. Merges
+// created through the user interface are followed to the parent
+// that belongs to the pull request, like ignoreUIMerge does with
+// the commits of the base branch.
+func headCommit(commits map[string]*graphQLCommit, head string, noUIMerge bool) *graphQLCommit {
+	c := commits[head]
+	for noUIMerge && c != nil && len(c.Parents.Nodes) == 2 && systemAccounts.Contains(c.Committer.Name) {
+		left := commits[c.Parents.Nodes[0].Oid]
+		right := commits[c.Parents.Nodes[1].Oid]
+		if (left == nil) == (right == nil) {
+			break
+		}
+		if left != nil {
+			c = left
+		} else {
+			c = right
+		}
+	}
+	return c
+}
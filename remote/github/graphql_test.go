@@ -0,0 +1,148 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/capitalone/checks-out/model"
+)
+
+// graphQLServer answers with the files in pages of one and a
+// single page of commits, comments and reviews.
+func graphQLServer(t *testing.T, queries *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		*queries++
+		if *queries == 1 {
+			fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {
+				"headRefOid": "c3",
+				"files": {"pageInfo": {"hasNextPage": true, "endCursor": "f1"}, "nodes": [{"path": "a.go"}]},
+				"commits": {"pageInfo": {"hasNextPage": false}, "nodes": [
+					{"commit": {"oid": "c1", "message": "first", "committer": {"name": "Bob", "date": "2017-01-01T00:00:00Z"},
+						"author": {"user": {"login": "Bob"}}, "parents": {"nodes": [{"oid": "base"}]}}},
+					{"commit": {"oid": "c3", "message": "merge", "committer": {"name": "GitHub", "date": "2017-01-03T00:00:00Z"},
+						"author": {"user": {"login": "bob"}}, "parents": {"nodes": [{"oid": "c1"}, {"oid": "base2"}]}}}
+				]},
+				"comments": {"pageInfo": {"hasNextPage": false}, "nodes": [
+					{"author": {"login": "Alice"}, "body": "old", "createdAt": "2016-12-31T00:00:00Z", "updatedAt": "2016-12-31T00:00:00Z"},
+					{"author": {"login": "carol"}, "body": "edited", "createdAt": "2016-12-31T00:00:00Z", "updatedAt": "2017-01-02T00:00:00Z"},
+					{"author": null, "body": "ghost", "createdAt": "2017-01-02T00:00:00Z", "updatedAt": "2017-01-02T00:00:00Z"}
+				]},
+				"reviews": {"pageInfo": {"hasNextPage": false}, "nodes": [
+					{"databaseId": 7, "author": {"login": "dave"}, "body": "", "state": "APPROVED", "submittedAt": "2017-01-02T00:00:00Z"}
+				]}
+			}}}}`)
+			return
+		}
+		if req.Variables["files"] != "f1" {
+			t.Errorf("Expected the files cursor, got %v", req.Variables)
+		}
+		if strings.Contains(req.Query, "commits") {
+			t.Errorf("Expected only the files in the next page, got %s", req.Query)
+		}
+		fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {
+			"headRefOid": "c3",
+			"files": {"pageInfo": {"hasNextPage": false}, "nodes": [{"path": "b.go"}]}
+		}}}}`)
+	}))
+}
+
+func TestLoadPullRequestData(t *testing.T) {
+	queries := 0
+	server := graphQLServer(t, &queries)
+	defer server.Close()
+	client := createClient(context.Background(), server.URL+"/", "graphql-token", "hubot")
+	repo := &model.Repo{Owner: "octocat", Name: "hello", Slug: "octocat/hello"}
+
+	data, err := loadPullRequestData(context.Background(), client, repo, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queries != 2 {
+		t.Errorf("Expected 2 queries, got %d", queries)
+	}
+	if len(data.Files) != 2 || data.Files[0].Filename != "a.go" || data.Files[1].Filename != "b.go" {
+		t.Errorf("Unexpected files %v", data.Files)
+	}
+	if len(data.Commits) != 2 || data.Commits[0].Author.String() != "bob" || len(data.Commits[1].Parents) != 2 {
+		t.Errorf("Unexpected commits %v", data.Commits)
+	}
+	if len(data.Comments) != 2 || len(data.Reviews) != 1 || data.Reviews[0].State.String() != "approved" {
+		t.Errorf("Unexpected feedback %v %v", data.Comments, data.Reviews)
+	}
+	// the merge through the user interface is ignored
+	if data.HeadDate.Day() != 1 {
+		t.Errorf("Expected the head date of the first commit, got %s", data.HeadDate)
+	}
+	if len(data.HeadComments) != 1 || data.HeadComments[0].Author.String() != "carol" {
+		t.Errorf("Expected the updated comment since the head, got %v", data.HeadComments)
+	}
+	if len(data.HeadReviews) != 1 {
+		t.Errorf("Expected the review since the head, got %v", data.HeadReviews)
+	}
+
+	queries = 0
+	data, err = loadPullRequestData(context.Background(), client, repo, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.HeadDate.Day() != 3 || len(data.HeadComments) != 0 || len(data.HeadReviews) != 0 {
+		t.Errorf("Expected no feedback since the merge, got %s %v %v", data.HeadDate, data.HeadComments, data.HeadReviews)
+	}
+}
+
+func TestLoadPullRequestDataError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"repository": null}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}]}`)
+	}))
+	defer server.Close()
+	client := createClient(context.Background(), server.URL+"/", "graphql-token", "hubot")
+	repo := &model.Repo{Owner: "octocat", Name: "hello", Slug: "octocat/hello"}
+	_, err := loadPullRequestData(context.Background(), client, repo, 1, false)
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("Expected the GraphQL error, got %v", err)
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	client := createClient(context.Background(), "https://github.example.com/api/v3/", "graphql-token", "")
+	u, _ := client.BaseURL.Parse(graphQLURL(client))
+	if u.String() != "https://github.example.com/api/graphql" {
+		t.Errorf("Unexpected enterprise url %s", u)
+	}
+	client.BaseURL, _ = url.Parse("https://api.github.com/")
+	u, _ = client.BaseURL.Parse(graphQLURL(client))
+	if u.String() != "https://api.github.com/graphql" {
+		t.Errorf("Unexpected url %s", u)
+	}
+}
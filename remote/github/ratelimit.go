@@ -0,0 +1,334 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
+	"github.com/capitalone/checks-out/model"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// rateLimit tracks the rate limit of a token for a resource from
+// the headers of the GitHub responses. Requests of the token wait
+// for the rate limit instead of failing, and are paced when it
+// runs low.
+type rateLimit struct {
+	mu    sync.Mutex
+	state model.RateLimit
+	// next is the earliest start of the next paced request
+	next time.Time
+}
+
+// coreResource is the rate limit resource of the REST API
+// requests that do not have a dedicated resource.
+const coreResource = "core"
+
+var (
+	rateLimitsMu sync.Mutex
+	// rateLimits is keyed by the token key and the resource
+	rateLimits = map[string]*rateLimit{}
+
+	rateNow   = time.Now
+	rateSleep = sleepContext
+)
+
+// tokenRateLimit returns the rate limit state of the token key for
+// the resource. Tokens are not kept in memory, only a hash of them.
+func tokenRateLimit(key, login, resource string) *rateLimit {
+	rateLimitsMu.Lock()
+	defer rateLimitsMu.Unlock()
+	l, ok := rateLimits[key+":"+resource]
+	if !ok {
+		l = &rateLimit{state: model.RateLimit{Login: login, Resource: resource}}
+		rateLimits[key+":"+resource] = l
+	}
+	return l
+}
+
+// requestResource returns the rate limit resource that the request
+// is expected to count against. The X-RateLimit-Resource header of
+// the response names the resource that it actually counted against.
+func requestResource(req *http.Request) string {
+	p := strings.TrimPrefix(req.URL.Path, "/api/v3")
+	switch {
+	case strings.HasPrefix(p, "/search/"):
+		return "search"
+	case strings.HasSuffix(p, "/graphql"):
+		return "graphql"
+	}
+	return coreResource
+}
+
+// tokenKey identifies a token by a prefix of its hash.
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// TokenRateLimit returns the core rate limit state of the token,
+// or nil when the token did not make core requests.
+func TokenRateLimit(token string) *model.RateLimit {
+	rateLimitsMu.Lock()
+	l, ok := rateLimits[tokenKey(token)+":"+coreResource]
+	rateLimitsMu.Unlock()
+	if !ok {
+		return nil
+	}
+	l.mu.Lock()
+	state := l.state
+	l.mu.Unlock()
+	return &state
+}
+
+// RateLimits returns the rate limit state of the tokens that
+// made requests, sorted by login and resource.
+func RateLimits() []*model.RateLimit {
+	rateLimitsMu.Lock()
+	out := make([]*model.RateLimit, 0, len(rateLimits))
+	for _, l := range rateLimits {
+		l.mu.Lock()
+		state := l.state
+		l.mu.Unlock()
+		out = append(out, &state)
+	}
+	rateLimitsMu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Login != out[j].Login {
+			return out[i].Login < out[j].Login
+		}
+		return out[i].Resource < out[j].Resource
+	})
+	return out
+}
+
+// reserve returns how long a request must wait for the rate limit.
+// Requests wait for a secondary rate limit to pass and for an
+// exhausted rate limit to reset. When fewer than GITHUB_RATE_LOW
+// requests remain they are spread evenly until the reset, or a tenth
+// of the limit for the resources with a smaller limit. The
+// request is rejected when it would wait longer than
+// GITHUB_RATE_MAX_WAIT.
+func (l *rateLimit) reserve(now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state.Requests++
+	start := now
+	if blocked := time.Unix(l.state.BlockedUntil, 0); blocked.After(start) {
+		start = blocked
+	}
+	var interval time.Duration
+	reset := time.Unix(l.state.Reset, 0)
+	low := envvars.Env.Github.RateLow
+	if low >= l.state.Limit {
+		low = l.state.Limit / 10
+	}
+	if l.state.Limit > 0 && reset.After(now) && l.state.Remaining < low {
+		if l.state.Remaining <= 0 {
+			if reset.After(start) {
+				start = reset
+			}
+		} else {
+			interval = reset.Sub(now) / time.Duration(l.state.Remaining+1)
+			if l.next.After(start) {
+				start = l.next
+			}
+		}
+	}
+	wait := start.Sub(now)
+	if wait > envvars.Env.Github.RateMaxWait {
+		l.state.Rejected++
+		metrics.GitHubRateLimited.Inc(l.state.Login, "rejected")
+		return wait, false
+	}
+	if interval > 0 {
+		l.next = start.Add(interval)
+	}
+	if wait > 0 {
+		l.state.Delayed++
+		metrics.GitHubRateLimited.Inc(l.state.Login, "delayed")
+	}
+	return wait, true
+}
+
+// update records the rate limit headers of a response.
+func (l *rateLimit) update(h http.Header, now time.Time) {
+	limit, err1 := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, err2 := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, err3 := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return
+	}
+	l.mu.Lock()
+	l.state.Limit = limit
+	l.state.Remaining = remaining
+	l.state.Reset = reset
+	l.state.Updated = now.Unix()
+	login := l.state.Login
+	l.mu.Unlock()
+	metrics.GitHubRateLimitRemaining.Set(float64(remaining), login, l.state.Resource)
+}
+
+// retry blocks the requests of the token until the time.
+func (l *rateLimit) retry(until time.Time) {
+	l.mu.Lock()
+	if until.Unix() > l.state.BlockedUntil {
+		l.state.BlockedUntil = until.Unix()
+	}
+	l.state.Retried++
+	login := l.state.Login
+	l.mu.Unlock()
+	metrics.GitHubRateLimited.Inc(login, "retried")
+}
+
+// rateLimited reports whether the response hit a rate limit and
+// how long to wait before retrying. Secondary (abuse) rate limits
+// answer 403 or 429, usually with a Retry-After header. An
+// exhausted primary rate limit waits for the reset instead.
+func rateLimited(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return 0, true
+	}
+	backoff := time.Second << uint(attempt)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return backoff, true
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+	msg := strings.ToLower(string(body))
+	if strings.Contains(msg, "abuse") || strings.Contains(msg, "secondary rate limit") {
+		return backoff, true
+	}
+	return 0, false
+}
+
+// rewind returns a copy of the request for a retry.
+func rewind(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.Body = body
+	return r2, true
+}
+
+// rateLimitedResponse answers a request that was not sent because
+// it would wait too long for the rate limit. The 429 status lets
+// the webhook queue retry the webhook later.
+func rateLimitedResponse(req *http.Request, wait time.Duration) *http.Response {
+	body := fmt.Sprintf(`{"message":"GitHub rate limit exceeded, retry in %s"}`, wait/time.Second*time.Second)
+	return &http.Response{
+		Status:     "429 Too Many Requests",
+		StatusCode: http.StatusTooManyRequests,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+			"Retry-After":  {strconv.Itoa(int(wait / time.Second))},
+		},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func sleepContext(c context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+// rateLimitRoundTrip sends the request once the rate limit of the
+// token for the resource of the request allows it. Requests that hit
+// a secondary rate limit are retried up to GITHUB_ABUSE_RETRIES times.
+func rateLimitRoundTrip(key, login string, req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	l := tokenRateLimit(key, login, requestResource(req))
+	for attempt := 0; ; attempt++ {
+		wait, ok := l.reserve(rateNow())
+		if !ok {
+			log.Warnf("Rejecting GitHub request of %s, rate limited for %s", l.state.Login, wait)
+			return rateLimitedResponse(req, wait), nil
+		}
+		if err := rateSleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		resp, err := send(req)
+		if err != nil {
+			return resp, err
+		}
+		now := rateNow()
+		if resource := resp.Header.Get("X-RateLimit-Resource"); resource != "" && resource != l.state.Resource {
+			l = tokenRateLimit(key, login, resource)
+		}
+		l.update(resp.Header, now)
+		delay, limited := rateLimited(resp, attempt)
+		if !limited || attempt >= envvars.Env.Github.AbuseRetries {
+			return resp, nil
+		}
+		next, ok := rewind(req)
+		if !ok {
+			return resp, nil
+		}
+		log.Debugf("GitHub request %s %s rate limited, retrying in %s", req.Method, req.URL.Path, delay)
+		l.retry(now.Add(delay))
+		resp.Body.Close()
+		req = next
+	}
+}
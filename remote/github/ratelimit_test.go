@@ -0,0 +1,206 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+)
+
+func setupRateLimit(t *testing.T) func() {
+	low, maxWait, retries := envvars.Env.Github.RateLow, envvars.Env.Github.RateMaxWait, envvars.Env.Github.AbuseRetries
+	envvars.Env.Github.RateLow = 100
+	envvars.Env.Github.RateMaxWait = time.Minute
+	envvars.Env.Github.AbuseRetries = 2
+	var slept time.Duration
+	rateSleep = func(c context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	}
+	return func() {
+		envvars.Env.Github.RateLow, envvars.Env.Github.RateMaxWait, envvars.Env.Github.AbuseRetries = low, maxWait, retries
+		rateSleep = sleepContext
+		rateNow = time.Now
+	}
+}
+
+func TestRateLimitReserve(t *testing.T) {
+	defer setupRateLimit(t)()
+	now := time.Unix(1500000000, 0)
+	l := &rateLimit{state: model.RateLimit{Login: "octocat"}}
+
+	if wait, ok := l.reserve(now); !ok || wait != 0 {
+		t.Errorf("Expected no wait without rate limit headers, got %s", wait)
+	}
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", "4000")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+	l.update(h, now)
+	if wait, ok := l.reserve(now); !ok || wait != 0 {
+		t.Errorf("Expected no wait above the low rate limit, got %s", wait)
+	}
+
+	// 9 requests remaining for 100 seconds are spaced by 10 seconds
+	h.Set("X-RateLimit-Remaining", "9")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(100*time.Second).Unix(), 10))
+	l.update(h, now)
+	for i := 0; i < 3; i++ {
+		wait, ok := l.reserve(now)
+		if !ok || wait != time.Duration(i)*10*time.Second {
+			t.Errorf("Expected paced request %d to wait %ds, got %s", i, i*10, wait)
+		}
+	}
+
+	// an exhausted rate limit waits for the reset, if it is soon enough
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(30*time.Second).Unix(), 10))
+	l.update(h, now)
+	if wait, ok := l.reserve(now); !ok || wait != 30*time.Second {
+		t.Errorf("Expected to wait for the reset, got %s", wait)
+	}
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+	l.update(h, now)
+	if _, ok := l.reserve(now); ok {
+		t.Error("Expected the request to be rejected")
+	}
+	if l.state.Rejected != 1 || l.state.Delayed != 3 {
+		t.Errorf("Expected 1 rejected and 3 delayed requests, got %d and %d", l.state.Rejected, l.state.Delayed)
+	}
+}
+
+func TestRateLimitAbuseRetry(t *testing.T) {
+	defer setupRateLimit(t)()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have triggered an abuse detection mechanism."}`))
+			return
+		}
+		w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer ts.Close()
+
+	client := createClient(context.Background(), ts.URL+"/", "abuse-token", "octocat")
+	_, _, err := client.Users.Get(context.Background(), "octocat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the request to be retried, got %d calls", calls)
+	}
+	for _, rl := range RateLimits() {
+		if rl.Login == "octocat" && rl.Retried != 1 {
+			t.Errorf("Expected one retry, got %d", rl.Retried)
+		}
+	}
+}
+
+func TestRateLimitAbuseBody(t *testing.T) {
+	defer setupRateLimit(t)()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+	}))
+	defer ts.Close()
+
+	client := createClient(context.Background(), ts.URL+"/", "secondary-token", "hubot")
+	_, resp, err := client.Users.Get(context.Background(), "hubot")
+	if err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected 403 after the retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 2 retries, got %d calls", calls-1)
+	}
+}
+
+func TestRateLimitRejected(t *testing.T) {
+	defer setupRateLimit(t)()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer ts.Close()
+
+	client := createClient(context.Background(), ts.URL+"/", "exhausted-token", "monalisa")
+	_, resp, err := client.Users.Get(context.Background(), "monalisa")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if status := createError(resp, err).(exterror.ExtError).Status; status != http.StatusTooManyRequests {
+		t.Errorf("Expected 429, got %d", status)
+	}
+	_, _, err = client.Users.Get(context.Background(), "monalisa")
+	if err == nil || calls != 1 {
+		t.Errorf("Expected the next request not to be sent, got %d calls", calls)
+	}
+}
+
+func TestRateLimitResources(t *testing.T) {
+	defer setupRateLimit(t)()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		if r.URL.Path == "/search/issues" {
+			w.Header().Set("X-RateLimit-Limit", "30")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Resource", "search")
+		}
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := createClient(context.Background(), ts.URL+"/", "resource-token", "mona")
+	client.Search.Issues(context.Background(), "is:open", nil)
+	_, _, err := client.Users.Get(context.Background(), "mona")
+	if err != nil || calls != 2 {
+		t.Errorf("Expected an exhausted search rate limit not to hold core requests, got %v", err)
+	}
+	if core := TokenRateLimit("resource-token"); core == nil || core.Resource != coreResource || core.Remaining != 4000 {
+		t.Errorf("Expected the core rate limit, got %v", core)
+	}
+	_, _, err = client.Search.Issues(context.Background(), "is:open", nil)
+	if err == nil || calls != 2 {
+		t.Errorf("Expected the exhausted search request not to be sent, got %d calls", calls)
+	}
+}
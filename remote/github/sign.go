@@ -0,0 +1,65 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+)
+
+// tagPayload generates the content of the git tag object
+// that is covered by the signature. The remote system must
+// generate an identical tag object for the signature to be valid.
+func tagPayload(tag model.AnnotatedTag, name, email string, t time.Time, message string) []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "object %s\n", tag.SHA)
+	fmt.Fprintf(&buffer, "type commit\n")
+	fmt.Fprintf(&buffer, "tag %s\n", tag.Name)
+	fmt.Fprintf(&buffer, "tagger %s <%s> %d +0000\n", name, email, t.Unix())
+	fmt.Fprintf(&buffer, "\n%s", message)
+	return buffer.Bytes()
+}
+
+// signPayload creates an ASCII armored detached signature
+// using the same gpg arguments as "git tag --sign".
+func signPayload(ctx context.Context, payload []byte) ([]byte, error) {
+	key := envvars.Env.Signing.GpgKey
+	if len(key) == 0 {
+		return nil, fmt.Errorf("Unable to sign tag. Missing environment variable GPG_SIGNING_KEY")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, envvars.Env.Signing.GpgProgram, "--status-fd=2", "-bsau", key)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to sign tag. %s %s", err, stderr.String())
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("\n[GNUPG:] SIG_CREATED ")) {
+		return nil, fmt.Errorf("Unable to sign tag. %s", stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
@@ -23,10 +23,13 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/set"
 	"github.com/capitalone/checks-out/usage"
 
 	"github.com/google/go-github/github"
@@ -41,6 +44,9 @@ const (
 type UserTransport struct {
 	Login string
 	Event string
+	// token identifies the token of the client in the ETag
+	// cache and the rate limits
+	token string
 	// Transport is the underlying HTTP transport to use when making requests.
 	// It will default to http.DefaultTransport if nil.
 	Transport http.RoundTripper
@@ -49,8 +55,20 @@ type UserTransport struct {
 // RoundTrip implements the RoundTripper interface.
 func (t *UserTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	caller := t.caller()
-	usage.RecordApiRequest(t.Login, t.Event, caller)
-	return t.transport().RoundTrip(req)
+	usage.RecordApiRequest(t.Login, t.Event)
+	record := func(r *http.Request) (*http.Response, error) {
+		resp, err := t.transport().RoundTrip(r)
+		if err != nil {
+			metrics.GitHubRequests.Inc(caller, "error")
+		} else {
+			metrics.GitHubRequests.Inc(caller, strconv.Itoa(resp.StatusCode))
+		}
+		return resp, err
+	}
+	send := func(r *http.Request) (*http.Response, error) {
+		return etags.roundTrip(t.token, r, record)
+	}
+	return rateLimitRoundTrip(t.token, t.Login, req, send)
 }
 
 func locateParent(frames *runtime.Frames) bool {
@@ -104,6 +122,7 @@ func createClient(ctx context.Context, rawurl, accessToken, login string) *githu
 	client.Transport = &UserTransport{
 		Login:     login,
 		Event:     usage.GetEventFromContext(ctx),
+		token:     tokenKey(accessToken),
 		Transport: client.Transport}
 	g := github.NewClient(client)
 	g.BaseURL, _ = url.Parse(rawurl)
@@ -176,12 +195,54 @@ func getOrgHook(ctx context.Context, client *github.Client, owner, rawurl string
 	return nil, nil
 }
 
+// repoHookEvents and orgHookEvents are the events that the
+// webhooks of repositories and organizations subscribe to.
+var (
+	repoHookEvents = []string{"issue_comment", "status", "pull_request", "pull_request_review", "deployment_status", "push"}
+	orgHookEvents  = []string{"repository", "membership", "team", "organization"}
+)
+
+// getTokenHook is a helper function that returns the hook
+// whose url carries the access token, or nil if no hook does.
+func getTokenHook(hooks []*github.Hook, token string) *github.Hook {
+	for _, hook := range hooks {
+		hookurl, ok := hook.Config["url"].(string)
+		if !ok {
+			continue
+		}
+		parsed, err := url.Parse(hookurl)
+		if err != nil {
+			continue
+		}
+		if parsed.Query().Get("access_token") == token {
+			return hook
+		}
+	}
+	return nil
+}
+
+// missingEvents is a helper function that returns
+// the events that the hook does not subscribe to.
+func missingEvents(hook *github.Hook, events []string) []string {
+	current := set.New(hook.Events...)
+	if current.Contains("*") {
+		return nil
+	}
+	var missing []string
+	for _, event := range events {
+		if !current.Contains(event) {
+			missing = append(missing, event)
+		}
+	}
+	return missing
+}
+
 // createHook is a helper function that creates a post-commit hook
 // for the specified repository.
 func createHook(ctx context.Context, client *github.Client, owner, name, url string) (*github.Hook, error) {
 	var hook = new(github.Hook)
 	hook.Name = github.String("web")
-	hook.Events = []string{"issue_comment", "status", "pull_request", "pull_request_review"}
+	hook.Events = repoHookEvents
 	hook.Config = map[string]interface{}{}
 	hook.Config["url"] = url
 	hook.Config["content_type"] = "json"
@@ -197,7 +258,7 @@ func createHook(ctx context.Context, client *github.Client, owner, name, url str
 func createOrgHook(ctx context.Context, client *github.Client, owner, url string) (*github.Hook, error) {
 	var hook = new(github.Hook)
 	hook.Name = github.String("web")
-	hook.Events = []string{"repository"}
+	hook.Events = orgHookEvents
 	hook.Config = map[string]interface{}{}
 	hook.Config["url"] = url
 	hook.Config["content_type"] = "json"
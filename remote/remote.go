@@ -72,6 +72,10 @@ type Remote interface {
 	// DelHook deletes a webhook from the remote repository.
 	DelHook(context.Context, *model.User, *model.Repo, string) error
 
+	// UpgradeHook subscribes the webhook of the remote repository whose
+	// url carries the access token to the events it is missing.
+	UpgradeHook(context.Context, *model.User, *model.Repo, string) error
+
 	// GetAllComments gets pull request comments from the remote system.
 	GetAllComments(context.Context, *model.User, *model.Repo, int) ([]*model.Comment, error)
 
@@ -115,7 +119,7 @@ type Remote interface {
 	CreatePR(c context.Context, u *model.User, r *model.Repo, title, head, base, body string) (int, error)
 
 	// MergePR merges the named pull request from the remote system
-	MergePR(c context.Context, u *model.User, r *model.Repo, pullRequest model.PullRequest, approvers []*model.Person, message string, mergeMethod string) (string, error)
+	MergePR(c context.Context, u *model.User, r *model.Repo, pullRequest model.PullRequest, message string, mergeMethod string) (string, error)
 
 	// CompareBranches compares two branches for changes
 	CompareBranches(c context.Context, u *model.User, repo *model.Repo, base string, head string, owner string) (model.BranchCompare, error)
@@ -129,6 +133,18 @@ type Remote interface {
 	// Tag applies a tag with the specified string to the specified sha
 	Tag(c context.Context, u *model.User, r *model.Repo, tag string, sha string) error
 
+	// CreateAnnotatedTag creates an annotated tag object and a tag reference to it
+	CreateAnnotatedTag(c context.Context, u *model.User, r *model.Repo, tag model.AnnotatedTag) error
+
+	// ListMergedPullRequests returns the pull requests merged into the base branch between two refs
+	ListMergedPullRequests(c context.Context, u *model.User, r *model.Repo, base, from, to string) ([]model.PullRequest, error)
+
+	// ListOpenPullRequests returns the open pull requests of a repository
+	ListOpenPullRequests(c context.Context, u *model.User, r *model.Repo) ([]model.PullRequest, error)
+
+	// CreateRelease creates a release for an existing tag and returns the release URL
+	CreateRelease(c context.Context, u *model.User, r *model.Repo, release model.Release) (string, error)
+
 	// GetPullRequest returns the pull request associated with a pull request number
 	GetPullRequest(c context.Context, u *model.User, r *model.Repo, number int) (model.PullRequest, error)
 
@@ -138,6 +154,10 @@ type Remote interface {
 	// GetPullRequestCommits returns the commits associated with a pull request number
 	GetPullRequestCommits(c context.Context, u *model.User, r *model.Repo, number int) ([]model.Commit, error)
 
+	// LoadPullRequestData returns the files, commits, comments and reviews of
+	// a pull request with a few GraphQL queries
+	LoadPullRequestData(c context.Context, u *model.User, r *model.Repo, number int, noUIMerge bool) (*model.PullRequestData, error)
+
 	// GetPullRequestsForCommit returns all pull requests associated with a commit SHA
 	GetPullRequestsForCommit(c context.Context, u *model.User, r *model.Repo, sha *string) ([]model.PullRequest, error)
 
@@ -147,6 +167,13 @@ type Remote interface {
 	// WriteComment puts a new comment into the PR
 	WriteComment(c context.Context, u *model.User, r *model.Repo, num int, message string) error
 
+	// GetStickyComment returns the id and the body of the comment in the PR
+	// that contains the marker. The id is 0 if there is no such comment.
+	GetStickyComment(c context.Context, u *model.User, r *model.Repo, num int, marker string) (int64, string, error)
+
+	// EditComment replaces the body of a comment
+	EditComment(c context.Context, u *model.User, r *model.Repo, id int64, message string) error
+
 	ScheduleDeployment(c context.Context, u *model.User, r *model.Repo, d model.DeploymentInfo) error
 
 	GetOrgPerm(c context.Context, user *model.User, owner string) (*model.Perm, error)
@@ -157,6 +184,10 @@ type Remote interface {
 	// DelOrgHook deletes a webhook from the remote organization.
 	DelOrgHook(context.Context, *model.User, *model.OrgDb, string) error
 
+	// UpgradeOrgHook subscribes the webhook of the remote organization whose
+	// url carries the access token to the events it is missing.
+	UpgradeOrgHook(context.Context, *model.User, *model.OrgDb, string) error
+
 	GetOrg(c context.Context, user *model.User, owner string) (*model.OrgDb, error)
 
 	// GetOrgRepos gets the organization repository list from the remote system.
@@ -247,6 +278,12 @@ func DelHook(c context.Context, u *model.User, r *model.Repo, hook string) error
 	return FromContext(c).DelHook(c, u, r, hook)
 }
 
+// UpgradeHook subscribes the webhook of the remote repository whose
+// url carries the access token to the events it is missing.
+func UpgradeHook(c context.Context, u *model.User, r *model.Repo, token string) error {
+	return FromContext(c).UpgradeHook(c, u, r, token)
+}
+
 // GetStatus gets the commit statuses in the remote system.
 func GetStatus(c context.Context, u *model.User, r *model.Repo, sha string) (model.CombinedStatus, error) {
 	return FromContext(c).GetStatus(c, u, r, sha)
@@ -277,8 +314,8 @@ func CreatePR(c context.Context, u *model.User, r *model.Repo, title, head, base
 	return FromContext(c).CreatePR(c, u, r, title, head, base, body)
 }
 
-func MergePR(c context.Context, u *model.User, r *model.Repo, pullRequest model.PullRequest, approvers []*model.Person, message string, mergeMethod string) (string, error) {
-	return FromContext(c).MergePR(c, u, r, pullRequest, approvers, message, mergeMethod)
+func MergePR(c context.Context, u *model.User, r *model.Repo, pullRequest model.PullRequest, message string, mergeMethod string) (string, error) {
+	return FromContext(c).MergePR(c, u, r, pullRequest, message, mergeMethod)
 }
 
 func CompareBranches(c context.Context, u *model.User, r *model.Repo, ref1 string, ref2 string, owner string) (model.BranchCompare, error) {
@@ -297,6 +334,26 @@ func Tag(c context.Context, u *model.User, r *model.Repo, tag string, sha string
 	return FromContext(c).Tag(c, u, r, tag, sha)
 }
 
+// CreateAnnotatedTag creates an annotated tag object and a tag reference to it
+func CreateAnnotatedTag(c context.Context, u *model.User, r *model.Repo, tag model.AnnotatedTag) error {
+	return FromContext(c).CreateAnnotatedTag(c, u, r, tag)
+}
+
+// ListMergedPullRequests returns the pull requests merged into the base branch between two refs
+func ListMergedPullRequests(c context.Context, u *model.User, r *model.Repo, base, from, to string) ([]model.PullRequest, error) {
+	return FromContext(c).ListMergedPullRequests(c, u, r, base, from, to)
+}
+
+// ListOpenPullRequests returns the open pull requests of a repository
+func ListOpenPullRequests(c context.Context, u *model.User, r *model.Repo) ([]model.PullRequest, error) {
+	return FromContext(c).ListOpenPullRequests(c, u, r)
+}
+
+// CreateRelease creates a release for an existing tag and returns the release URL
+func CreateRelease(c context.Context, u *model.User, r *model.Repo, release model.Release) (string, error) {
+	return FromContext(c).CreateRelease(c, u, r, release)
+}
+
 func GetPullRequest(c context.Context, u *model.User, r *model.Repo, number int) (model.PullRequest, error) {
 	return FromContext(c).GetPullRequest(c, u, r, number)
 }
@@ -309,6 +366,12 @@ func GetPullRequestCommits(c context.Context, u *model.User, r *model.Repo, numb
 	return FromContext(c).GetPullRequestCommits(c, u, r, number)
 }
 
+// LoadPullRequestData returns the files, commits, comments and reviews of
+// a pull request with a few GraphQL queries
+func LoadPullRequestData(c context.Context, u *model.User, r *model.Repo, number int, noUIMerge bool) (*model.PullRequestData, error) {
+	return FromContext(c).LoadPullRequestData(c, u, r, number, noUIMerge)
+}
+
 func GetPullRequestsForCommit(c context.Context, u *model.User, r *model.Repo, sha *string) ([]model.PullRequest, error) {
 	return FromContext(c).GetPullRequestsForCommit(c, u, r, sha)
 }
@@ -322,6 +385,17 @@ func WriteComment(c context.Context, u *model.User, r *model.Repo, num int, mess
 	return FromContext(c).WriteComment(c, u, r, num, message)
 }
 
+// GetStickyComment returns the id and the body of the comment in the PR
+// that contains the marker. The id is 0 if there is no such comment.
+func GetStickyComment(c context.Context, u *model.User, r *model.Repo, num int, marker string) (int64, string, error) {
+	return FromContext(c).GetStickyComment(c, u, r, num, marker)
+}
+
+// EditComment replaces the body of a comment
+func EditComment(c context.Context, u *model.User, r *model.Repo, id int64, message string) error {
+	return FromContext(c).EditComment(c, u, r, id, message)
+}
+
 func ScheduleDeployment(c context.Context, u *model.User, r *model.Repo, d model.DeploymentInfo) error {
 	return FromContext(c).ScheduleDeployment(c, u, r, d)
 }
@@ -340,6 +414,12 @@ func DelOrgHook(c context.Context, u *model.User, o *model.OrgDb, hook string) e
 	return FromContext(c).DelOrgHook(c, u, o, hook)
 }
 
+// UpgradeOrgHook subscribes the webhook of the remote organization whose
+// url carries the access token to the events it is missing.
+func UpgradeOrgHook(c context.Context, u *model.User, o *model.OrgDb, token string) error {
+	return FromContext(c).UpgradeOrgHook(c, u, o, token)
+}
+
 func GetOrg(c context.Context, user *model.User, owner string) (*model.OrgDb, error) {
 	return FromContext(c).GetOrg(c, user, owner)
 }
@@ -352,6 +432,18 @@ func GetOrgRepos(c context.Context, user *model.User, owner string) ([]*model.Re
 	return FromContext(c).GetOrgRepos(c, user, owner)
 }
 
+// RateLimits returns the GitHub rate limit state of the tokens
+// that made requests.
+func RateLimits() []*model.RateLimit {
+	return github.RateLimits()
+}
+
+// RateLimit returns the GitHub core rate limit state of the token of
+// the user, or nil when the token did not make requests.
+func RateLimit(u *model.User) *model.RateLimit {
+	return github.TokenRateLimit(u.Token)
+}
+
 var once sync.Once
 var cachedRemote Remote
 
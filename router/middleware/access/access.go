@@ -96,3 +96,33 @@ func RepoPull(c *gin.Context) {
 	log.Debugf("User %s granted Pull access to %s/%s", user.Login, owner, name)
 	c.Next()
 }
+
+func RepoPush(c *gin.Context) {
+	var (
+		owner = c.Param("owner")
+		name  = c.Param("repo")
+		user  = session.User(c)
+	)
+
+	perm, err := remote.GetPerm(c, user, owner, name)
+	if err != nil {
+		log.Warnf("Cannot find repository %s/%s. %s", owner, name, err)
+		c.String(404, "Not Found")
+		c.Abort()
+		return
+	}
+	if !perm.Pull {
+		log.Warnf("User %s does not have Pull access to repository %s/%s", user.Login, owner, name)
+		c.String(404, "Not Found")
+		c.Abort()
+		return
+	}
+	if !perm.Push {
+		log.Warnf("User %s does not have Push access to repository %s/%s", user.Login, owner, name)
+		c.String(403, "Insufficient privileges")
+		c.Abort()
+		return
+	}
+	log.Debugf("User %s granted Push access to %s/%s", user.Login, owner, name)
+	c.Next()
+}
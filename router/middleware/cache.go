@@ -19,15 +19,12 @@ See the License for the specific language governing permissions and limitations
 package middleware
 
 import (
-	"time"
-
 	"github.com/gin-gonic/gin"
 	"github.com/capitalone/checks-out/cache"
-	"github.com/capitalone/checks-out/envvars"
 )
 
 func Cache() gin.HandlerFunc {
-	cache_ := cache.NewTTL(time.Duration(envvars.Env.Cache.CacheTTL))
+	cache_ := cache.Shared()
 	return func(c *gin.Context) {
 		cache.ToContext(c,  cache_)
 		c.Next()
@@ -63,14 +63,38 @@ func Load() http.Handler {
 
 	adminGroup := e.Group("/admin", session.UserMust, api.CheckAdmin)
 	adminGroup.GET("config/subtree/*path", api.GetAllConfigurationSubtree)
-	adminGroup.POST("slack/:hostname", api.RegisterSlackUrl)
-	adminGroup.GET("slack/:hostname", api.GetSlackUrl)
-	adminGroup.DELETE("slack/:hostname", api.DeleteSlackUrl)
-	adminGroup.PUT("slack/:hostname", api.UpdateSlackUrl)
+	adminGroup.POST("slack/:hostname", api.SlackTarget.Register)
+	adminGroup.GET("slack/:hostname", api.SlackTarget.Get)
+	adminGroup.DELETE("slack/:hostname", api.SlackTarget.Delete)
+	adminGroup.PUT("slack/:hostname", api.SlackTarget.Update)
+	adminGroup.POST("teams/:hostname", api.TeamsTarget.Register)
+	adminGroup.GET("teams/:hostname", api.TeamsTarget.Get)
+	adminGroup.DELETE("teams/:hostname", api.TeamsTarget.Delete)
+	adminGroup.PUT("teams/:hostname", api.TeamsTarget.Update)
+	adminGroup.POST("webhook/:hostname", api.WebhookTarget.Register)
+	adminGroup.GET("webhook/:hostname", api.WebhookTarget.Get)
+	adminGroup.DELETE("webhook/:hostname", api.WebhookTarget.Delete)
+	adminGroup.PUT("webhook/:hostname", api.WebhookTarget.Update)
 	adminGroup.DELETE("repos/:owner", api.AdminDeleteOrg)
 	adminGroup.DELETE("repos/:owner/:repo", api.AdminDeleteRepo)
 	adminGroup.GET("user/:user/repos", api.GetReposForUserLogin)
 	adminGroup.GET("stats", api.AdminStats)
+	adminGroup.GET("ratelimits", api.GetRateLimits)
+	adminGroup.GET("outbox", api.GetOutbox)
+	adminGroup.GET("outbox/:id", api.GetOutboxMessage)
+	adminGroup.POST("outbox/:id/retry", api.RetryOutboxMessage)
+	adminGroup.DELETE("outbox/:id", api.DeleteOutboxMessage)
+	adminGroup.GET("hooks", api.GetHookJobs)
+	adminGroup.GET("hooks/:delivery", api.GetHookJob)
+	adminGroup.POST("hooks/:delivery/retry", web.RetryHookJob)
+	adminGroup.GET("deliveries", api.GetHookDeliveries)
+	adminGroup.GET("deliveries/:id", api.GetHookDelivery)
+	adminGroup.POST("deliveries/:id/replay", web.ReplayHookDelivery)
+	adminGroup.POST("reevaluate/:owner", web.ReevaluateOrg)
+	adminGroup.POST("reevaluate/:owner/:repo", web.ReevaluateRepo)
+	adminGroup.POST("reevaluate/:owner/:repo/:id", web.ReevaluatePullRequest)
+	adminGroup.DELETE("cache/:owner", api.FlushOrgCache)
+	adminGroup.DELETE("cache/:owner/:repo", api.FlushRepoCache)
 
 	e.GET("/api/user", session.UserMust, api.GetUser)
 	e.DELETE("/api/user", session.UserMust, api.DeleteUser)
@@ -86,18 +110,28 @@ func Load() http.Handler {
 	e.GET("/api/repos/:owner/:repo/maintainers", session.UserMust, access.RepoPull, api.GetMaintainer)
 	e.GET("/api/repos/:owner/:repo/validate", session.UserMust, access.RepoPull, api.Validate)
 	e.GET("/api/repos/:owner/:repo/lgtm-to-checks-out", session.UserMust, access.RepoPull, api.Convert)
+	e.GET("/api/repos/:owner/:repo/deployments", session.UserMust, access.RepoPull, web.PendingDeployments)
 
 	e.GET("/api/teams/:owner", session.UserMust, api.GetTeams)
 
 	e.GET("/api/pr/:owner/:repo/:id/status", session.UserMust, access.RepoPull, web.ApprovalStatus)
+	e.POST("/api/pr/:owner/:repo/:id/deploy/:env", session.UserMust, access.RepoPush, web.ApproveDeployment)
 
 	e.POST("/api/repos/:owner", session.UserMust, access.OwnerAdmin, api.PostOrg)
 	e.DELETE("/api/repos/:owner", session.UserMust, access.OwnerAdmin, api.DeleteOrg)
 
-	e.POST("/api/user/slack/:hostname", session.UserMust, api.UserRegisterSlackUrl)
-	e.GET("/api/user/slack/:hostname", session.UserMust, api.UserGetSlackUrl)
-	e.DELETE("/api/user/slack/:hostname", session.UserMust, api.UserDeleteSlackUrl)
-	e.PUT("/api/user/slack/:hostname", session.UserMust, api.UserUpdateSlackUrl)
+	e.POST("/api/user/slack/:hostname", session.UserMust, api.SlackTarget.UserRegister)
+	e.GET("/api/user/slack/:hostname", session.UserMust, api.SlackTarget.UserGet)
+	e.DELETE("/api/user/slack/:hostname", session.UserMust, api.SlackTarget.UserDelete)
+	e.PUT("/api/user/slack/:hostname", session.UserMust, api.SlackTarget.UserUpdate)
+	e.POST("/api/user/teams/:hostname", session.UserMust, api.TeamsTarget.UserRegister)
+	e.GET("/api/user/teams/:hostname", session.UserMust, api.TeamsTarget.UserGet)
+	e.DELETE("/api/user/teams/:hostname", session.UserMust, api.TeamsTarget.UserDelete)
+	e.PUT("/api/user/teams/:hostname", session.UserMust, api.TeamsTarget.UserUpdate)
+	e.POST("/api/user/webhook/:hostname", session.UserMust, api.WebhookTarget.UserRegister)
+	e.GET("/api/user/webhook/:hostname", session.UserMust, api.WebhookTarget.UserGet)
+	e.DELETE("/api/user/webhook/:hostname", session.UserMust, api.WebhookTarget.UserDelete)
+	e.PUT("/api/user/webhook/:hostname", session.UserMust, api.WebhookTarget.UserUpdate)
 
 	if sunlight {
 		e.GET("/api/repos", api.GetAllRepos)
@@ -112,8 +146,13 @@ func Load() http.Handler {
 		}
 	}
 	e.GET("/api/count", api.GetAllReposCount)
+	e.GET("/metrics", api.Metrics)
 
 	e.POST("/hook", web.ProcessHook)
+	e.POST("/slack/command", web.SlackCommand)
+	e.POST("/slack/action", web.SlackAction)
+	e.GET("/slack/link", session.UserMust, web.SlackLink)
+	e.POST("/slack/link", session.UserMust, web.SlackLinkConfirm)
 	e.GET("/login", web.Login)
 	e.POST("/login", web.LoginToken)
 	e.GET("/logout", web.Logout)
@@ -39,7 +39,9 @@ var ConfigTemplateName = fmt.Sprintf("template.%s", envvars.Env.Branding.Name)
 
 var configFileName = fmt.Sprintf(".%s", envvars.Env.Branding.Name)
 
-var orgRepoName = fmt.Sprintf("%s-configuration", envvars.Env.Branding.Name)
+// OrgRepoName is the repository of an organization that holds
+// the template configuration and maintainers files.
+var OrgRepoName = fmt.Sprintf("%s-configuration", envvars.Env.Branding.Name)
 
 const MaintainersTemplateName = "template.MAINTAINERS"
 
@@ -77,11 +79,11 @@ func findConfig(c context.Context, user *model.User, caps *model.Capabilities, r
 		return cfg, nil
 	}
 	// look for template configuration file in org repository
-	if !repo.Org || len(orgRepoName) == 0 {
+	if !repo.Org || len(OrgRepoName) == 0 {
 		return nil, exterr
 	}
 	orgRepo := *repo
-	orgRepo.Name = orgRepoName
+	orgRepo.Name = OrgRepoName
 	_, err = remote.GetRepo(c, user, orgRepo.Owner, orgRepo.Name)
 	if err != nil {
 		ext, ok := err.(exterror.ExtError)
@@ -101,6 +103,23 @@ func findConfig(c context.Context, user *model.User, caps *model.Capabilities, r
 	return cfg, nil
 }
 
+// ConfigFiles returns the files of a repository
+// that its configuration is read from.
+func ConfigFiles(config *model.Config) set.Set {
+	files := set.New(configFileName, ".lgtm", config.Maintainers.Path)
+	if config.Deployment.Enable {
+		files.Add(config.Deployment.Path)
+	}
+	return files
+}
+
+// OrgConfigFiles returns the files of the organization
+// repository that the configuration of a repository
+// without its own files is read from.
+func OrgConfigFiles() set.Set {
+	return set.New(ConfigTemplateName, MaintainersTemplateName)
+}
+
 func GetConfig(c context.Context, user *model.User, caps *model.Capabilities, repo *model.Repo) (*model.Config, error) {
 	config, err := findConfig(c, user, caps, repo)
 	if err != nil {
@@ -114,7 +133,12 @@ func GetConfig(c context.Context, user *model.User, caps *model.Capabilities, re
 			msg := fmt.Sprintf("%s file not found", config.Deployment.Path)
 			return nil, exterror.Append(err, msg)
 		}
-		config.LoadDeploymentMap(deployFile)
+		err = config.LoadDeploymentMap(deployFile)
+		if err != nil {
+			err = badRequest(err)
+			msg := fmt.Sprintf("Parsing %s file", config.Deployment.Path)
+			return nil, exterror.Append(err, msg)
+		}
 	}
 	return config, nil
 }
@@ -131,12 +155,17 @@ func GetConfigAndMaintainers(c context.Context, user *model.User, caps *model.Ca
 	return config, snapshot, err
 }
 
+// FixSlackTargets resolves the webhook URL of each comment target.
+// Hostname targets are looked up in the registered Slack URLs, then
+// in the registered Teams URLs and then in the registered webhook
+// targets, preferring the URL registered by the user over the
+// admin-level URL.
 func FixSlackTargets(c context.Context, config *model.Config, user string) error {
 	for k, v := range config.Comment.Targets {
 		// we're potentially going to modify the curTarget
 		curTarget := &config.Comment.Targets[k]
-		//skip over the github targets
-		if v.Target == model.Github.String() {
+		//skip over the github and email targets
+		if v.Target == model.Github.String() || v.Target == model.Email.String() {
 			continue
 		}
 		if v.Target == model.Slack.String() {
@@ -146,37 +175,88 @@ func FixSlackTargets(c context.Context, config *model.Config, user string) error
 			}
 			continue
 		}
+		if v.Target == model.Teams.String() {
+			//set URL to the default teams URL if no override URL was specified
+			if v.Url == "" {
+				curTarget.Url = envvars.Env.Teams.TargetUrl
+			}
+			continue
+		}
+		if v.Target == model.Webhook.String() {
+			//set URL and secret to the default webhook if no override URL was specified
+			if v.Url == "" {
+				curTarget.Url = envvars.Env.Webhook.TargetUrl
+				curTarget.Secret = envvars.Env.Webhook.Secret
+			} else if v.Secret == "" {
+				// deliveries to an override URL are signed with the secret of a registered webhook
+				return errors.New("Webhook URL " + v.Url + " is not registered with a signing secret")
+			}
+			continue
+		}
 		// for everything else try to find the url for it
-		// if found, change target to slack and set the url
-		url, err := store.GetSlackUrl(c, v.Target, user)
+		// if found, change target to slack, teams or webhook and set the url
+		url, err := findTargetUrl(c, store.GetSlackUrl, v.Target, user)
+		if err != nil {
+			return err
+		}
+		if url != "" {
+			curTarget.Target = model.Slack.String()
+			curTarget.Url = url
+			continue
+		}
+		url, err = findTargetUrl(c, store.GetTeamsUrl, v.Target, user)
 		if err != nil {
 			return err
 		}
-		if url == "" {
-			url, err = store.GetSlackUrl(c, v.Target, "")
+		if url != "" {
+			curTarget.Target = model.Teams.String()
+			curTarget.Url = url
+			continue
 		}
+		webhook, err := findWebhookTarget(c, v.Target, user)
 		if err != nil {
 			return err
 		}
-		if url == "" {
-			return errors.New("No URL found for slack host " + v.Target)
+		if webhook == nil {
+			return errors.New("No URL found for slack, teams or webhook host " + v.Target)
 		}
-		curTarget.Target = model.Slack.String()
-		curTarget.Url = url
+		curTarget.Target = model.Webhook.String()
+		curTarget.Url = webhook.Url
+		curTarget.Secret = webhook.Secret
+		curTarget.Host = v.Target
+		curTarget.User = user
 	}
 	return nil
 }
 
+type urlLookup func(c context.Context, hostname string, user string) (string, error)
+
+func findTargetUrl(c context.Context, lookup urlLookup, hostname string, user string) (string, error) {
+	url, err := lookup(c, hostname, user)
+	if err != nil || url != "" {
+		return url, err
+	}
+	return lookup(c, hostname, "")
+}
+
+func findWebhookTarget(c context.Context, hostname string, user string) (*model.WebhookTarget, error) {
+	target, err := store.GetWebhookTarget(c, hostname, user)
+	if err != nil || target != nil {
+		return target, err
+	}
+	return store.GetWebhookTarget(c, hostname, "")
+}
+
 func findMaintainers(c context.Context, user *model.User, repo *model.Repo, path string) ([]byte, error) {
 	file, err := remote.GetContents(c, user, repo, path)
 	if err == nil {
 		return file, nil
 	}
-	if !repo.Org || len(orgRepoName) == 0 {
+	if !repo.Org || len(OrgRepoName) == 0 {
 		return nil, exterror.Append(err, fmt.Sprintf("%s file not found", path))
 	}
 	orgRepo := *repo
-	orgRepo.Name = orgRepoName
+	orgRepo.Name = OrgRepoName
 	file, err = remote.GetContents(c, user, &orgRepo, MaintainersTemplateName)
 	if err == nil {
 		return file, nil
@@ -34,9 +34,9 @@ import (
 )
 
 func init() {
-	cache.Longterm.Set("people:Foo", &model.Person{Login: "Foo"})
-	cache.Longterm.Set("people:Bar", &model.Person{Login: "Bar"})
-	cache.Longterm.Set("people:Baz", &model.Person{Login: "Baz"})
+	cache.Longterm().Set("people:Foo", &model.Person{Login: "Foo"})
+	cache.Longterm().Set("people:Bar", &model.Person{Login: "Bar"})
+	cache.Longterm().Set("people:Baz", &model.Person{Login: "Baz"})
 }
 
 type mockRemote struct {
@@ -246,6 +246,23 @@ func (ms mockStore) GetSlackUrl(hostname string, user string) (string, error) {
 	return "", nil
 }
 
+func (ms mockStore) GetTeamsUrl(hostname string, user string) (string, error) {
+	if user == "" && hostname == "contoso.webhook.office.com" {
+		return "https://contoso.webhook.office.com/webhookb2/default", nil
+	}
+	if user == "jon" && hostname == "fabrikam.webhook.office.com" {
+		return "https://fabrikam.webhook.office.com/webhookb2/jon", nil
+	}
+	return "", nil
+}
+
+func (ms mockStore) GetWebhookTarget(hostname string, user string) (*model.WebhookTarget, error) {
+	if user == "" && hostname == "cm.example.com" {
+		return &model.WebhookTarget{Url: "https://cm.example.com/hooks", Secret: "s3cret"}, nil
+	}
+	return nil, nil
+}
+
 func TestFixSlackTargets(t *testing.T) {
 	envvars.Env.Slack.TargetUrl = "http://standard-url.com"
 	c := store.AddToContext(context.Background(), mockStore{})
@@ -297,3 +314,89 @@ func TestFixSlackTargets(t *testing.T) {
 		t.Errorf("Unexpected url %s", config.Comment.Targets[0].Url)
 	}
 }
+
+func TestFixTeamsAndWebhookTargets(t *testing.T) {
+	envvars.Env.Teams.TargetUrl = "https://standard.webhook.office.com/webhookb2/default"
+	envvars.Env.Webhook.TargetUrl = "https://default.example.com/hooks"
+	envvars.Env.Webhook.Secret = "default-secret"
+	c := store.AddToContext(context.Background(), mockStore{})
+	config := &model.Config{
+		Comment: model.CommentConfig{
+			Enable: true,
+			Targets: []model.TargetConfig{
+				{
+					Target: "contoso.webhook.office.com",
+				},
+				{
+					Target: "teams",
+				},
+				{
+					Target: "fabrikam.webhook.office.com",
+				},
+				{
+					Target: "floopy-server.slack.com",
+				},
+				{
+					Target: "cm.example.com",
+				},
+				{
+					Target: "webhook",
+				},
+				{
+					Target: "email",
+				},
+			},
+		},
+	}
+	err := FixSlackTargets(c, config, "jon")
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	expected := []model.TargetConfig{
+		{
+			Target: model.Teams.String(),
+			Url:    "https://contoso.webhook.office.com/webhookb2/default",
+		},
+		{
+			Target: model.Teams.String(),
+			Url:    "https://standard.webhook.office.com/webhookb2/default",
+		},
+		{
+			Target: model.Teams.String(),
+			Url:    "https://fabrikam.webhook.office.com/webhookb2/jon",
+		},
+		{
+			Target: model.Slack.String(),
+			Url:    "http://this-is-the-url.com",
+		},
+		{
+			Target: model.Webhook.String(),
+			Url:    "https://cm.example.com/hooks",
+			Secret: "s3cret",
+		},
+		{
+			Target: model.Webhook.String(),
+			Url:    "https://default.example.com/hooks",
+			Secret: "default-secret",
+		},
+		{
+			Target: model.Email.String(),
+		},
+	}
+	for i, target := range config.Comment.Targets {
+		if target.Target != expected[i].Target {
+			t.Errorf("Unexpected target %s at %d", target.Target, i)
+		}
+		if target.Url != expected[i].Url {
+			t.Errorf("Unexpected url %s at %d", target.Url, i)
+		}
+		if target.Secret != expected[i].Secret {
+			t.Errorf("Unexpected secret %s at %d", target.Secret, i)
+		}
+	}
+	config.Comment.Targets = []model.TargetConfig{{Target: "unknown.webhook.office.com"}}
+	err = FixSlackTargets(c, config, "jon")
+	if err == nil {
+		t.Error("Expected error for unregistered host")
+	}
+}
@@ -38,5 +38,9 @@ func validateSnapshot(config *model.Config, snapshot *model.MaintainerSnapshot)
 			errs = multierror.Append(errs, badRequest(err))
 		}
 	}
+	for _, match := range config.Deployment.Approvals {
+		err := match.Validate(snapshot)
+		errs = multierror.Append(errs, badRequest(err))
+	}
 	return errs
 }
@@ -0,0 +1,118 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"github.com/capitalone/checks-out/model"
+
+	"github.com/russross/meddler"
+)
+
+func (db *datastore) CreatePendingDeployment(pending *model.PendingDeployment) error {
+	return meddler.Insert(db, pendingTable, pending)
+}
+
+func (db *datastore) GetPendingDeployments(repoID int64) ([]*model.PendingDeployment, error) {
+	var pending = []*model.PendingDeployment{}
+	var err = meddler.QueryAll(db, &pending, pendingRepoQuery[db.curDB], repoID)
+	return pending, err
+}
+
+func (db *datastore) DeletePendingDeployment(pending *model.PendingDeployment) error {
+	var _, err = db.Exec(pendingDeleteStmt[db.curDB], pending.ID)
+	return err
+}
+
+func (db *datastore) CreateDeploymentApproval(approval *model.DeploymentApproval) error {
+	return meddler.Insert(db, approvalTable, approval)
+}
+
+func (db *datastore) GetDeploymentApprovals(repoID int64, pr int, env string) ([]*model.DeploymentApproval, error) {
+	var approvals = []*model.DeploymentApproval{}
+	var err = meddler.QueryAll(db, &approvals, approvalQuery[db.curDB], repoID, pr, env)
+	return approvals, err
+}
+
+const (
+	pendingTable  = "pending_deployments"
+	approvalTable = "deployment_approvals"
+)
+
+var pendingRepoQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM pending_deployments
+	WHERE pending_repo_id = $1
+	ORDER BY pending_id
+	`,
+	MYSQL: `
+	SELECT *
+	FROM pending_deployments
+	WHERE pending_repo_id = ?
+	ORDER BY pending_id
+	`,
+	SQLITE: `
+	SELECT *
+	FROM pending_deployments
+	WHERE pending_repo_id = ?
+	ORDER BY pending_id
+	`,
+}
+
+var pendingDeleteStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM pending_deployments
+	WHERE pending_id = $1
+	`,
+	MYSQL: `
+	DELETE FROM pending_deployments
+	WHERE pending_id = ?
+	`,
+	SQLITE: `
+	DELETE FROM pending_deployments
+	WHERE pending_id = ?
+	`,
+}
+
+var approvalQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM deployment_approvals
+	WHERE approval_repo_id = $1
+	AND approval_pr = $2
+	AND approval_env = $3
+	ORDER BY approval_id
+	`,
+	MYSQL: `
+	SELECT *
+	FROM deployment_approvals
+	WHERE approval_repo_id = ?
+	AND approval_pr = ?
+	AND approval_env = ?
+	ORDER BY approval_id
+	`,
+	SQLITE: `
+	SELECT *
+	FROM deployment_approvals
+	WHERE approval_repo_id = ?
+	AND approval_pr = ?
+	AND approval_env = ?
+	ORDER BY approval_id
+	`,
+}
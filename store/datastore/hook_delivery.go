@@ -0,0 +1,146 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+
+	"github.com/russross/meddler"
+)
+
+func (db *datastore) CreateHookDelivery(delivery *model.HookDelivery) error {
+	return meddler.Insert(db, hookDeliveryTable, delivery)
+}
+
+func (db *datastore) GetHookDelivery(id int64) (*model.HookDelivery, error) {
+	var delivery = new(model.HookDelivery)
+	var err = meddler.Load(db, hookDeliveryTable, delivery, id)
+	if err == sql.ErrNoRows {
+		return delivery, exterror.Create(http.StatusNotFound, err)
+	}
+	return delivery, err
+}
+
+func (db *datastore) GetHookDeliveryGUID(guid string) (*model.HookDelivery, error) {
+	var delivery = new(model.HookDelivery)
+	var err = meddler.QueryRow(db, delivery, hookDeliveryGUIDQuery[db.curDB], guid)
+	if err == sql.ErrNoRows {
+		return delivery, exterror.Create(http.StatusNotFound, err)
+	}
+	return delivery, err
+}
+
+func (db *datastore) GetHookDeliveries(filter model.HookDeliveryFilter) ([]*model.HookDelivery, error) {
+	var deliveries = []*model.HookDelivery{}
+	var err error
+	if db.curDB == POSTGRES {
+		err = meddler.QueryAll(db, &deliveries, hookDeliveriesQuery[db.curDB],
+			filter.Repo, filter.PullRequest, filter.Event, filter.Limit)
+	} else {
+		err = meddler.QueryAll(db, &deliveries, hookDeliveriesQuery[db.curDB],
+			filter.Repo, filter.Repo, filter.PullRequest, filter.PullRequest,
+			filter.Event, filter.Event, filter.Limit)
+	}
+	return deliveries, err
+}
+
+func (db *datastore) UpdateHookDelivery(delivery *model.HookDelivery) error {
+	return meddler.Update(db, hookDeliveryTable, delivery)
+}
+
+func (db *datastore) DeleteHookDeliveriesBefore(created int64) error {
+	var _, err = db.Exec(hookDeliveryPruneStmt[db.curDB], created)
+	return err
+}
+
+const hookDeliveryTable = "hook_deliveries"
+
+var hookDeliveryGUIDQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM hook_deliveries
+	WHERE hook_delivery_guid = $1
+	ORDER BY hook_delivery_id DESC
+	LIMIT 1
+	`,
+	MYSQL: `
+	SELECT *
+	FROM hook_deliveries
+	WHERE hook_delivery_guid = ?
+	ORDER BY hook_delivery_id DESC
+	LIMIT 1
+	`,
+	SQLITE: `
+	SELECT *
+	FROM hook_deliveries
+	WHERE hook_delivery_guid = ?
+	ORDER BY hook_delivery_id DESC
+	LIMIT 1
+	`,
+}
+
+// hookDeliveriesQuery ignores the empty fields of the filter.
+var hookDeliveriesQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM hook_deliveries
+	WHERE ($1 = '' OR hook_delivery_repo = $1)
+	AND ($2 = 0 OR hook_delivery_pr = $2)
+	AND ($3 = '' OR hook_delivery_event = $3)
+	ORDER BY hook_delivery_id DESC
+	LIMIT $4
+	`,
+	MYSQL: `
+	SELECT *
+	FROM hook_deliveries
+	WHERE (? = '' OR hook_delivery_repo = ?)
+	AND (? = 0 OR hook_delivery_pr = ?)
+	AND (? = '' OR hook_delivery_event = ?)
+	ORDER BY hook_delivery_id DESC
+	LIMIT ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM hook_deliveries
+	WHERE (? = '' OR hook_delivery_repo = ?)
+	AND (? = 0 OR hook_delivery_pr = ?)
+	AND (? = '' OR hook_delivery_event = ?)
+	ORDER BY hook_delivery_id DESC
+	LIMIT ?
+	`,
+}
+
+var hookDeliveryPruneStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM hook_deliveries
+	WHERE hook_delivery_created < $1
+	`,
+	MYSQL: `
+	DELETE FROM hook_deliveries
+	WHERE hook_delivery_created < ?
+	`,
+	SQLITE: `
+	DELETE FROM hook_deliveries
+	WHERE hook_delivery_created < ?
+	`,
+}
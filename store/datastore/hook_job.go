@@ -0,0 +1,185 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+
+	"github.com/russross/meddler"
+)
+
+func (db *datastore) CreateHookJob(job *model.HookJob) error {
+	return meddler.Insert(db, hookJobTable, job)
+}
+
+func (db *datastore) GetHookJobDelivery(delivery string) (*model.HookJob, error) {
+	var job = new(model.HookJob)
+	var err = meddler.QueryRow(db, job, hookJobDeliveryQuery[db.curDB], delivery)
+	if err == sql.ErrNoRows {
+		return job, exterror.Create(http.StatusNotFound, err)
+	}
+	return job, err
+}
+
+func (db *datastore) GetDueHookJobs(now int64, limit int) ([]*model.HookJob, error) {
+	var jobs = []*model.HookJob{}
+	var err = meddler.QueryAll(db, &jobs, hookJobDueQuery[db.curDB], model.HookJobPending, now, limit)
+	return jobs, err
+}
+
+func (db *datastore) ClaimHookJob(id int64, due int64, lease int64) (bool, error) {
+	res, err := db.Exec(hookJobClaimStmt[db.curDB], lease, id, model.HookJobPending, due)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (db *datastore) GetHookJobsByState(state string, limit int) ([]*model.HookJob, error) {
+	var jobs = []*model.HookJob{}
+	var err = meddler.QueryAll(db, &jobs, hookJobStateQuery[db.curDB], state, limit)
+	return jobs, err
+}
+
+func (db *datastore) UpdateHookJob(job *model.HookJob) error {
+	return meddler.Update(db, hookJobTable, job)
+}
+
+func (db *datastore) DeleteHookJobsBefore(updated int64) error {
+	var _, err = db.Exec(hookJobPruneStmt[db.curDB], model.HookJobPending, updated)
+	return err
+}
+
+const hookJobTable = "hook_jobs"
+
+var hookJobDeliveryQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_delivery = $1
+	`,
+	MYSQL: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_delivery = ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_delivery = ?
+	`,
+}
+
+var hookJobDueQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_state = $1
+	AND hook_job_next_attempt <= $2
+	ORDER BY hook_job_id
+	LIMIT $3
+	`,
+	MYSQL: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_state = ?
+	AND hook_job_next_attempt <= ?
+	ORDER BY hook_job_id
+	LIMIT ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_state = ?
+	AND hook_job_next_attempt <= ?
+	ORDER BY hook_job_id
+	LIMIT ?
+	`,
+}
+
+var hookJobClaimStmt = map[string]string{
+	POSTGRES: `
+	UPDATE hook_jobs
+	SET hook_job_next_attempt = $1
+	WHERE hook_job_id = $2
+	AND hook_job_state = $3
+	AND hook_job_next_attempt = $4
+	`,
+	MYSQL: `
+	UPDATE hook_jobs
+	SET hook_job_next_attempt = ?
+	WHERE hook_job_id = ?
+	AND hook_job_state = ?
+	AND hook_job_next_attempt = ?
+	`,
+	SQLITE: `
+	UPDATE hook_jobs
+	SET hook_job_next_attempt = ?
+	WHERE hook_job_id = ?
+	AND hook_job_state = ?
+	AND hook_job_next_attempt = ?
+	`,
+}
+
+var hookJobStateQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_state = $1
+	ORDER BY hook_job_id DESC
+	LIMIT $2
+	`,
+	MYSQL: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_state = ?
+	ORDER BY hook_job_id DESC
+	LIMIT ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM hook_jobs
+	WHERE hook_job_state = ?
+	ORDER BY hook_job_id DESC
+	LIMIT ?
+	`,
+}
+
+var hookJobPruneStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM hook_jobs
+	WHERE hook_job_state <> $1
+	AND hook_job_updated < $2
+	`,
+	MYSQL: `
+	DELETE FROM hook_jobs
+	WHERE hook_job_state <> ?
+	AND hook_job_updated < ?
+	`,
+	SQLITE: `
+	DELETE FROM hook_jobs
+	WHERE hook_job_state <> ?
+	AND hook_job_updated < ?
+	`,
+}
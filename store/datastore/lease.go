@@ -0,0 +1,122 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"database/sql"
+
+	"github.com/capitalone/checks-out/model"
+
+	"github.com/russross/meddler"
+)
+
+func (db *datastore) AcquireLease(key, owner string, now, expires int64) (bool, error) {
+	res, err := db.Exec(leaseRenewStmt[db.curDB], owner, expires, key, now, owner)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return true, nil
+	}
+	lease := &model.Lease{
+		Key:     key,
+		Owner:   owner,
+		Expires: expires,
+	}
+	err = meddler.Insert(db, leaseTable, lease)
+	if err == nil {
+		return true, nil
+	}
+	// the key is leased by another owner, unless the lease could
+	// not be read either. MySQL does not count rows whose values
+	// are unchanged, so a renewal by the owner ends up here too.
+	existing := new(model.Lease)
+	err2 := meddler.QueryRow(db, existing, leaseKeyQuery[db.curDB], key)
+	if err2 == sql.ErrNoRows {
+		return false, err
+	}
+	if err2 != nil {
+		return false, err2
+	}
+	return existing.Owner == owner && existing.Expires >= now, nil
+}
+
+func (db *datastore) ReleaseLease(key, owner string) error {
+	var _, err = db.Exec(leaseReleaseStmt[db.curDB], key, owner)
+	return err
+}
+
+const leaseTable = "leases"
+
+var leaseKeyQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM leases
+	WHERE lease_key = $1
+	`,
+	MYSQL: `
+	SELECT *
+	FROM leases
+	WHERE lease_key = ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM leases
+	WHERE lease_key = ?
+	`,
+}
+
+var leaseRenewStmt = map[string]string{
+	POSTGRES: `
+	UPDATE leases
+	SET lease_owner = $1, lease_expires = $2
+	WHERE lease_key = $3
+	AND (lease_expires < $4 OR lease_owner = $5)
+	`,
+	MYSQL: `
+	UPDATE leases
+	SET lease_owner = ?, lease_expires = ?
+	WHERE lease_key = ?
+	AND (lease_expires < ? OR lease_owner = ?)
+	`,
+	SQLITE: `
+	UPDATE leases
+	SET lease_owner = ?, lease_expires = ?
+	WHERE lease_key = ?
+	AND (lease_expires < ? OR lease_owner = ?)
+	`,
+}
+
+var leaseReleaseStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM leases
+	WHERE lease_key = $1
+	AND lease_owner = $2
+	`,
+	MYSQL: `
+	DELETE FROM leases
+	WHERE lease_key = ?
+	AND lease_owner = ?
+	`,
+	SQLITE: `
+	DELETE FROM leases
+	WHERE lease_key = ?
+	AND lease_owner = ?
+	`,
+}
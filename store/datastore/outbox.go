@@ -0,0 +1,161 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+
+	"github.com/russross/meddler"
+)
+
+func (db *datastore) CreateOutboxMessage(msg *model.OutboxMessage) error {
+	return meddler.Insert(db, outboxTable, msg)
+}
+
+func (db *datastore) GetOutboxMessage(id int64) (*model.OutboxMessage, error) {
+	var msg = new(model.OutboxMessage)
+	var err = meddler.Load(db, outboxTable, msg, id)
+	if err == sql.ErrNoRows {
+		return msg, exterror.Create(http.StatusNotFound, err)
+	}
+	return msg, err
+}
+
+func (db *datastore) GetDueOutboxMessages(now int64, limit int) ([]*model.OutboxMessage, error) {
+	var msgs = []*model.OutboxMessage{}
+	var err = meddler.QueryAll(db, &msgs, outboxDueQuery[db.curDB], model.OutboxPending, now, limit)
+	return msgs, err
+}
+
+func (db *datastore) ClaimOutboxMessage(id int64, due int64, lease int64) (bool, error) {
+	res, err := db.Exec(outboxClaimStmt[db.curDB], lease, id, model.OutboxPending, due)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (db *datastore) GetOutboxMessagesByState(state string) ([]*model.OutboxMessage, error) {
+	var msgs = []*model.OutboxMessage{}
+	var err = meddler.QueryAll(db, &msgs, outboxStateQuery[db.curDB], state)
+	return msgs, err
+}
+
+func (db *datastore) UpdateOutboxMessage(msg *model.OutboxMessage) error {
+	return meddler.Update(db, outboxTable, msg)
+}
+
+func (db *datastore) DeleteOutboxMessage(id int64) error {
+	var _, err = db.Exec(outboxDeleteStmt[db.curDB], id)
+	return err
+}
+
+const outboxTable = "outbox"
+
+var outboxDueQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM outbox
+	WHERE outbox_state = $1
+	AND outbox_next_attempt <= $2
+	ORDER BY outbox_id
+	LIMIT $3
+	`,
+	MYSQL: `
+	SELECT *
+	FROM outbox
+	WHERE outbox_state = ?
+	AND outbox_next_attempt <= ?
+	ORDER BY outbox_id
+	LIMIT ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM outbox
+	WHERE outbox_state = ?
+	AND outbox_next_attempt <= ?
+	ORDER BY outbox_id
+	LIMIT ?
+	`,
+}
+
+var outboxClaimStmt = map[string]string{
+	POSTGRES: `
+	UPDATE outbox
+	SET outbox_next_attempt = $1
+	WHERE outbox_id = $2
+	AND outbox_state = $3
+	AND outbox_next_attempt = $4
+	`,
+	MYSQL: `
+	UPDATE outbox
+	SET outbox_next_attempt = ?
+	WHERE outbox_id = ?
+	AND outbox_state = ?
+	AND outbox_next_attempt = ?
+	`,
+	SQLITE: `
+	UPDATE outbox
+	SET outbox_next_attempt = ?
+	WHERE outbox_id = ?
+	AND outbox_state = ?
+	AND outbox_next_attempt = ?
+	`,
+}
+
+var outboxStateQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM outbox
+	WHERE outbox_state = $1
+	ORDER BY outbox_id
+	`,
+	MYSQL: `
+	SELECT *
+	FROM outbox
+	WHERE outbox_state = ?
+	ORDER BY outbox_id
+	`,
+	SQLITE: `
+	SELECT *
+	FROM outbox
+	WHERE outbox_state = ?
+	ORDER BY outbox_id
+	`,
+}
+
+var outboxDeleteStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM outbox
+	WHERE outbox_id = $1
+	`,
+	MYSQL: `
+	DELETE FROM outbox
+	WHERE outbox_id = ?
+	`,
+	SQLITE: `
+	DELETE FROM outbox
+	WHERE outbox_id = ?
+	`,
+}
@@ -0,0 +1,92 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+
+	"github.com/russross/meddler"
+)
+
+func (db *datastore) GetSlackLink(team string, user string) (*model.SlackLink, error) {
+	var link = new(model.SlackLink)
+	var err = meddler.QueryRow(db, link, slackLinkQuery[db.curDB], team, user)
+	if err == sql.ErrNoRows {
+		return link, exterror.Create(http.StatusNotFound, err)
+	}
+	return link, err
+}
+
+func (db *datastore) CreateSlackLink(link *model.SlackLink) error {
+	var err = db.DeleteSlackLink(link.Team, link.User)
+	if err != nil {
+		return err
+	}
+	return meddler.Insert(db, slackLinkTable, link)
+}
+
+func (db *datastore) DeleteSlackLink(team string, user string) error {
+	var _, err = db.Exec(slackLinkDeleteStmt[db.curDB], team, user)
+	return err
+}
+
+const slackLinkTable = "slack_links"
+
+var slackLinkQuery = map[string]string{
+	POSTGRES: `
+	SELECT *
+	FROM slack_links
+	WHERE slack_link_team = $1
+	AND slack_link_user = $2
+	`,
+	MYSQL: `
+	SELECT *
+	FROM slack_links
+	WHERE slack_link_team = ?
+	AND slack_link_user = ?
+	`,
+	SQLITE: `
+	SELECT *
+	FROM slack_links
+	WHERE slack_link_team = ?
+	AND slack_link_user = ?
+	`,
+}
+
+var slackLinkDeleteStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM slack_links
+	WHERE slack_link_team = $1
+	AND slack_link_user = $2
+	`,
+	MYSQL: `
+	DELETE FROM slack_links
+	WHERE slack_link_team = ?
+	AND slack_link_user = ?
+	`,
+	SQLITE: `
+	DELETE FROM slack_links
+	WHERE slack_link_team = ?
+	AND slack_link_user = ?
+	`,
+}
@@ -0,0 +1,108 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+// GetTeamsUrl returns the Teams webhook URL that the user registered
+// for the hostname. A blank user selects the admin-level URL.
+// An empty string is returned when no URL is registered.
+func (db *datastore) GetTeamsUrl(hostname string, user string) (string, error) {
+	rows, err := db.Query(getTeamsStmt[db.curDB], hostname, user)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return "", nil
+	}
+	var url string
+	err = rows.Scan(&url)
+	return url, err
+}
+
+// AddUpdateTeamsUrl registers the Teams webhook URL of the user
+// for the hostname. A blank user registers the admin-level URL.
+func (db *datastore) AddUpdateTeamsUrl(hostname string, user string, url string) error {
+	query := upsertTeamsStmt[db.curDB]
+	var values []interface{}
+	switch db.curDB {
+	case POSTGRES:
+		values = []interface{}{hostname, user, url}
+	case MYSQL:
+		values = []interface{}{hostname, user, url, url}
+	case SQLITE:
+		values = []interface{}{hostname, user, url}
+	}
+	_, err := db.Exec(query, values...)
+	return err
+}
+
+// DeleteTeamsUrl removes the Teams webhook URL of the user
+// for the hostname. A blank user removes the admin-level URL.
+func (db *datastore) DeleteTeamsUrl(hostname string, user string) error {
+	_, err := db.Exec(deleteTeamsStmt[db.curDB], hostname, user)
+	return err
+}
+
+var upsertTeamsStmt = map[string]string{
+	POSTGRES: `
+	INSERT into teams_urls(host_name, user, url)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (host_name, user) DO UPDATE
+	SET url = excluded.url
+	`,
+	MYSQL: `
+	INSERT into teams_urls(host_name, user, url)
+	VALUES (?, ?, ?)
+	ON DUPLICATE KEY UPDATE url=?
+	`,
+	SQLITE: `
+	INSERT OR REPLACE into teams_urls(host_name, user, url)
+	VALUES (?, ?, ?)
+	`,
+}
+
+var getTeamsStmt = map[string]string{
+	POSTGRES: `
+	SELECT url FROM teams_urls
+	WHERE host_name = $1 and user = $2
+	`,
+	MYSQL: `
+	SELECT url FROM teams_urls
+	WHERE host_name = ? and user = ?
+	`,
+	SQLITE: `
+	SELECT url FROM teams_urls
+	WHERE host_name = ? and user = ?
+	`,
+}
+
+var deleteTeamsStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM teams_urls
+	WHERE host_name = $1 and user = $2
+	`,
+	MYSQL: `
+	DELETE FROM teams_urls
+	WHERE host_name = ? and user = ?
+	`,
+	SQLITE: `
+	DELETE FROM teams_urls
+	WHERE host_name = ? and user = ?
+	`,
+}
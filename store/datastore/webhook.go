@@ -0,0 +1,115 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package datastore
+
+import (
+	"github.com/capitalone/checks-out/model"
+)
+
+// GetWebhookTarget returns the webhook URL and signing secret that
+// the user registered for the hostname. A blank user selects the
+// admin-level target. Nil is returned when no target is registered.
+func (db *datastore) GetWebhookTarget(hostname string, user string) (*model.WebhookTarget, error) {
+	rows, err := db.Query(getWebhookStmt[db.curDB], hostname, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, nil
+	}
+	target := new(model.WebhookTarget)
+	err = rows.Scan(&target.Url, &target.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// AddUpdateWebhookTarget registers the webhook target of the user
+// for the hostname. A blank user registers the admin-level target.
+func (db *datastore) AddUpdateWebhookTarget(hostname string, user string, target *model.WebhookTarget) error {
+	query := upsertWebhookStmt[db.curDB]
+	var values []interface{}
+	switch db.curDB {
+	case POSTGRES:
+		values = []interface{}{hostname, user, target.Url, target.Secret}
+	case MYSQL:
+		values = []interface{}{hostname, user, target.Url, target.Secret, target.Url, target.Secret}
+	case SQLITE:
+		values = []interface{}{hostname, user, target.Url, target.Secret}
+	}
+	_, err := db.Exec(query, values...)
+	return err
+}
+
+// DeleteWebhookTarget removes the webhook target of the user
+// for the hostname. A blank user removes the admin-level target.
+func (db *datastore) DeleteWebhookTarget(hostname string, user string) error {
+	_, err := db.Exec(deleteWebhookStmt[db.curDB], hostname, user)
+	return err
+}
+
+var upsertWebhookStmt = map[string]string{
+	POSTGRES: `
+	INSERT into webhook_urls(host_name, user, url, secret)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (host_name, user) DO UPDATE
+	SET url = excluded.url, secret = excluded.secret
+	`,
+	MYSQL: `
+	INSERT into webhook_urls(host_name, user, url, secret)
+	VALUES (?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE url=?, secret=?
+	`,
+	SQLITE: `
+	INSERT OR REPLACE into webhook_urls(host_name, user, url, secret)
+	VALUES (?, ?, ?, ?)
+	`,
+}
+
+var getWebhookStmt = map[string]string{
+	POSTGRES: `
+	SELECT url, secret FROM webhook_urls
+	WHERE host_name = $1 and user = $2
+	`,
+	MYSQL: `
+	SELECT url, secret FROM webhook_urls
+	WHERE host_name = ? and user = ?
+	`,
+	SQLITE: `
+	SELECT url, secret FROM webhook_urls
+	WHERE host_name = ? and user = ?
+	`,
+}
+
+var deleteWebhookStmt = map[string]string{
+	POSTGRES: `
+	DELETE FROM webhook_urls
+	WHERE host_name = $1 and user = $2
+	`,
+	MYSQL: `
+	DELETE FROM webhook_urls
+	WHERE host_name = ? and user = ?
+	`,
+	SQLITE: `
+	DELETE FROM webhook_urls
+	WHERE host_name = ? and user = ?
+	`,
+}
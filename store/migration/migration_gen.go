@@ -7,6 +7,16 @@
 // sqlite3/005_oauth_scope.sql
 // sqlite3/006_add_orgs_table.sql
 // sqlite3/007_add_slack_urls.sql
+// sqlite3/008_add_pending_deployments.sql
+// sqlite3/009_add_teams_urls.sql
+// sqlite3/010_add_webhook_urls.sql
+// sqlite3/011_add_outbox.sql
+// sqlite3/012_add_slack_links.sql
+// sqlite3/013_add_hook_jobs.sql
+// sqlite3/014_add_leases.sql
+// sqlite3/015_add_hook_deliveries.sql
+// sqlite3/016_outbox_target_host.sql
+// sqlite3/017_hook_events.sql
 // mysql/001_init.sql
 // mysql/002_org.sql
 // mysql/003_drop_emails.sql
@@ -14,6 +24,16 @@
 // mysql/005_oauth_scope.sql
 // mysql/006_add_orgs_table.sql
 // mysql/007_add_slack_urls.sql
+// mysql/008_add_pending_deployments.sql
+// mysql/009_add_teams_urls.sql
+// mysql/010_add_webhook_urls.sql
+// mysql/011_add_outbox.sql
+// mysql/012_add_slack_links.sql
+// mysql/013_add_hook_jobs.sql
+// mysql/014_add_leases.sql
+// mysql/015_add_hook_deliveries.sql
+// mysql/016_outbox_target_host.sql
+// mysql/017_hook_events.sql
 // postgres/001_init.sql
 // postgres/002_org.sql
 // postgres/003_drop_emails.sql
@@ -21,6 +41,16 @@
 // postgres/005_oauth_scope.sql
 // postgres/006_add_orgs_table.sql
 // postgres/007_add_slack_urls.sql
+// postgres/008_add_pending_deployments.sql
+// postgres/009_add_teams_urls.sql
+// postgres/010_add_webhook_urls.sql
+// postgres/011_add_outbox.sql
+// postgres/012_add_slack_links.sql
+// postgres/013_add_hook_jobs.sql
+// postgres/014_add_leases.sql
+// postgres/015_add_hook_deliveries.sql
+// postgres/016_outbox_target_host.sql
+// postgres/017_hook_events.sql
 // DO NOT EDIT!
 
 package migration
@@ -228,6 +258,206 @@ func sqlite3007_add_slack_urlsSql() (*asset, error) {
 	return a, nil
 }
 
+var _sqlite3008_add_pending_deploymentsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x52\x41\x4e\xc3\x30\x10\xbc\xfb\x15\x7b\x4c\x45\xf2\x82\x9c\x40\xe5\x86\x04\xaa\xe0\x1c\x99\x66\x1b\xad\xea\xac\x8d\xed\x94\xe4\xf7\xe0\x48\x18\xe3\xba\x11\x37\x7b\x67\xc6\xbb\x3b\xe3\xa6\x81\xbb\x91\x06\x2b\x3d\xc2\x9b\x11\xe2\x68\x31\x1c\xbd\x7c\x57\x08\x74\x02\xd6\x1e\x70\x26\xe7\x1d\x18\xe4\x9e\x78\xe8\x7a\x34\x4a\x2f\x23\xb2\x77\x95\x80\x58\xa6\x1e\x88\x3d\x0e\x68\xc1\x58\x1a\xa5\x5d\xe0\x8c\x0b\xc8\xc9\x6b\xe2\xef\x67\x83\xa0\x4e\xf8\x16\x8d\x4e\x45\xa1\x13\x4f\x4a\xa5\x1c\x63\x37\x61\x8b\x27\xf0\x38\xfb\x22\xe8\xa5\x3b\xaf\x68\x5a\x44\xbe\xdc\x56\x18\xb9\x28\x2d\xfb\x2b\xd1\x51\x73\x28\xb9\x6b\x60\x35\x2b\xae\x20\x76\x6d\x34\x90\xb8\xc7\x39\x33\x90\xe6\x2e\x5d\x1e\x34\x97\x3c\x85\x2a\x73\x28\x79\xb5\x14\xcb\xaf\xb4\x93\xc6\x58\x7d\x91\x6a\xcd\xe5\xe7\xf2\xef\x60\xa2\x60\x2b\x99\x48\xba\x11\x4d\xc4\x8b\x4e\x47\x54\xe9\x81\x78\x03\xcf\x9c\x0d\xd8\xc4\xf4\x31\x61\x95\x4f\x59\xa7\x23\xd5\x7f\xfa\xd7\x59\xbf\xdd\x1a\x50\x93\xfc\xf8\xbd\xfe\x64\x21\xf6\x87\xe7\x17\x78\xbd\x7f\x78\x7a\x2c\x9a\xd9\xa6\x84\x42\x60\xad\xf8\x02\x45\xe1\xc7\xe2\x45\x03\x00\x00")
+
+func sqlite3008_add_pending_deploymentsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3008_add_pending_deploymentsSql,
+		"sqlite3/008_add_pending_deployments.sql",
+	)
+}
+
+func sqlite3008_add_pending_deploymentsSql() (*asset, error) {
+	bytes, err := sqlite3008_add_pending_deploymentsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/008_add_pending_deployments.sql", size: 837, mode: os.FileMode(420), modTime: time.Unix(1792347057, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3009_add_teams_urlsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6d\x8f\xc1\x0e\x82\x40\x0c\x44\xef\xfd\x8a\x1e\x21\xc2\x17\x70\xd2\xe0\xcd\x44\x63\xf4\x4c\x56\xad\xd8\xb8\x5b\x70\xb7\x1b\xe1\xef\x5d\x38\x18\x12\xbd\x4d\x66\x3a\x2f\x9d\xb2\xc4\x95\xe3\xd6\x1b\x25\x3c\xf7\x00\x57\x4f\x93\x54\x73\xb1\x84\x7c\x47\xe9\x14\x69\xe0\xa0\x01\x95\x8c\x0b\x4d\xf4\x36\x64\x80\xc8\x37\x64\x51\x6a\xc9\x63\xef\xd9\x19\x3f\xe2\x93\x46\x34\x51\x3b\x96\x44\x71\x24\x5a\xa4\xbb\x47\x17\xb4\x11\xe3\x12\x93\x06\x9d\x79\x12\xad\x9d\xa2\x18\x52\xf9\xd7\xf5\xf6\x8f\x29\xfc\x8a\x94\x7d\x61\xc5\x5c\xce\x21\xaf\x00\xca\xc5\x84\xba\x7b\x0b\x40\x7d\xdc\x1f\xf0\xb4\xde\xec\xb6\x8b\xa7\x2b\xf8\x00\x24\xcc\x30\xcf\xec\x00\x00\x00")
+
+func sqlite3009_add_teams_urlsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3009_add_teams_urlsSql,
+		"sqlite3/009_add_teams_urls.sql",
+	)
+}
+
+func sqlite3009_add_teams_urlsSql() (*asset, error) {
+	bytes, err := sqlite3009_add_teams_urlsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/009_add_teams_urls.sql", size: 236, mode: os.FileMode(420), modTime: time.Unix(1792347367, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3010_add_webhook_urlsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6d\x8f\xc1\x0e\x82\x30\x10\x44\xef\xfb\x15\x7b\x94\x08\x5f\xc0\x49\x83\x37\x13\x8d\xd1\x33\x29\xba\x42\x43\xd9\x62\xbb\x0d\xf0\xf7\x16\x0e\x46\x83\xb7\xc9\xcc\xec\xdb\x4c\x96\xe1\xb6\xd3\xb5\x53\x42\x78\xeb\x01\xee\x8e\x66\x29\xaa\x32\x84\xfa\x89\x6c\x05\x69\xd4\x5e\x3c\x0e\x54\x35\xd6\xb6\x65\x70\xc6\x6f\x00\x51\x3f\x50\xb3\x50\x4d\x0e\x7b\xa7\x3b\xe5\x26\x6c\x69\x42\x15\xc4\x6a\x8e\x9c\x8e\x58\xd2\xd8\x6b\xac\x97\x92\x55\x17\xa9\x34\xca\x42\xe4\x60\xcc\x1c\x05\x1f\x8f\xd7\xae\x33\x6b\xd3\x53\x44\xca\x9f\x32\xeb\x57\xa0\xcd\xe7\x49\xba\x40\x13\x48\x72\x80\xec\x6b\x5c\x61\x07\x06\x28\x2e\xa7\x33\x5e\x77\xfb\xe3\xe1\x67\x4e\x0e\x6f\x01\x31\xce\x2e\x08\x01\x00\x00")
+
+func sqlite3010_add_webhook_urlsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3010_add_webhook_urlsSql,
+		"sqlite3/010_add_webhook_urls.sql",
+	)
+}
+
+func sqlite3010_add_webhook_urlsSql() (*asset, error) {
+	bytes, err := sqlite3010_add_webhook_urlsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/010_add_webhook_urls.sql", size: 264, mode: os.FileMode(420), modTime: time.Unix(1792347616, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3011_add_outboxSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x52\x4b\x4e\xc3\x30\x10\xdd\xfb\x14\xb3\x2c\xa2\x39\x41\x57\x2d\x31\x28\x12\x6a\x51\x1a\xa4\xee\x22\x97\x4c\x23\x0b\x7f\x22\x7b\xac\x26\xb7\xc7\x0d\xa9\x30\xa1\x65\x37\x7a\x3f\xcf\x1b\x39\xcb\xe0\x51\xcb\xd6\x09\x42\x78\xef\x18\xfb\x70\x78\x19\x49\x1c\x15\x82\x3c\x81\xb1\x04\xd8\x4b\x4f\x1e\x6c\xa0\xa3\xed\x17\x0c\xa6\xa9\x96\x0d\x48\x43\xd8\xa2\x83\xce\x49\x2d\xdc\x00\x9f\x38\x80\x08\x64\xa5\x89\x41\x1a\x0d\x2d\x7f\xe4\x24\x5c\x8b\x04\x84\x3d\x8d\xb1\x26\x28\x95\xd0\xc1\xa9\x91\x4b\x20\x8f\x31\x85\xe6\xa8\x11\x1a\xfd\x1f\xa9\x0a\xed\x1c\xeb\xc4\xa0\xac\x68\xee\xbe\xe8\x69\xac\x7a\x87\x15\x44\xa8\xbb\xd8\xbb\xd8\x56\xfc\x85\x97\xb7\x34\x26\x7a\xaf\xc2\xff\x74\x4a\x78\xaa\xd1\x39\xeb\xe6\x3b\x7e\xdf\xbb\xb9\x9a\xd3\x7b\x74\x4d\xca\xb0\x87\x15\x63\x4f\x25\x5f\x57\x3c\x42\x39\x3f\x40\xf1\x0c\xdb\x5d\x05\xfc\x50\xec\xab\x3d\xc8\xbe\xfe\x55\xcb\x9a\x29\x08\x16\x29\xbe\xbc\xb5\xfa\x25\x3a\x4b\x3e\x42\x6e\xcf\x86\xb1\xbc\xdc\xbd\x41\xb5\xde\xbc\xf2\xc9\xb3\x62\x5f\x6e\x8f\x53\xed\x2e\x02\x00\x00")
+
+func sqlite3011_add_outboxSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3011_add_outboxSql,
+		"sqlite3/011_add_outbox.sql",
+	)
+}
+
+func sqlite3011_add_outboxSql() (*asset, error) {
+	bytes, err := sqlite3011_add_outboxSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/011_add_outbox.sql", size: 558, mode: os.FileMode(420), modTime: time.Unix(1792348047, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3012_add_slack_linksSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x90\x4d\x0e\x82\x40\x0c\x85\xf7\x3d\x45\x97\x10\xe1\x04\xac\x34\x12\x63\x62\xd4\x10\x5d\x9b\x51\x2b\x69\x98\x29\x38\xd3\x89\x72\x7b\xff\x36\xc8\x82\x5d\x9b\x7e\x7d\xaf\x7d\x79\x8e\x33\xc7\xb5\x37\x4a\x78\xec\x00\x2e\x9e\x3e\xa5\x9a\xb3\x25\xe4\x1b\x4a\xab\x48\x4f\x0e\x1a\x30\x58\x73\x69\x4e\x96\xa5\x09\x09\xe0\xa0\x3d\xf1\x15\x59\x94\x6a\xf2\xd8\x79\x76\xc6\xf7\xd8\x50\x8f\x26\x6a\xcb\xf2\x56\x74\x24\x9a\xfd\xaf\x28\x19\x87\x4a\x4f\xfd\x3a\x48\xb4\x76\x04\xc4\xf0\x56\x9b\x02\x6c\x5b\xb3\x4c\x12\xbf\x5f\xae\xb8\xde\x1e\xca\x55\x59\x7d\xa6\x51\xf8\x1e\x29\x19\x1d\x92\x8d\x8d\x53\x48\x0b\x80\x7c\x90\xcd\xb2\x7d\x08\xc0\xb2\xda\xed\xf1\x30\x5f\x6c\xca\x61\x1a\x05\xbc\x00\xf7\x27\x1f\x69\x46\x01\x00\x00")
+
+func sqlite3012_add_slack_linksSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3012_add_slack_linksSql,
+		"sqlite3/012_add_slack_links.sql",
+	)
+}
+
+func sqlite3012_add_slack_linksSql() (*asset, error) {
+	bytes, err := sqlite3012_add_slack_linksSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/012_add_slack_links.sql", size: 326, mode: os.FileMode(420), modTime: time.Unix(1792349210, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3013_add_hook_jobsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x92\x41\x4e\xc3\x30\x10\x45\xf7\x3e\xc5\x2c\x53\xd1\x9c\xa0\xab\x42\x0c\x8a\x84\x5a\x94\x06\xa9\xbb\xc8\x21\x43\x31\x75\xec\x60\x8f\x4b\x72\x7b\xdc\xa0\xd2\xa6\x29\xd9\xd9\xfe\xef\x7f\x7b\x66\x1c\xc7\x70\x57\xcb\x9d\x15\x84\xf0\xda\x30\xf6\x66\xf1\xb8\x24\x51\x2a\x04\xf9\x0e\xda\x10\x60\x2b\x1d\x39\xf8\x30\x66\x5f\x7c\x9a\xd2\x45\x0c\xfe\x36\x85\xac\x40\x6a\xc2\x1d\x5a\x68\xac\xac\x85\xed\x60\x8f\x1d\x08\x4f\x46\xea\x90\x56\xa3\xa6\xf9\xa5\xa1\x42\x25\x0f\x18\x30\xc2\x96\xfa\x7c\xed\x95\x1a\x20\x78\x08\xa6\x09\xbd\x14\x0e\x0b\x6f\x55\x8f\x0c\x94\x46\x74\xca\x88\x6a\xc2\xeb\xa8\xaf\xef\x5f\x5d\x10\x61\xdd\x84\x72\xd3\x55\xce\x9f\x78\x76\x9b\xd2\xc1\x7f\x42\xa7\x49\x25\x1c\x15\x68\xad\xb1\xe3\xd7\x5a\x74\x5e\xd1\xf8\xfc\x77\x08\xd5\x29\x78\xa0\xf9\xa6\xba\xd6\xbc\x96\x5f\x1e\xa3\x51\x87\x67\x6c\xb6\x60\xec\x21\xe3\xcb\x9c\x07\x3e\xe1\x5b\x48\x1f\x61\xb5\xce\x81\x6f\xd3\x4d\xbe\x01\xd9\x16\x57\x7d\x31\xfa\x3c\x66\x88\x86\xe2\xfc\x76\xf9\xc7\x3b\xe2\x8b\x5f\x94\x98\x6f\xcd\x58\x92\xad\x5f\x20\x5f\xde\x3f\xf3\x73\xe0\x82\xfd\x00\x4e\xb4\xf6\x6e\x6e\x02\x00\x00")
+
+func sqlite3013_add_hook_jobsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3013_add_hook_jobsSql,
+		"sqlite3/013_add_hook_jobs.sql",
+	)
+}
+
+func sqlite3013_add_hook_jobsSql() (*asset, error) {
+	bytes, err := sqlite3013_add_hook_jobsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/013_add_hook_jobs.sql", size: 622, mode: os.FileMode(420), modTime: time.Unix(1792349878, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3014_add_leasesSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x8f\xc1\x0a\x82\x50\x10\x45\xf7\xf3\x15\xb3\x4c\xca\x2f\x70\x55\x28\x11\x44\x85\xd8\x3a\x5e\x35\xc9\xd0\x73\xb4\xf7\xe6\x91\xfe\x7d\x5a\x61\xb5\x68\x77\xe1\x5c\xce\xe5\xc6\x31\x4e\x2b\x2e\x9d\x51\xc2\x7d\x03\x70\x72\x34\x44\x35\x47\x4b\xc8\x17\x94\x5a\x91\x5a\xf6\xea\xd1\x92\xf1\xe4\x27\x80\xaf\x74\xe0\x33\xb2\x28\x95\xe4\xb0\x71\x5c\x19\xd7\xe1\x95\x3a\x34\x41\x6b\x96\xde\x53\x91\xe8\x6c\x6c\x0f\x48\xa9\xd5\xa7\x51\x82\xb5\x1f\x54\xdf\xa5\x77\xfc\x81\xd4\x36\xec\xc8\xe3\x6a\x53\x64\xcb\x2c\xff\x69\x04\xe1\x5b\xa0\xc9\x38\x10\x41\x94\x00\xc4\x5f\x97\xd2\xde\x0d\x90\xe6\xdb\x1d\x16\xf3\xc5\x3a\x7b\x9f\x48\xe0\x01\xa8\x68\x96\x36\xf8\x00\x00\x00")
+
+func sqlite3014_add_leasesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3014_add_leasesSql,
+		"sqlite3/014_add_leases.sql",
+	)
+}
+
+func sqlite3014_add_leasesSql() (*asset, error) {
+	bytes, err := sqlite3014_add_leasesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/014_add_leases.sql", size: 248, mode: os.FileMode(420), modTime: time.Unix(1792350165, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3015_add_hook_deliveriesSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x95\x53\xdd\x4e\x83\x30\x14\xbe\xef\x53\x9c\xcb\x19\xc7\x13\xec\x6a\x4a\x35\x24\x66\x33\x0c\x93\xdd\x91\x0e\x8e\xac\x59\x69\xc9\x69\x99\xf0\xf6\x16\xd4\xa8\x63\x0d\xd9\x5d\xd3\xef\xef\xf4\x3b\x69\x14\xc1\x7d\x2d\x2b\x12\x0e\xe1\xad\x61\xac\x20\x1c\x8e\x4e\x1c\x14\x82\x7c\x07\x6d\x1c\x60\x27\xad\xb3\x70\x34\xe6\x94\x97\xa8\xe4\x19\x49\xa2\x5d\x30\xf8\x77\xd5\xe7\xb2\x04\xa9\x1d\x56\x48\xd0\x90\xac\x05\xf5\x70\xc2\x1e\x44\xeb\x8c\xd4\xde\xb8\x46\xed\x96\x13\x55\xd5\x7a\x9d\xc3\xee\x0a\x84\x67\xaf\x18\xb1\x71\x0e\xdd\x2a\x35\x25\x89\xc2\x49\xa3\x03\x0e\x84\x8d\x09\x40\x0d\x41\xb2\xc9\xf8\x33\x4f\xa7\xd8\x41\x58\xcc\x5b\x52\x01\xe9\x11\x45\x89\x64\x43\xc6\xa2\x57\x46\x94\x73\x73\x13\xda\x56\xb9\xd0\xcb\x89\x0c\x05\xb0\xaf\x15\x95\xe1\xe9\x1b\x32\x05\x5a\xfb\x4b\x61\x77\x2b\xc6\x1e\x53\xbe\xce\xb8\xbf\x8a\xf9\x1e\x92\x27\xd8\x6c\x33\xe0\xfb\x64\x97\xed\x40\x76\xf9\x95\xda\x7c\xa9\x17\x2b\x87\xc5\x94\xb6\x9c\x84\xfb\xb0\x5b\xb2\xc6\xfd\xcf\x67\x0d\xb4\x1b\x9d\x7f\x8a\x9a\x37\xff\x66\x0e\x35\x45\x7f\x3e\x44\x6c\x3e\x34\x63\x71\xba\x7d\x85\x6c\xfd\xf0\xc2\x2f\x6d\x56\xec\x13\xc3\x7f\x5f\x11\x3f\x03\x00\x00")
+
+func sqlite3015_add_hook_deliveriesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3015_add_hook_deliveriesSql,
+		"sqlite3/015_add_hook_deliveries.sql",
+	)
+}
+
+func sqlite3015_add_hook_deliveriesSql() (*asset, error) {
+	bytes, err := sqlite3015_add_hook_deliveriesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/015_add_hook_deliveries.sql", size: 831, mode: os.FileMode(420), modTime: time.Unix(1792351448, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3016_outbox_target_hostSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x93\xc1\x6e\x83\x30\x0c\x86\xef\x79\x0a\xdf\xda\x69\xe9\x13\xf4\xc4\x4a\x3a\x21\x51\x98\x20\x95\x7a\x43\xe9\xc8\x58\x34\x48\x50\x08\x1a\x7d\xfb\x85\x42\xb7\x40\xe9\x0e\x48\xe8\xb7\x9d\xe4\xff\x6c\x6f\x36\xf0\x5c\x89\x42\x33\xc3\xe1\x58\x23\xe4\x85\x94\x24\x40\xbd\x97\x90\x80\x6a\xcd\x59\x75\x90\x90\xc8\x3b\x10\xa0\x31\x18\x5e\xd5\xd9\xa0\x6e\x11\x7a\xd7\xbc\xaf\x32\xec\x5c\x72\x10\x1f\x20\x95\x01\xde\x89\xc6\x34\x63\xe5\x1a\xc1\xf8\x97\x89\x1c\x84\x34\xbc\xe0\x1a\x6a\x2d\x2a\xa6\x2f\xf0\xc5\x2f\xc0\x5a\xa3\x84\xb4\x07\x55\x5c\x1a\xfc\x97\x6e\x98\x2e\xb8\xb1\xf7\x75\xe6\x7a\xac\x6c\xcb\xd2\x09\xb7\xba\xbc\xc6\x1c\xe9\x53\x35\x66\xae\xb5\x8d\xbd\x6e\xa6\x49\x56\xf1\x66\x2e\x36\x65\x5b\xcc\xb5\x9a\x5d\x4a\xc5\xf2\x87\x6f\x68\xcc\xd5\xfc\x83\x28\x33\x3d\x2b\x4b\x22\x88\x28\x79\xb5\x44\x17\x72\xa4\xad\xbd\x25\xfe\x97\x57\xb2\xc6\x64\x5c\x6b\x75\x67\x66\xe8\x40\x7e\x2b\x76\xad\xd7\xb9\x1b\x41\x4f\xb6\x5f\x41\x94\x92\x84\xf6\x52\x3c\xa6\xa1\x94\x84\x64\x47\xdd\x36\xe1\x69\x0b\xb0\x83\x1c\xc3\x6a\x35\x7c\x2e\x4b\xec\x42\xbc\xe7\x87\x27\xbc\xf0\x9c\x0f\x5e\x82\xb1\xe8\x1d\xcf\x4c\xe3\x99\x55\xb4\x4f\xe2\x83\x2d\x9c\xce\xa8\x9f\xc4\x6f\xe3\x34\x4f\x03\xbb\x84\x78\x94\x58\x18\x3e\x39\x41\xb0\x87\x28\xa6\x40\x4e\x41\x4a\x53\x10\x5d\x36\xe9\xb1\x92\xb7\x4d\x58\x2f\x7a\x71\x9f\xde\x73\xde\x38\x2b\xe5\xab\x6f\xb9\xb8\x54\x9e\xef\xc3\x2e\x0e\x8f\x87\xe8\x17\x10\xb7\xce\x86\x19\xde\xa2\x1f\x7f\x2f\xe2\x41\x97\x03\x00\x00")
+
+func sqlite3016_outbox_target_hostSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3016_outbox_target_hostSql,
+		"sqlite3/016_outbox_target_host.sql",
+	)
+}
+
+func sqlite3016_outbox_target_hostSql() (*asset, error) {
+	bytes, err := sqlite3016_outbox_target_hostSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/016_outbox_target_host.sql", size: 919, mode: os.FileMode(420), modTime: time.Unix(1792352612, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3017_hook_eventsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x3d\x8e\x4b\x0e\xc2\x30\x0c\x05\xf7\x39\xc5\xdb\xa3\x54\xb0\xe2\x02\x1c\x01\xd6\x55\x42\x4d\x6a\x01\x76\x89\x0d\x05\x4e\x4f\x3f\x12\xdb\x91\x66\x34\x31\x62\x73\xe7\x52\x93\x13\x4e\x43\x08\x31\xe2\xd8\x13\x46\xca\xbd\xea\xd5\xa0\x17\xd0\x9b\xcd\x59\x0a\x2a\x0d\x6a\xec\x5a\x99\x0c\x49\x3a\x68\x2d\x49\xf8\x9b\x9c\x55\x26\x52\x69\xd6\xed\x99\xed\x5c\x39\x53\x07\x57\xf8\x14\x13\x1a\x41\x2f\x12\x37\xe4\xcf\x42\xb6\xbb\x7d\x3b\xf7\xdb\x15\x37\xf6\xb8\x61\xbd\x98\x4a\xcd\x72\xf1\xbf\x3a\xe8\x28\xe1\x07\x32\x0e\x73\x3a\xa7\x00\x00\x00")
+
+func sqlite3017_hook_eventsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3017_hook_eventsSql,
+		"sqlite3/017_hook_events.sql",
+	)
+}
+
+func sqlite3017_hook_eventsSql() (*asset, error) {
+	bytes, err := sqlite3017_hook_eventsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/017_hook_events.sql", size: 167, mode: os.FileMode(420), modTime: time.Unix(1792353302, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _mysql001_initSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8c\x92\x41\x6f\x82\x30\x18\x86\xef\xfd\x15\xdf\x11\xb2\x99\x6c\x66\x9e\x38\x55\xf9\xb6\x35\xd3\xe2\x6a\x59\xf4\x64\x9a\xad\x31\x8d\x08\xa6\xa0\xfe\xfd\x85\x5a\x81\x6d\xb2\xc8\xa9\xe9\xc3\x5b\x78\x9f\x7e\x83\x01\xdc\xed\xcc\xc6\xaa\x4a\x43\xba\x27\x64\x22\x90\x4a\x04\x49\xc7\x53\x04\xf6\x0c\x3c\x91\x80\x4b\xb6\x90\x0b\x38\x94\xda\x96\x10\x10\xb7\x58\x9b\x2f\x70\x0f\xe3\x12\x5f\x50\xc0\x5c\xb0\x19\x15\x2b\x78\xc3\x15\xd0\x54\x26\x6b\xc6\x27\x02\x67\xc8\x25\xb9\x77\x81\xac\xd8\x98\x1c\x00\x3e\xa8\x98\xbc\x52\x11\x0c\x47\xa3\xd0\xa3\xaa\xd8\xea\x1e\xa4\x77\xca\x64\xd7\x91\x3a\xaa\x4a\xd9\x16\x3d\x3e\x0c\x9f\x2e\xac\xd4\x9f\x56\x57\xbf\x63\x29\x67\xef\x29\x06\xed\xef\x84\x24\x8c\xfe\xed\x6c\xf5\xbe\x70\x9d\xeb\x45\xd3\xf9\xa6\xd2\x2e\xd1\xa8\xf2\x09\xbf\x5d\x9c\x72\x6d\xe1\x4f\x2d\xc7\x72\xb5\xd3\xd0\xc3\xca\xec\xb0\xe9\x63\x99\xc9\xb7\x3f\x98\xf7\xe1\xe0\xde\x9a\x63\x7d\xc5\x30\x4e\x92\x29\x52\x7e\x39\xcf\x6b\xba\xee\xa9\xf9\xe4\x59\x13\x9d\x4a\x14\xde\xd2\xd9\x0b\x8d\x63\x60\x3c\xc6\x25\x04\x6d\xad\x30\xba\xe1\x4d\xef\xa5\x3e\xb6\x3b\x81\x71\x71\xca\x09\x89\x45\x32\xef\xa6\xa3\xee\x8e\x9b\xc2\x88\x7c\x07\x00\x00\xff\xff\x77\x0d\xa2\x03\xb8\x02\x00\x00")
 
 func mysql001_initSqlBytes() ([]byte, error) {
@@ -368,6 +598,206 @@ func mysql007_add_slack_urlsSql() (*asset, error) {
 	return a, nil
 }
 
+var _mysql008_add_pending_deploymentsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x53\x41\x6e\x83\x30\x10\xbc\xfb\x15\x7b\x04\x15\x2e\x95\x72\xe2\x44\x13\xa4\x56\x6a\x92\x0a\x91\xaa\x37\xe4\x86\x0d\xb2\x62\x6c\xc7\x98\x14\x7e\xdf\x90\xaa\xae\xa1\x94\xe6\x66\x7b\x66\xbd\xb3\x33\x76\x18\xc2\x5d\xc5\x4a\x4d\x0d\xc2\x4e\x11\xb2\xd7\xd8\x2f\x0d\x7d\xe7\x08\xec\x00\x42\x1a\xc0\x96\xd5\xa6\x06\x85\xa2\x60\xa2\xcc\x0b\x54\x5c\x76\x15\x0a\x53\x7b\x04\xec\x31\x2b\x80\x09\x83\x25\x6a\x50\x9a\x55\x54\x77\x70\xc4\x0e\xe2\x5d\xb6\xcd\x9f\x36\xcb\x34\x59\x27\x9b\x2c\x70\x0a\x34\x2a\xe9\x56\xf5\xad\x44\xc3\xb9\xcb\x51\x7a\x16\xd6\x78\x80\xd7\x38\x5d\x3e\xc6\xa9\x77\xbf\x58\xf8\x93\x24\x43\xeb\xe3\x80\xe5\x82\x28\xce\xff\xdf\xa0\x68\xc7\x25\x2d\x20\x4b\xde\x06\x13\xec\xe5\x45\x5b\x7b\xb1\xe6\x17\x70\x75\xd1\x8e\xd6\x43\x4c\x14\xd8\x02\x6b\x73\x77\x7c\xf0\x46\x66\xf8\xc4\x8f\x66\x43\xf8\x31\x3f\xa7\x4a\x69\x79\xa6\xfc\x9a\xc2\xf7\xe6\xf6\x18\x6c\xc5\x5c\x0e\x96\xf4\x47\x10\x16\x9f\xf5\xd1\xb2\xb8\x2c\x99\xb8\x81\x37\xe1\x5f\x23\xd8\xa9\x41\x6f\xac\x3a\x70\x25\x06\x03\x3d\xc1\xa8\xef\x97\xb7\xa1\xf3\xe0\x57\xf2\x43\x10\xb2\x4a\xb7\x2f\x90\xc5\x0f\xcf\xc9\xa4\xbb\x91\x4b\x98\xf8\x03\x11\xf9\x04\xd3\x11\x78\x0d\x44\x03\x00\x00")
+
+func mysql008_add_pending_deploymentsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql008_add_pending_deploymentsSql,
+		"mysql/008_add_pending_deployments.sql",
+	)
+}
+
+func mysql008_add_pending_deploymentsSql() (*asset, error) {
+	bytes, err := mysql008_add_pending_deploymentsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/008_add_pending_deployments.sql", size: 836, mode: os.FileMode(420), modTime: time.Unix(1792347057, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql009_add_teams_urlsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x8f\x4d\x0b\x82\x40\x14\x45\xf7\xef\x57\xbc\xa5\x92\x42\x49\xad\x5c\x99\x0a\x05\x7d\x31\x68\x5b\x99\xea\x65\x43\xe3\x58\x33\x23\xe5\xbf\x4f\x5d\x48\x04\xed\x2e\x9c\xcb\xb9\x5c\xdf\xc7\x49\x25\x4a\xcd\x2d\x61\xfe\x00\x38\x6b\xea\xa3\xe5\x27\x49\x28\xae\xa8\x6a\x8b\xf4\x16\xc6\x1a\xb4\xc4\x2b\x53\x34\x5a\x1a\x07\x10\xc5\x05\x85\xb2\x54\x92\xc6\x87\x16\x15\xd7\x2d\xde\xa9\xc5\x28\xcf\xf6\xc5\x7a\x17\xb3\x74\x9b\xee\x32\xaf\x2b\xde\x6a\x63\x0b\xc5\x2b\xc2\x63\xc4\xe2\x55\xc4\x9c\x60\xb1\x70\x07\xb1\x6a\xa4\xec\x2b\x8d\xe9\x2c\xff\xa9\x96\x23\x9c\x4d\x83\xf9\x0f\x55\xe2\xd9\x90\x33\xae\x78\x83\xcd\x05\x37\x04\xf0\xbf\xce\x25\xf5\x4b\x01\x24\x6c\x7f\xc0\x2c\x5a\x6e\xd2\xaf\x3b\x21\x7c\x00\x50\x7a\xe3\x1b\x06\x01\x00\x00")
+
+func mysql009_add_teams_urlsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql009_add_teams_urlsSql,
+		"mysql/009_add_teams_urls.sql",
+	)
+}
+
+func mysql009_add_teams_urlsSql() (*asset, error) {
+	bytes, err := mysql009_add_teams_urlsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/009_add_teams_urls.sql", size: 262, mode: os.FileMode(420), modTime: time.Unix(1792347367, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql010_add_webhook_urlsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x8f\x4d\x0b\x82\x50\x10\x45\xf7\xf3\x2b\x66\xa9\xa4\x50\x91\x2b\x57\x56\x42\x41\x59\x88\xb6\x15\xab\xa9\x1e\x3d\xdf\xb3\xf7\x41\xf9\xef\x33\x17\x92\x8b\xda\x0d\x9c\xc3\x9d\x7b\x7d\x1f\x47\x15\xbb\xaa\xd2\x10\xe6\x35\xc0\x49\xd1\xe7\x34\xe5\x91\x13\xb2\x0b\x0a\x69\x90\x5e\x4c\x1b\x8d\x4f\x3a\xde\xa4\xbc\x17\x56\x71\xed\x00\x22\x3b\x23\x13\x86\xae\xa4\xb0\x56\xac\x2a\x55\x83\x77\x6a\x30\xca\xb3\x5d\xb1\x4e\x16\x69\xbc\x8d\x93\xcc\x6b\xc5\x9b\xd4\xa6\x10\x65\x45\x78\x88\xd2\xc5\x2a\x4a\x9d\x69\x10\xb8\x5d\xb4\xb0\x9c\x7f\x14\xab\xdb\x94\xdf\x54\xf1\x1e\x4e\xc6\xd3\xd9\x90\x6a\x6a\x4b\x9b\x3f\x82\x15\xec\x61\xc9\xe9\x6b\x78\xdd\x3b\x17\xdc\x10\xc0\xff\xda\xbf\x94\x4f\x01\xb0\x4c\x77\x7b\xcc\xa2\xf9\x26\x1e\x2c\x0e\xe1\x0d\xd9\xa8\x87\xbf\x2b\x01\x00\x00")
+
+func mysql010_add_webhook_urlsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql010_add_webhook_urlsSql,
+		"mysql/010_add_webhook_urls.sql",
+	)
+}
+
+func mysql010_add_webhook_urlsSql() (*asset, error) {
+	bytes, err := mysql010_add_webhook_urlsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/010_add_webhook_urls.sql", size: 299, mode: os.FileMode(420), modTime: time.Unix(1792347616, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql011_add_outboxSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x85\x92\x41\x6f\x83\x30\x0c\x85\xef\xf9\x15\x3e\x52\xad\x5c\x26\xf5\xd4\x13\x2d\x51\x87\x54\xe8\x84\x60\xea\x0d\xa5\xc3\x43\xd1\x42\x82\x82\xd1\xe0\xdf\x0f\x36\xa6\xa5\x52\xa6\xdd\x2c\xf9\x7b\x2f\xcf\x76\xc2\x10\x1e\x5a\xd9\x58\x41\x08\x65\xc7\xd8\xab\xc5\xa5\x24\x71\x53\x08\xf2\x0d\xb4\x21\xc0\x51\xf6\xd4\x83\x19\xe8\x66\xc6\x80\xc1\x5a\x55\xb2\x06\xa9\x09\x1b\xb4\xd0\x59\xd9\x0a\x3b\xc1\x3b\x4e\x10\x95\xc5\xa5\x4a\xb2\x63\xce\x53\x9e\x15\xdb\x5f\x9e\x84\x6d\x90\xe0\x25\xca\x8f\x4f\x51\x1e\x3c\xee\x76\x9b\x2f\x7f\x3d\x28\xe5\x60\x83\x55\x90\xf2\x38\x29\xd3\x82\x5f\x5d\x7d\x8f\x73\x3a\xf2\xf7\xb4\x68\xb1\xff\x43\xa6\x86\xe6\xee\x51\xa7\xd7\x89\x49\x19\x51\x3b\x42\x5f\xa2\x9e\x96\x9d\xfc\x97\x5b\x10\x61\xdb\xcd\x8b\x4a\xb2\x82\x9f\x78\xee\x63\x34\x8e\xf4\x03\xc2\x21\x39\xcd\xa8\x0f\x53\xa2\xa7\x0a\xad\x35\xd6\x3f\xd2\xf7\x95\xea\xd5\xc1\xdd\x5d\x57\x3b\x0d\xb6\xd9\x33\x16\x9d\x8b\x39\x4b\x11\x1d\xce\x7c\xa5\x20\x8a\xe3\x39\x64\xcc\xaf\x10\xb8\x13\x6e\x7d\x29\x17\x8b\xd0\xf9\x24\xb1\xf9\xd0\x8c\xc5\xf9\xe5\xf9\xce\x73\xcf\x3e\x01\xb9\x75\xe4\xb2\x4a\x02\x00\x00")
+
+func mysql011_add_outboxSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql011_add_outboxSql,
+		"mysql/011_add_outbox.sql",
+	)
+}
+
+func mysql011_add_outboxSql() (*asset, error) {
+	bytes, err := mysql011_add_outboxSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/011_add_outbox.sql", size: 586, mode: os.FileMode(420), modTime: time.Unix(1792348047, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql012_add_slack_linksSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x90\x3d\x0f\x82\x40\x0c\x86\xf7\xfe\x8a\x8e\x10\x65\x31\x61\x62\x42\x25\x4a\xa2\x60\x08\xba\x92\x53\x2a\x69\x38\x0e\xbc\x3b\xa2\xfc\x7b\xbf\x16\x64\x72\x6b\x93\xa7\xed\xd3\xd7\xf3\x70\xd6\x70\xa5\x85\x25\x3c\x76\x00\x17\x4d\xef\xd2\x8a\xb3\x24\xe4\x2b\xaa\xd6\x22\x3d\xd8\x58\x83\x46\x8a\x4b\x5d\x48\x56\xb5\x71\x00\x47\x6d\xc1\x25\xb2\xb2\x54\x91\xc6\x4e\x73\x23\xf4\x80\x35\x0d\x18\x1e\xf3\xb4\x88\x93\x55\x16\xed\xa3\x24\x9f\xff\xce\x58\x12\x0d\x9e\xc2\x6c\xb5\x0d\x33\x67\xe1\xfb\xee\xe7\x94\xea\xa5\x9c\x80\xbd\x79\xad\xfd\x07\x94\x6d\xc5\xea\x2f\xf2\xfb\x64\x89\xcb\x78\x13\x7f\xc5\x7a\xc5\xb7\x9e\x9c\x89\xdf\x7c\xea\xe1\x82\x1b\x00\x78\xa3\xcc\xd6\xed\x5d\x01\xac\xb3\xf4\x80\x79\xb8\xdc\x45\xe3\x94\x02\x78\x02\xf0\x97\x88\x0f\x5e\x01\x00\x00")
+
+func mysql012_add_slack_linksSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql012_add_slack_linksSql,
+		"mysql/012_add_slack_links.sql",
+	)
+}
+
+func mysql012_add_slack_linksSql() (*asset, error) {
+	bytes, err := mysql012_add_slack_linksSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/012_add_slack_links.sql", size: 350, mode: os.FileMode(420), modTime: time.Unix(1792349210, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql013_add_hook_jobsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x92\xcd\x6e\x83\x30\x10\x84\xef\x7e\x8a\x3d\x82\x9a\x5c\x2a\xe5\x94\x13\x09\x56\x8a\x14\x48\x85\x4c\x95\x1b\x32\x65\x9b\xba\x31\x36\xb5\x4d\x1a\xde\xbe\xd0\x5f\x48\xa9\xda\x9b\xad\xf9\xbc\xbb\xb3\xe3\xf9\x1c\xae\x2a\x71\x30\xdc\x21\x64\x35\x21\xf7\x06\xfb\xa3\xe3\x85\x44\x10\x0f\xa0\xb4\x03\x3c\x0b\xeb\x2c\x3c\x6a\x7d\xcc\x9f\x74\x61\x3d\x02\x5f\x97\x5c\x94\x20\x94\xc3\x03\x1a\xa8\x8d\xa8\xb8\x69\xe1\x88\x2d\x04\x19\xdb\xe5\x51\xb2\x4e\x69\x4c\x13\x36\x1b\xbe\x28\x51\x8a\x13\x76\xdc\x5d\x90\xae\x6f\x82\xd4\xbb\x5e\x2c\xfc\xb7\x46\xaa\x91\x72\x84\xe2\x09\x95\xfb\x07\x57\x70\x8b\x79\x63\xe4\x08\x1d\x11\x35\x6f\xa5\xe6\x25\xc4\x34\x8c\xb2\x98\xd1\x3d\x9b\xae\x64\x5d\x6f\xff\xef\x8e\xdc\x39\xac\xea\x6e\x2b\x51\xc2\xe8\x86\xa6\xd3\x94\xc2\xb3\xfb\x44\x61\x15\x6d\x3a\x78\x1a\x94\xdc\xba\x1c\x8d\xd1\x66\x30\xe1\x88\x30\x68\x1b\xe9\x7e\x53\xdf\x63\x2b\x3f\x7a\x8c\xa4\xa6\x2e\x2f\xa4\x46\x89\xe7\x06\xbd\x1f\x81\xf8\xc4\x5f\x12\x12\x6c\x59\x67\x87\x05\xab\x2d\xfd\x8e\x1c\x82\x30\xec\xac\x86\x74\x0f\xde\x78\x57\xb3\x69\xb7\x7d\xa5\xf9\xe0\x6f\x85\xfa\x45\x11\x12\xa6\xbb\xdb\xcb\xd2\x4b\xf2\x0a\x84\x73\xbb\x28\x84\x02\x00\x00")
+
+func mysql013_add_hook_jobsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql013_add_hook_jobsSql,
+		"mysql/013_add_hook_jobs.sql",
+	)
+}
+
+func mysql013_add_hook_jobsSql() (*asset, error) {
+	bytes, err := mysql013_add_hook_jobsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/013_add_hook_jobs.sql", size: 644, mode: os.FileMode(420), modTime: time.Unix(1792349878, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql014_add_leasesSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x85\x8f\x4d\x0b\x82\x40\x14\x45\xf7\xef\x57\xbc\xa5\x52\x6e\x02\x57\xae\xfc\xa2\x84\xd2\x10\x6d\x2b\x53\xbd\x64\x48\x47\x9b\x19\x49\xff\x7d\x5a\x61\xb5\x6a\x77\xe1\x1e\xce\xe5\x5a\x16\x2e\x6a\x5e\x4a\xa6\x09\xf3\x16\xe0\x24\x69\x8a\x9a\x1d\x2b\x42\x7e\x41\xd1\x68\xa4\x9e\x2b\xad\xb0\x22\xa6\x48\x19\x80\xaf\x54\xf0\x33\x72\xa1\xa9\x24\x89\xad\xe4\x35\x93\x03\x5e\x69\x40\x37\xcf\x92\x22\x8a\xfd\x34\xdc\x85\x71\xb6\x9c\xf1\xa9\x3b\xb8\xa9\xbf\x71\x53\x63\x65\xdb\xe6\x53\x2d\xba\xaa\xfa\x20\xcd\x5d\x8c\xb2\x3f\x10\xf5\x2d\x97\xa4\xd0\x8b\xd6\x51\x9c\xfd\x00\x9d\xe0\xb7\x8e\x8c\x79\xcf\x04\xd3\x01\xb0\xbe\x2e\x06\xe3\x04\x40\x90\x26\x7b\xcc\x5c\x6f\x1b\xbe\x4f\x39\xf0\x00\xda\xfa\x9f\x28\x08\x01\x00\x00")
+
+func mysql014_add_leasesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql014_add_leasesSql,
+		"mysql/014_add_leases.sql",
+	)
+}
+
+func mysql014_add_leasesSql() (*asset, error) {
+	bytes, err := mysql014_add_leasesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/014_add_leases.sql", size: 264, mode: os.FileMode(420), modTime: time.Unix(1792350165, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql015_add_hook_deliveriesSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x9d\x92\xc1\x6e\x83\x30\x0c\x86\xef\x79\x0a\x1f\xa9\xd6\x5e\x26\xf5\xd4\x13\x2d\x51\x87\x54\xe8\x84\x60\xea\x0d\xa5\xe0\xd1\xa8\x29\x41\x4e\xe8\xc6\xdb\x0f\xb6\x69\x6a\x07\x02\xa9\xb7\x28\xf9\xfc\x29\xbf\xed\xc5\x02\x9e\x2e\xb2\x20\x61\x11\x92\x8a\xb1\x8c\xb0\x3b\x5a\x71\x54\x08\xf2\x1d\x4a\x6d\x01\x3f\xa5\xb1\x06\x4e\x5a\x9f\xd3\x1c\x95\xbc\x22\x49\x34\x0e\x83\xbb\xab\x26\x95\x39\xc8\xd2\x62\x81\x04\x15\xc9\x8b\xa0\x06\xce\xd8\x80\x9b\xc4\xfb\xd4\x0f\x37\x11\x0f\x78\x18\xcf\x7b\x65\x45\xdd\x16\xbe\xb9\xd1\xe6\xc5\x8d\x9c\xe7\xe5\x72\xd6\x47\xf0\x8a\xa5\xbd\x63\xbe\x3f\x56\xd6\x4a\xf5\x61\x91\x59\xa9\xcb\x09\x23\x61\xa5\x27\x90\x8a\xc0\x0f\x63\xbe\xe5\x51\xff\xed\x28\x0c\xa6\x35\xa9\x09\xc5\x09\x45\x8e\x64\x20\xe0\x9e\x9f\x04\x31\x3f\x0c\xc4\xaf\x44\xa3\xb4\xc8\x6f\x98\x91\x6c\x84\xa6\x56\x76\xd4\x87\x44\x9a\x46\x89\x9f\x19\xe7\xb0\xf6\xb7\xfe\xd0\x40\x2a\xd2\x19\x1a\xf3\x47\xb0\xd9\x8a\x31\x77\x17\xf3\x08\x62\x77\xbd\xe3\xff\x37\x01\x5c\xcf\x6b\x5b\xe5\xf1\x03\x38\xfd\x2e\xcf\x7b\xf6\x56\xf7\x90\xad\x5b\x94\x47\x6b\x7f\x33\x77\x49\x16\x37\x2b\xef\xe9\x8f\x92\x31\x2f\xda\xbf\x0e\x0b\x57\xec\x0b\xd8\x5a\x4f\x66\x21\x03\x00\x00")
+
+func mysql015_add_hook_deliveriesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql015_add_hook_deliveriesSql,
+		"mysql/015_add_hook_deliveries.sql",
+	)
+}
+
+func mysql015_add_hook_deliveriesSql() (*asset, error) {
+	bytes, err := mysql015_add_hook_deliveriesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/015_add_hook_deliveries.sql", size: 801, mode: os.FileMode(420), modTime: time.Unix(1792351448, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql016_outbox_target_hostSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\xc8\x2f\x2d\x49\xca\xaf\x50\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x83\x0a\xc5\x17\xa7\x26\x17\xa5\x96\x58\x63\x53\xed\xe8\xe2\x82\xa6\x38\x23\xbf\xb8\x44\x21\xcc\x31\xc8\xd9\xc3\x31\x48\xc3\xc8\xd4\x54\x53\xc1\xc5\xd5\xcd\x31\xd4\x27\x44\x41\x5d\x9d\x48\x23\x4a\x8b\x53\x8b\x70\x1b\xc1\xa5\x8b\xe4\x09\x97\xfc\xf2\x3c\x62\xbd\x01\x72\x99\x35\x91\x6a\x41\x4e\x20\xd2\xb5\x90\xd0\x51\xf0\x75\x75\xf1\x0c\xf5\x0d\x71\x8d\x08\xb1\xe6\x02\x00\xa6\x7f\xed\x2b\x67\x01\x00\x00")
+
+func mysql016_outbox_target_hostSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql016_outbox_target_hostSql,
+		"mysql/016_outbox_target_host.sql",
+	)
+}
+
+func mysql016_outbox_target_hostSql() (*asset, error) {
+	bytes, err := mysql016_outbox_target_hostSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/016_outbox_target_host.sql", size: 359, mode: os.FileMode(420), modTime: time.Unix(1792352612, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysql017_hook_eventsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x3d\x8e\x4b\x0e\xc2\x30\x0c\x05\xf7\x39\xc5\xdb\xa3\x54\xb0\xe2\x02\x1c\x01\xd6\x55\x42\x4d\x6a\x01\x76\x89\x0d\x05\x4e\x4f\x3f\x12\xdb\x91\x66\x34\x31\x62\x73\xe7\x52\x93\x13\x4e\x43\x08\x31\xe2\xd8\x13\x46\xca\xbd\xea\xd5\xa0\x17\xd0\x9b\xcd\x59\x0a\x2a\x0d\x6a\xec\x5a\x99\x0c\x49\x3a\x68\x2d\x49\xf8\x9b\x9c\x55\x26\x52\x69\xd6\xed\x99\xed\x5c\x39\x53\x07\x57\xf8\x14\x13\x1a\x41\x2f\x12\x37\xe4\xcf\x42\xb6\xbb\x7d\x3b\xf7\xdb\x15\x37\xf6\xb8\x61\xbd\x98\x4a\xcd\x72\xf1\xbf\x3a\xe8\x28\xe1\x07\x32\x0e\x73\x3a\xa7\x00\x00\x00")
+
+func mysql017_hook_eventsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysql017_hook_eventsSql,
+		"mysql/017_hook_events.sql",
+	)
+}
+
+func mysql017_hook_eventsSql() (*asset, error) {
+	bytes, err := mysql017_hook_eventsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/017_hook_events.sql", size: 167, mode: os.FileMode(420), modTime: time.Unix(1792353302, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _postgres001_initSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8c\x92\xdf\x6a\x83\x30\x14\x87\xef\xf3\x14\xe7\xb2\xb2\xf6\x09\xbc\xd2\x79\x56\xc2\x5c\xec\x62\x04\x7b\x55\xc2\x16\x24\xd4\x7f\x44\xdb\xee\xf1\x87\x21\xda\x1a\xd8\xa8\x57\xe1\x23\xe7\xf8\xfd\x4e\xce\x6e\x07\x2f\x8d\xae\x8c\x1c\x15\x14\x3d\x21\xaf\x1c\x23\x81\x20\xa2\x38\x45\xa0\x6f\xc0\x32\x01\x58\xd2\x5c\xe4\x70\x19\x94\x19\x60\x43\xec\xe1\xa4\xbf\xc1\x7e\x31\xdd\xe7\xc8\x69\x94\xc2\x81\xd3\x8f\x88\x1f\xe1\x1d\x8f\x64\x6b\xef\xd4\x5d\xa5\x5b\x00\x10\x58\x0a\x87\xc6\xee\xac\x3c\xa4\x1a\xa9\xeb\x35\x92\x57\x39\x4a\xb3\x42\x83\xfa\x32\x6a\x74\x88\x6c\x0b\x46\x3f\x0b\xdc\xdc\x7f\x13\x90\x20\xfc\x57\xdf\xa8\xbe\xb3\xfa\xd3\x61\xd1\xff\xcb\xdf\x5e\x5a\x82\x52\x26\x70\x8f\xdc\xe1\xee\xd6\x2a\x03\x8b\xb1\x65\xad\x6c\x14\x78\x6c\xa8\x2f\x95\xcf\x6a\xdd\x9e\x7d\xd6\x1b\x7d\x9d\xe6\x0f\x71\x96\xa5\x18\xb1\xb9\xdc\x25\xf6\x22\x2f\xad\x57\x89\x29\x4b\xb0\xf4\x12\xeb\x9f\xd3\xca\x37\x63\xf3\x10\xee\x38\x08\x9f\xe9\x30\x0f\xc2\xeb\xe0\xf0\xa4\xf1\xb8\x47\x49\x77\x6b\x09\x49\x78\x76\x70\x0f\x61\x6b\xc2\x47\x62\x77\x29\x24\xbf\x01\x00\x00\xff\xff\x1e\xfd\x38\xa0\x7e\x02\x00\x00")
 
 func postgres001_initSqlBytes() ([]byte, error) {
@@ -508,6 +938,206 @@ func postgres007_add_slack_urlsSql() (*asset, error) {
 	return a, nil
 }
 
+var _postgres008_add_pending_deploymentsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x93\xc1\x6f\x82\x30\x14\xc6\xef\xfd\x2b\xde\x11\x33\xb8\x2c\xf1\xc4\x09\xb5\x73\x64\x4e\x4d\xe9\x16\x3c\x99\x4e\xaa\x69\x56\xdb\x0e\x8a\xc3\xff\x7e\x42\x32\x56\x91\xb1\xdd\xda\xf7\xfd\xfa\xfa\xfa\x7d\x69\x10\xc0\xdd\x51\x1c\x72\x66\x39\xbc\x18\x84\x76\x39\xaf\x97\x96\xbd\x49\x0e\x62\x0f\x4a\x5b\xe0\x95\x28\x6c\x01\x86\xab\x4c\xa8\xc3\x36\xe3\x46\xea\xf3\x91\x2b\x5b\x78\x08\xda\xb2\xc8\x60\x12\xcf\x13\x4c\xe2\x68\x01\x6b\x12\x3f\x47\x64\x03\x4f\x78\xe3\x3b\x4c\xce\x8d\xae\xc1\x78\x49\xf1\x1c\x93\xa6\xbb\x2a\xa5\x74\x19\x93\x0f\xca\x39\xdf\xc3\x6b\x44\xa6\x8f\x11\xf1\xee\xc7\xe3\x51\x2f\x64\x59\xf1\x7e\x45\xb9\x22\x57\xa7\xbf\x3b\x18\x76\x96\x9a\x65\x40\x71\x4a\xdd\xfa\x4e\x2b\xcb\xab\x8b\x1b\x37\x42\x63\x5c\xfb\x34\x34\x0a\x11\x9a\x12\x1c\x51\x7c\x29\xcd\x70\x0a\xf1\x03\x2c\x57\x14\x70\x1a\x27\x34\x01\x51\x6d\x5d\x53\x40\xab\x3e\x7f\xc1\xeb\x38\x57\x77\x1d\x88\xe8\xe7\xe8\x96\x19\x93\xeb\x13\x93\x4d\x46\xdf\x9b\xc1\x90\x5a\x68\x28\xa5\x16\xfa\x25\xa6\x56\x1f\x74\xb9\xa5\xa4\x3e\x08\xf5\x0f\xae\xe3\x6e\xad\x95\x4a\x7c\x94\xdc\xeb\x4e\xed\xbb\x23\xfa\x57\xf3\xf8\x9d\x7b\x47\x4d\x48\x81\xf3\x03\x66\xfa\x53\x21\x34\x23\xab\x35\xd0\x68\xb2\xc0\xbd\x86\x86\x2e\xd0\x13\x5a\x88\xbe\x00\xa8\x60\xa3\x0b\x55\x03\x00\x00")
+
+func postgres008_add_pending_deploymentsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres008_add_pending_deploymentsSql,
+		"postgres/008_add_pending_deployments.sql",
+	)
+}
+
+func postgres008_add_pending_deploymentsSql() (*asset, error) {
+	bytes, err := postgres008_add_pending_deploymentsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/008_add_pending_deployments.sql", size: 853, mode: os.FileMode(420), modTime: time.Unix(1792347057, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres009_add_teams_urlsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6d\x8e\xb1\x0e\x82\x40\x10\x44\xfb\xfd\x8a\x2d\x21\xc2\x17\x50\x41\x20\x86\x88\x91\x9c\x5a\x50\x91\x53\x57\xbc\xe4\x38\xf4\x6e\x2f\xf2\xf9\x02\x85\xa1\xa0\x9b\xbc\xc9\xbc\x4c\x1c\xe3\xae\x57\x9d\x95\x4c\x78\x7d\x03\xdc\x2d\xcd\x91\xe5\x4d\x13\xaa\x27\x9a\x81\x91\x46\xe5\xd8\x21\x93\xec\x5d\xeb\xad\x76\x01\x20\xaa\x07\x66\xe5\xfe\x5c\x88\x32\xad\xb0\x16\xe5\x31\x15\x0d\x1e\x8a\x26\x9a\xba\xd7\xe0\xb8\x35\xb2\x9f\x3c\x34\xf2\xe2\x30\x5e\xeb\xb9\xf2\x8e\xec\x06\xb5\x7a\x03\x1a\xf5\xf1\x14\xfc\x65\xd1\x32\x0e\x21\x4c\x00\xe2\xd5\xed\x7c\xf8\x1a\x80\x5c\x9c\x6a\xbc\xa4\x59\x55\xac\x8e\x26\xf0\x03\x95\x80\xd3\x1e\xe0\x00\x00\x00")
+
+func postgres009_add_teams_urlsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres009_add_teams_urlsSql,
+		"postgres/009_add_teams_urls.sql",
+	)
+}
+
+func postgres009_add_teams_urlsSql() (*asset, error) {
+	bytes, err := postgres009_add_teams_urlsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/009_add_teams_urls.sql", size: 224, mode: os.FileMode(420), modTime: time.Unix(1792347367, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres010_add_webhook_urlsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6d\x8f\xbd\x0e\x82\x40\x10\x84\xfb\x7d\x8a\x2d\x21\xc2\x13\x50\x41\x20\x86\x88\x91\x9c\x5a\x50\x11\xd0\x55\x2e\x1e\x77\x7a\x3f\x81\xc7\x17\x28\x8c\x46\xba\xc9\xcc\xe6\xdb\x99\x30\xc4\x4d\xcf\xef\xba\xb1\x84\xe7\x27\xc0\x45\xd3\x2c\x6d\xd3\x0a\x42\x7e\x43\xa9\x2c\xd2\xc8\x8d\x35\x38\x50\xdb\x29\xf5\xa8\x9d\x16\xc6\x03\x44\x7e\xc5\x24\xdf\x1e\x33\x96\xc7\x05\x96\x2c\xdf\xc7\xac\xc2\x5d\x56\x05\x53\xd6\x29\x63\x6b\xd9\xf4\x13\x89\x46\xbb\x50\xa4\x13\x62\x8e\x9c\x21\xbd\xe2\x6a\xf1\x6f\x1a\x9a\xea\xd8\x95\x63\xc9\x5f\x8e\xbc\xcf\x93\x60\x81\xfa\xe0\x47\x00\xe1\xd7\xa0\x54\x0d\x12\x20\x65\x87\x12\x4f\x71\x52\x64\x3f\x13\x22\x78\x03\x30\x3c\x23\x3b\xfc\x00\x00\x00")
+
+func postgres010_add_webhook_urlsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres010_add_webhook_urlsSql,
+		"postgres/010_add_webhook_urls.sql",
+	)
+}
+
+func postgres010_add_webhook_urlsSql() (*asset, error) {
+	bytes, err := postgres010_add_webhook_urlsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/010_add_webhook_urls.sql", size: 252, mode: os.FileMode(420), modTime: time.Unix(1792347616, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres011_add_outboxSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x85\x92\xc1\x4e\xc3\x30\x0c\x86\xef\x79\x0a\x1f\x3b\xb1\x5e\x90\x76\xda\x29\x5b\xc3\x88\x18\xdb\x94\x06\xb4\x9d\xaa\x8c\x86\xaa\x52\xda\x54\x89\x2b\xca\xdb\xd3\x8e\x22\xb2\x51\x89\x9b\x65\x7f\xb6\x7f\xff\x72\x1c\xc3\x5d\x55\x16\x4e\xa1\x86\x97\x86\x90\x37\xa7\x87\x10\xd5\xd9\x68\x28\xdf\xa1\xb6\x08\xba\x2b\x3d\x7a\xb0\x2d\x9e\x6d\x17\x11\x18\xa3\xac\xcc\x61\xc5\x37\x29\x13\x9c\x6e\xe1\x20\xf8\x33\x15\x27\x78\x62\xa7\xf9\x2f\x82\xca\x15\x1a\xe1\x95\x8a\xf5\x23\x15\xd1\xfd\x62\x31\xbb\x8c\xac\x5b\x63\x02\xac\x75\x06\x24\x3b\xca\x20\xe5\x75\x2f\x05\x6f\xb3\xb5\xaa\xb4\xff\x83\x9a\xb6\xb8\x5a\x11\xd4\x1a\xf5\x69\xac\xca\x2f\x2d\x53\x9b\x3d\x0e\xe7\xfe\xa7\x4f\x21\xea\xaa\xe9\x3d\xe0\x3b\xc9\x36\x4c\x4c\x31\xb5\xee\xf0\x07\x1c\x7c\xe9\xd1\x29\xcc\x28\x8f\x99\x76\xce\xba\xdb\x33\xbe\xad\xcf\xc7\xde\xd0\x9d\x26\x0f\x0a\x64\xb6\x24\x64\x2d\x18\x95\xac\x97\x93\xb0\x23\xf0\x07\xd8\xed\x25\xb0\x23\x4f\x65\x0a\x65\x97\x5d\xdd\x66\xeb\x71\x0e\x44\x61\x7e\x3e\xa5\x7b\x18\x1d\x07\x1f\x91\xd8\x8f\x9a\x90\x44\xec\x0f\x20\xe9\x6a\xcb\xc6\x9e\x25\xf9\x02\xc8\xc6\xdd\xec\x37\x02\x00\x00")
+
+func postgres011_add_outboxSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres011_add_outboxSql,
+		"postgres/011_add_outbox.sql",
+	)
+}
+
+func postgres011_add_outboxSql() (*asset, error) {
+	bytes, err := postgres011_add_outboxSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/011_add_outbox.sql", size: 567, mode: os.FileMode(420), modTime: time.Unix(1792348047, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres012_add_slack_linksSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x90\xb1\x0e\x82\x30\x10\x86\xf7\x7b\x8a\x1b\x21\xc2\x62\xc2\xc4\x54\x84\x28\x11\x95\x54\x34\x61\x22\x15\x2b\x69\x28\x45\x69\x89\x3e\xbe\x01\x17\x64\x62\xbb\xcb\x7d\xf9\xf3\xdd\xef\xba\xb8\x6a\x44\xd5\x31\xc3\xf1\xf2\x04\x28\x3b\x3e\x8c\x86\xdd\x24\x47\xf1\x40\xd5\x1a\xe4\x1f\xa1\x8d\x46\x2d\x59\x59\x17\x52\xa8\x5a\x5b\x80\x93\xb5\x10\x77\x0c\xe2\xed\x39\xa2\x31\x49\x30\xa5\xf1\x81\xd0\x1c\xf7\x51\xee\xfc\x63\x86\xb3\x06\xaf\x84\x6e\x76\x84\x5a\x6b\xcf\xb3\xc7\x74\xd5\x4b\x39\x03\x7b\xcd\xbb\x45\xa0\x6c\x2b\xa1\x16\x91\xbf\xbf\x46\xcf\xf8\x98\x0d\xc7\x5e\x89\x57\xcf\xad\x99\x9f\x33\xf7\xb0\xc1\xf6\x01\xdc\x49\x4d\x61\xfb\x56\x00\x21\x3d\xa5\x98\x91\x20\x89\xa6\xc5\xf8\xf0\x05\x31\x05\x86\x33\x51\x01\x00\x00")
+
+func postgres012_add_slack_linksSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres012_add_slack_linksSql,
+		"postgres/012_add_slack_links.sql",
+	)
+}
+
+func postgres012_add_slack_linksSql() (*asset, error) {
+	bytes, err := postgres012_add_slack_linksSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/012_add_slack_links.sql", size: 337, mode: os.FileMode(420), modTime: time.Unix(1792349210, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres013_add_hook_jobsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x92\x41\x53\xc2\x30\x10\x85\xef\xf9\x15\x7b\x6c\x47\xb8\x38\xc3\x89\x53\x81\x88\x19\x11\x98\x34\x3a\x70\xea\xa4\x76\xd5\x68\x48\x6a\x92\x22\xfc\x7b\x29\xa2\x50\xe8\x8c\xde\x92\xbc\x6f\xde\xee\xbe\x4d\xb7\x0b\x57\x2b\xf5\xe2\x64\x40\x78\x28\x09\x79\x72\x58\x1f\x83\xcc\x35\x82\x7a\x06\x63\x03\xe0\x46\xf9\xe0\xe1\xd5\xda\xf7\xec\xcd\xe6\x3e\x22\xf0\x7b\xc9\x54\x01\x03\x36\x4e\x29\x67\xc9\x04\xe6\x9c\xdd\x27\x7c\x09\x77\x74\xd9\x39\x85\x0a\xd4\x6a\x8d\x6e\x0b\x8f\x09\x1f\xde\x26\x3c\xba\xee\xf5\xe2\xbd\xb7\xa9\xb4\x6e\xa0\xb8\x46\x13\xfe\xc1\xe5\xd2\x63\x56\x39\xdd\x40\x1b\x44\x29\xb7\xda\xca\x02\x04\x5d\x88\x76\x0f\x1f\xea\x59\xff\xae\x25\x43\xc0\x55\xb9\x8b\x80\x4d\x05\x1d\x53\xde\x4e\x19\xdc\x84\x1f\xb4\xce\x64\x07\xb7\x83\x5a\xfa\x90\xa1\x73\xd6\xed\x7b\x6b\x68\x0e\x7d\xa5\xc3\xe5\xfb\xf7\x5e\x8a\x83\x6f\x43\xaa\xca\xe2\x4c\xaa\x8c\xfa\xa8\x30\xba\x88\x3f\x26\x71\x9f\x90\x21\xa7\x89\xa0\xbb\x59\x46\x74\x01\xec\x06\xa6\x33\x01\x74\xc1\x52\x91\x82\xda\x64\x67\xe1\x58\x73\xdc\x3b\x44\x4d\xb1\xd3\x3e\x7b\x5d\xa3\x7b\xf2\xad\x46\xf6\xd3\x10\x32\xe2\xb3\x39\x88\x64\x30\xa1\x47\xc3\x3e\xf9\x02\x31\xda\x50\x87\x7f\x02\x00\x00")
+
+func postgres013_add_hook_jobsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres013_add_hook_jobsSql,
+		"postgres/013_add_hook_jobs.sql",
+	)
+}
+
+func postgres013_add_hook_jobsSql() (*asset, error) {
+	bytes, err := postgres013_add_hook_jobsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/013_add_hook_jobs.sql", size: 639, mode: os.FileMode(420), modTime: time.Unix(1792349878, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres014_add_leasesSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x85\x8f\xb1\x0e\x82\x30\x14\x45\xf7\xf7\x15\x6f\x84\x28\x8b\x09\x13\x53\x11\xa2\x8d\xa8\xa4\xa2\x89\x93\x41\x7d\x9a\x46\x2c\xd8\x96\x88\x7f\xaf\xa8\x41\x9d\xdc\xee\x70\x72\x4f\x8e\xe7\x61\xef\x2c\x8f\x3a\xb7\x84\xcb\x0a\x60\xa7\xa9\x9d\x36\xdf\x16\x84\xf2\x80\xaa\xb4\x48\x8d\x34\xd6\x60\x41\xb9\x21\xe3\x00\xbe\xd6\x46\xee\x31\xe4\xa3\x45\x2c\x38\x4b\x30\x15\x7c\xca\xc4\x1a\x27\xf1\xba\xdf\x11\x27\xba\xe1\x8a\x89\xe1\x98\x09\x67\xe0\xfb\xee\xf3\x4d\xd5\x45\xf1\x41\xca\xab\x22\xfd\x0f\xa2\xa6\x92\x9a\x4c\xab\xe3\xb3\xec\x07\xa8\x95\xbc\xd4\xe4\x74\x3e\x17\xdc\x00\xc0\xfb\xaa\x8a\x1e\x0a\x80\x48\xcc\x53\xcc\x58\x98\xc4\xef\x8e\x00\xee\x91\x58\x67\xb7\xfb\x00\x00\x00")
+
+func postgres014_add_leasesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres014_add_leasesSql,
+		"postgres/014_add_leases.sql",
+	)
+}
+
+func postgres014_add_leasesSql() (*asset, error) {
+	bytes, err := postgres014_add_leasesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/014_add_leases.sql", size: 251, mode: os.FileMode(420), modTime: time.Unix(1792350165, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres015_add_hook_deliveriesSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x95\x93\x31\x6f\xc2\x30\x10\x85\x77\xff\x8a\x1b\x83\x0a\x4b\x25\x26\xa6\x40\x5c\x1a\x95\x02\x72\xdc\x2a\x4c\x91\x21\x57\xb0\xea\xc6\x91\xed\x50\xf8\xf7\x4d\xd2\x16\x15\x12\x1a\xb1\x59\xbe\x4f\xef\xf9\xdd\x9d\x07\x03\xb8\xfb\x90\x5b\x23\x1c\xc2\x4b\x4e\xc8\xc6\x60\x75\x74\x62\xad\x10\xe4\x1b\x64\xda\x01\x1e\xa4\x75\x16\x76\x5a\xbf\x27\x29\x2a\xb9\x47\x23\xd1\x7a\x04\xce\xae\x8e\x89\x4c\x61\x1c\x4e\x23\xca\x42\x7f\x06\x4b\x16\x3e\xfb\x6c\x05\x4f\x74\xd5\x6f\x90\xdb\xa2\x64\x5f\x7d\x36\x79\xf4\x99\x77\x3f\x1c\xf6\x9a\x08\xee\x31\x73\x67\x4c\xfd\x96\xac\x50\xaa\x09\x8b\x8d\x93\x3a\xeb\x50\x34\x98\xeb\x0e\x24\x37\x10\xce\x39\x9d\x52\xd6\xac\xad\x85\xc5\xa4\x30\xaa\x43\x62\x87\x22\x45\x63\x81\xd3\x98\xb7\x18\x88\xa3\xd2\x22\xad\xab\xff\xe4\x31\x68\x0b\xe5\xae\x68\xa0\x31\xda\x5c\xa9\x7d\x8f\xaf\x9e\x43\x19\xa4\x2d\xa0\xde\xa0\xb5\x27\x82\xf4\x46\x84\x4c\x18\xf5\x39\x2d\x93\x07\x34\x86\xf0\x01\xe6\x0b\x0e\x34\x0e\x23\x1e\x81\x3c\x24\x2d\x4d\x2c\x5b\x7d\xb1\x0c\xe0\x35\xb1\x7e\xc3\xbb\x34\xbb\xc5\xab\xde\x92\x6e\xaf\x0a\xbb\x51\xf9\xb7\x4d\xdd\xe2\x3f\x64\xd5\xa6\xc1\x9f\xaf\x12\xe8\xcf\x8c\x90\x80\x2d\x96\xc0\xfd\xf1\x8c\x5e\xca\x8c\xc8\x17\x1b\x5b\xfc\x46\x59\x03\x00\x00")
+
+func postgres015_add_hook_deliveriesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres015_add_hook_deliveriesSql,
+		"postgres/015_add_hook_deliveries.sql",
+	)
+}
+
+func postgres015_add_hook_deliveriesSql() (*asset, error) {
+	bytes, err := postgres015_add_hook_deliveriesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/015_add_hook_deliveries.sql", size: 857, mode: os.FileMode(420), modTime: time.Unix(1792351448, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres016_outbox_target_hostSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\xc8\x2f\x2d\x49\xca\xaf\x50\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x83\x0a\xc5\x17\xa7\x26\x17\xa5\x96\x58\x63\x53\xed\xe8\xe2\x82\xa6\x38\x23\xbf\xb8\x44\x21\xcc\x31\xc8\xd9\xc3\x31\x48\xc3\xc8\xd4\x54\x53\xc1\xc5\xd5\xcd\x31\xd4\x27\x44\x41\x5d\x9d\x48\x23\x4a\x8b\x53\x8b\x70\x1b\xc1\xa5\x8b\xe4\x09\x97\xfc\xf2\x3c\x62\xbd\x01\x72\x99\x35\x91\x6a\x41\x4e\x20\xd2\xb5\x90\xd0\x51\x08\x71\x8d\x08\xb1\xe6\x02\x00\x83\xf1\xd1\x82\x61\x01\x00\x00")
+
+func postgres016_outbox_target_hostSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres016_outbox_target_hostSql,
+		"postgres/016_outbox_target_host.sql",
+	)
+}
+
+func postgres016_outbox_target_hostSql() (*asset, error) {
+	bytes, err := postgres016_outbox_target_hostSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/016_outbox_target_host.sql", size: 353, mode: os.FileMode(420), modTime: time.Unix(1792352612, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgres017_hook_eventsSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x3d\x8e\x4b\x0e\xc2\x30\x0c\x05\xf7\x39\xc5\xdb\xa3\x54\xb0\xe2\x02\x1c\x01\xd6\x55\x42\x4d\x6a\x01\x76\x89\x0d\x05\x4e\x4f\x3f\x12\xdb\x91\x66\x34\x31\x62\x73\xe7\x52\x93\x13\x4e\x43\x08\x31\xe2\xd8\x13\x46\xca\xbd\xea\xd5\xa0\x17\xd0\x9b\xcd\x59\x0a\x2a\x0d\x6a\xec\x5a\x99\x0c\x49\x3a\x68\x2d\x49\xf8\x9b\x9c\x55\x26\x52\x69\xd6\xed\x99\xed\x5c\x39\x53\x07\x57\xf8\x14\x13\x1a\x41\x2f\x12\x37\xe4\xcf\x42\xb6\xbb\x7d\x3b\xf7\xdb\x15\x37\xf6\xb8\x61\xbd\x98\x4a\xcd\x72\xf1\xbf\x3a\xe8\x28\xe1\x07\x32\x0e\x73\x3a\xa7\x00\x00\x00")
+
+func postgres017_hook_eventsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgres017_hook_eventsSql,
+		"postgres/017_hook_events.sql",
+	)
+}
+
+func postgres017_hook_eventsSql() (*asset, error) {
+	bytes, err := postgres017_hook_eventsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/017_hook_events.sql", size: 167, mode: os.FileMode(420), modTime: time.Unix(1792353302, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -567,6 +1197,16 @@ var _bindata = map[string]func() (*asset, error){
 	"sqlite3/005_oauth_scope.sql": sqlite3005_oauth_scopeSql,
 	"sqlite3/006_add_orgs_table.sql": sqlite3006_add_orgs_tableSql,
 	"sqlite3/007_add_slack_urls.sql": sqlite3007_add_slack_urlsSql,
+	"sqlite3/008_add_pending_deployments.sql": sqlite3008_add_pending_deploymentsSql,
+	"sqlite3/009_add_teams_urls.sql": sqlite3009_add_teams_urlsSql,
+	"sqlite3/010_add_webhook_urls.sql": sqlite3010_add_webhook_urlsSql,
+	"sqlite3/011_add_outbox.sql": sqlite3011_add_outboxSql,
+	"sqlite3/012_add_slack_links.sql": sqlite3012_add_slack_linksSql,
+	"sqlite3/013_add_hook_jobs.sql": sqlite3013_add_hook_jobsSql,
+	"sqlite3/014_add_leases.sql": sqlite3014_add_leasesSql,
+	"sqlite3/015_add_hook_deliveries.sql": sqlite3015_add_hook_deliveriesSql,
+	"sqlite3/016_outbox_target_host.sql": sqlite3016_outbox_target_hostSql,
+	"sqlite3/017_hook_events.sql": sqlite3017_hook_eventsSql,
 	"mysql/001_init.sql": mysql001_initSql,
 	"mysql/002_org.sql": mysql002_orgSql,
 	"mysql/003_drop_emails.sql": mysql003_drop_emailsSql,
@@ -574,6 +1214,16 @@ var _bindata = map[string]func() (*asset, error){
 	"mysql/005_oauth_scope.sql": mysql005_oauth_scopeSql,
 	"mysql/006_add_orgs_table.sql": mysql006_add_orgs_tableSql,
 	"mysql/007_add_slack_urls.sql": mysql007_add_slack_urlsSql,
+	"mysql/008_add_pending_deployments.sql": mysql008_add_pending_deploymentsSql,
+	"mysql/009_add_teams_urls.sql": mysql009_add_teams_urlsSql,
+	"mysql/010_add_webhook_urls.sql": mysql010_add_webhook_urlsSql,
+	"mysql/011_add_outbox.sql": mysql011_add_outboxSql,
+	"mysql/012_add_slack_links.sql": mysql012_add_slack_linksSql,
+	"mysql/013_add_hook_jobs.sql": mysql013_add_hook_jobsSql,
+	"mysql/014_add_leases.sql": mysql014_add_leasesSql,
+	"mysql/015_add_hook_deliveries.sql": mysql015_add_hook_deliveriesSql,
+	"mysql/016_outbox_target_host.sql": mysql016_outbox_target_hostSql,
+	"mysql/017_hook_events.sql": mysql017_hook_eventsSql,
 	"postgres/001_init.sql": postgres001_initSql,
 	"postgres/002_org.sql": postgres002_orgSql,
 	"postgres/003_drop_emails.sql": postgres003_drop_emailsSql,
@@ -581,6 +1231,16 @@ var _bindata = map[string]func() (*asset, error){
 	"postgres/005_oauth_scope.sql": postgres005_oauth_scopeSql,
 	"postgres/006_add_orgs_table.sql": postgres006_add_orgs_tableSql,
 	"postgres/007_add_slack_urls.sql": postgres007_add_slack_urlsSql,
+	"postgres/008_add_pending_deployments.sql": postgres008_add_pending_deploymentsSql,
+	"postgres/009_add_teams_urls.sql": postgres009_add_teams_urlsSql,
+	"postgres/010_add_webhook_urls.sql": postgres010_add_webhook_urlsSql,
+	"postgres/011_add_outbox.sql": postgres011_add_outboxSql,
+	"postgres/012_add_slack_links.sql": postgres012_add_slack_linksSql,
+	"postgres/013_add_hook_jobs.sql": postgres013_add_hook_jobsSql,
+	"postgres/014_add_leases.sql": postgres014_add_leasesSql,
+	"postgres/015_add_hook_deliveries.sql": postgres015_add_hook_deliveriesSql,
+	"postgres/016_outbox_target_host.sql": postgres016_outbox_target_hostSql,
+	"postgres/017_hook_events.sql": postgres017_hook_eventsSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -631,6 +1291,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"005_oauth_scope.sql": &bintree{mysql005_oauth_scopeSql, map[string]*bintree{}},
 		"006_add_orgs_table.sql": &bintree{mysql006_add_orgs_tableSql, map[string]*bintree{}},
 		"007_add_slack_urls.sql": &bintree{mysql007_add_slack_urlsSql, map[string]*bintree{}},
+		"008_add_pending_deployments.sql": &bintree{mysql008_add_pending_deploymentsSql, map[string]*bintree{}},
+		"009_add_teams_urls.sql": &bintree{mysql009_add_teams_urlsSql, map[string]*bintree{}},
+		"010_add_webhook_urls.sql": &bintree{mysql010_add_webhook_urlsSql, map[string]*bintree{}},
+		"011_add_outbox.sql": &bintree{mysql011_add_outboxSql, map[string]*bintree{}},
+		"012_add_slack_links.sql": &bintree{mysql012_add_slack_linksSql, map[string]*bintree{}},
+		"013_add_hook_jobs.sql": &bintree{mysql013_add_hook_jobsSql, map[string]*bintree{}},
+		"014_add_leases.sql": &bintree{mysql014_add_leasesSql, map[string]*bintree{}},
+		"015_add_hook_deliveries.sql": &bintree{mysql015_add_hook_deliveriesSql, map[string]*bintree{}},
+		"016_outbox_target_host.sql": &bintree{mysql016_outbox_target_hostSql, map[string]*bintree{}},
+		"017_hook_events.sql": &bintree{mysql017_hook_eventsSql, map[string]*bintree{}},
 	}},
 	"postgres": &bintree{nil, map[string]*bintree{
 		"001_init.sql": &bintree{postgres001_initSql, map[string]*bintree{}},
@@ -640,6 +1310,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"005_oauth_scope.sql": &bintree{postgres005_oauth_scopeSql, map[string]*bintree{}},
 		"006_add_orgs_table.sql": &bintree{postgres006_add_orgs_tableSql, map[string]*bintree{}},
 		"007_add_slack_urls.sql": &bintree{postgres007_add_slack_urlsSql, map[string]*bintree{}},
+		"008_add_pending_deployments.sql": &bintree{postgres008_add_pending_deploymentsSql, map[string]*bintree{}},
+		"009_add_teams_urls.sql": &bintree{postgres009_add_teams_urlsSql, map[string]*bintree{}},
+		"010_add_webhook_urls.sql": &bintree{postgres010_add_webhook_urlsSql, map[string]*bintree{}},
+		"011_add_outbox.sql": &bintree{postgres011_add_outboxSql, map[string]*bintree{}},
+		"012_add_slack_links.sql": &bintree{postgres012_add_slack_linksSql, map[string]*bintree{}},
+		"013_add_hook_jobs.sql": &bintree{postgres013_add_hook_jobsSql, map[string]*bintree{}},
+		"014_add_leases.sql": &bintree{postgres014_add_leasesSql, map[string]*bintree{}},
+		"015_add_hook_deliveries.sql": &bintree{postgres015_add_hook_deliveriesSql, map[string]*bintree{}},
+		"016_outbox_target_host.sql": &bintree{postgres016_outbox_target_hostSql, map[string]*bintree{}},
+		"017_hook_events.sql": &bintree{postgres017_hook_eventsSql, map[string]*bintree{}},
 	}},
 	"sqlite3": &bintree{nil, map[string]*bintree{
 		"001_init.sql": &bintree{sqlite3001_initSql, map[string]*bintree{}},
@@ -649,6 +1329,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"005_oauth_scope.sql": &bintree{sqlite3005_oauth_scopeSql, map[string]*bintree{}},
 		"006_add_orgs_table.sql": &bintree{sqlite3006_add_orgs_tableSql, map[string]*bintree{}},
 		"007_add_slack_urls.sql": &bintree{sqlite3007_add_slack_urlsSql, map[string]*bintree{}},
+		"008_add_pending_deployments.sql": &bintree{sqlite3008_add_pending_deploymentsSql, map[string]*bintree{}},
+		"009_add_teams_urls.sql": &bintree{sqlite3009_add_teams_urlsSql, map[string]*bintree{}},
+		"010_add_webhook_urls.sql": &bintree{sqlite3010_add_webhook_urlsSql, map[string]*bintree{}},
+		"011_add_outbox.sql": &bintree{sqlite3011_add_outboxSql, map[string]*bintree{}},
+		"012_add_slack_links.sql": &bintree{sqlite3012_add_slack_linksSql, map[string]*bintree{}},
+		"013_add_hook_jobs.sql": &bintree{sqlite3013_add_hook_jobsSql, map[string]*bintree{}},
+		"014_add_leases.sql": &bintree{sqlite3014_add_leasesSql, map[string]*bintree{}},
+		"015_add_hook_deliveries.sql": &bintree{sqlite3015_add_hook_deliveriesSql, map[string]*bintree{}},
+		"016_outbox_target_host.sql": &bintree{sqlite3016_outbox_target_hostSql, map[string]*bintree{}},
+		"017_hook_events.sql": &bintree{sqlite3017_hook_eventsSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -0,0 +1,29 @@
+-- +migrate Up
+
+create table if not exists pending_deployments(
+  pending_id integer primary key AUTO_INCREMENT,
+  pending_repo_id integer not null,
+  pending_pr integer not null,
+  pending_ref VARCHAR(255) not null,
+  pending_task VARCHAR(255),
+  pending_env VARCHAR(255) not null,
+  pending_payload TEXT,
+  pending_contexts TEXT,
+  pending_created integer,
+  index ix_pending_repo (pending_repo_id)
+);
+
+create table if not exists deployment_approvals(
+  approval_id integer primary key AUTO_INCREMENT,
+  approval_repo_id integer not null,
+  approval_pr integer not null,
+  approval_env VARCHAR(255) not null,
+  approval_login VARCHAR(255) not null,
+  approval_created integer,
+  unique(approval_repo_id, approval_pr, approval_env, approval_login)
+);
+
+-- +migrate Down
+
+DROP TABLE deployment_approvals;
+DROP TABLE pending_deployments;
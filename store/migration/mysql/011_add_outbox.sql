@@ -0,0 +1,23 @@
+-- +migrate Up
+
+create table if not exists outbox(
+  outbox_id integer primary key AUTO_INCREMENT,
+  outbox_target VARCHAR(255) not null,
+  outbox_url MEDIUMTEXT,
+  outbox_secret MEDIUMTEXT,
+  outbox_names MEDIUMTEXT,
+  outbox_slug VARCHAR(255),
+  outbox_payload MEDIUMTEXT not null,
+  outbox_state VARCHAR(255) not null,
+  outbox_attempts INTEGER not null,
+  outbox_next_attempt BIGINT not null,
+  outbox_last_error MEDIUMTEXT,
+  outbox_created BIGINT,
+  outbox_updated BIGINT
+);
+
+ALTER TABLE outbox ADD INDEX (outbox_state, outbox_next_attempt);
+
+-- +migrate Down
+
+DROP TABLE outbox;
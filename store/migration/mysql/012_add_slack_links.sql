@@ -0,0 +1,14 @@
+-- +migrate Up
+
+create table if not exists slack_links(
+  slack_link_id integer primary key AUTO_INCREMENT,
+  slack_link_team VARCHAR(255) not null,
+  slack_link_user VARCHAR(255) not null,
+  slack_link_login VARCHAR(255) not null,
+  slack_link_created BIGINT,
+  unique(slack_link_team, slack_link_user)
+);
+
+-- +migrate Down
+
+DROP TABLE slack_links;
@@ -0,0 +1,23 @@
+-- +migrate Up
+
+create table if not exists hook_jobs(
+  hook_job_id integer primary key AUTO_INCREMENT,
+  hook_job_delivery VARCHAR(255) not null,
+  hook_job_event VARCHAR(255) not null,
+  hook_job_base_url VARCHAR(255),
+  hook_job_payload MEDIUMTEXT not null,
+  hook_job_state VARCHAR(255) not null,
+  hook_job_attempts INTEGER not null,
+  hook_job_next_attempt BIGINT not null,
+  hook_job_last_error MEDIUMTEXT,
+  hook_job_result MEDIUMTEXT,
+  hook_job_created BIGINT,
+  hook_job_updated BIGINT,
+  unique(hook_job_delivery)
+);
+
+ALTER TABLE hook_jobs ADD INDEX (hook_job_state, hook_job_next_attempt);
+
+-- +migrate Down
+
+DROP TABLE hook_jobs;
@@ -0,0 +1,25 @@
+-- +migrate Up
+
+create table if not exists hook_deliveries(
+  hook_delivery_id integer primary key AUTO_INCREMENT,
+  hook_delivery_guid VARCHAR(255),
+  hook_delivery_event VARCHAR(255) not null,
+  hook_delivery_action VARCHAR(255),
+  hook_delivery_repo VARCHAR(255),
+  hook_delivery_pr INTEGER,
+  hook_delivery_base_url VARCHAR(255),
+  hook_delivery_headers MEDIUMTEXT,
+  hook_delivery_payload MEDIUMTEXT not null,
+  hook_delivery_result MEDIUMTEXT,
+  hook_delivery_error MEDIUMTEXT,
+  hook_delivery_created BIGINT,
+  hook_delivery_processed BIGINT
+);
+
+ALTER TABLE hook_deliveries ADD INDEX (hook_delivery_repo, hook_delivery_pr);
+ALTER TABLE hook_deliveries ADD INDEX (hook_delivery_guid);
+ALTER TABLE hook_deliveries ADD INDEX (hook_delivery_created);
+
+-- +migrate Down
+
+DROP TABLE hook_deliveries;
@@ -0,0 +1,30 @@
+-- +migrate Up
+
+create table if not exists pending_deployments(
+  pending_id BIGSERIAL PRIMARY KEY,
+  pending_repo_id INTEGER not null,
+  pending_pr INTEGER not null,
+  pending_ref VARCHAR(255) not null,
+  pending_task VARCHAR(255),
+  pending_env VARCHAR(255) not null,
+  pending_payload TEXT,
+  pending_contexts TEXT,
+  pending_created INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS ix_pending_repo on pending_deployments (pending_repo_id);
+
+create table if not exists deployment_approvals(
+  approval_id BIGSERIAL PRIMARY KEY,
+  approval_repo_id INTEGER not null,
+  approval_pr INTEGER not null,
+  approval_env VARCHAR(255) not null,
+  approval_login VARCHAR(255) not null,
+  approval_created INTEGER,
+  unique(approval_repo_id, approval_pr, approval_env, approval_login)
+);
+
+-- +migrate Down
+
+DROP TABLE deployment_approvals;
+DROP TABLE pending_deployments;
@@ -0,0 +1,23 @@
+-- +migrate Up
+
+create table if not exists outbox(
+  outbox_id BIGSERIAL PRIMARY KEY,
+  outbox_target VARCHAR(255) not null,
+  outbox_url TEXT,
+  outbox_secret TEXT,
+  outbox_names TEXT,
+  outbox_slug VARCHAR(255),
+  outbox_payload TEXT not null,
+  outbox_state VARCHAR(255) not null,
+  outbox_attempts INTEGER not null,
+  outbox_next_attempt BIGINT not null,
+  outbox_last_error TEXT,
+  outbox_created BIGINT,
+  outbox_updated BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS ix_outbox_state on outbox (outbox_state, outbox_next_attempt);
+
+-- +migrate Down
+
+DROP TABLE outbox;
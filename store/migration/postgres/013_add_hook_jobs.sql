@@ -0,0 +1,23 @@
+-- +migrate Up
+
+create table if not exists hook_jobs(
+  hook_job_id BIGSERIAL PRIMARY KEY,
+  hook_job_delivery VARCHAR(255) not null,
+  hook_job_event VARCHAR(255) not null,
+  hook_job_base_url VARCHAR(255),
+  hook_job_payload TEXT not null,
+  hook_job_state VARCHAR(255) not null,
+  hook_job_attempts INTEGER not null,
+  hook_job_next_attempt BIGINT not null,
+  hook_job_last_error TEXT,
+  hook_job_result TEXT,
+  hook_job_created BIGINT,
+  hook_job_updated BIGINT,
+  unique(hook_job_delivery)
+);
+
+CREATE INDEX IF NOT EXISTS ix_hook_job_state on hook_jobs (hook_job_state, hook_job_next_attempt);
+
+-- +migrate Down
+
+DROP TABLE hook_jobs;
@@ -0,0 +1,13 @@
+-- +migrate Up
+
+create table if not exists leases(
+  lease_id BIGSERIAL PRIMARY KEY,
+  lease_key VARCHAR(255) not null,
+  lease_owner VARCHAR(255) not null,
+  lease_expires BIGINT not null,
+  unique(lease_key)
+);
+
+-- +migrate Down
+
+DROP TABLE leases;
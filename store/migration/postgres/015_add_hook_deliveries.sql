@@ -0,0 +1,25 @@
+-- +migrate Up
+
+create table if not exists hook_deliveries(
+  hook_delivery_id BIGSERIAL PRIMARY KEY,
+  hook_delivery_guid VARCHAR(255),
+  hook_delivery_event VARCHAR(255) not null,
+  hook_delivery_action VARCHAR(255),
+  hook_delivery_repo VARCHAR(255),
+  hook_delivery_pr INTEGER,
+  hook_delivery_base_url VARCHAR(255),
+  hook_delivery_headers TEXT,
+  hook_delivery_payload TEXT not null,
+  hook_delivery_result TEXT,
+  hook_delivery_error TEXT,
+  hook_delivery_created BIGINT,
+  hook_delivery_processed BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS ix_hook_delivery_repo on hook_deliveries (hook_delivery_repo, hook_delivery_pr);
+CREATE INDEX IF NOT EXISTS ix_hook_delivery_guid on hook_deliveries (hook_delivery_guid);
+CREATE INDEX IF NOT EXISTS ix_hook_delivery_created on hook_deliveries (hook_delivery_created);
+
+-- +migrate Down
+
+DROP TABLE hook_deliveries;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+ALTER TABLE outbox DROP COLUMN outbox_secret;
+ALTER TABLE outbox ADD COLUMN outbox_host VARCHAR(255) DEFAULT '';
+ALTER TABLE outbox ADD COLUMN outbox_user VARCHAR(255) DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE outbox DROP COLUMN outbox_host;
+ALTER TABLE outbox DROP COLUMN outbox_user;
+ALTER TABLE outbox ADD COLUMN outbox_secret TEXT;
@@ -0,0 +1,30 @@
+-- +migrate Up
+
+create table if not exists pending_deployments(
+  pending_id integer primary key autoincrement,
+  pending_repo_id integer not null,
+  pending_pr integer not null,
+  pending_ref text not null,
+  pending_task text,
+  pending_env text not null,
+  pending_payload text,
+  pending_contexts text,
+  pending_created integer
+);
+
+create index if not exists ix_pending_repo on pending_deployments (pending_repo_id);
+
+create table if not exists deployment_approvals(
+  approval_id integer primary key autoincrement,
+  approval_repo_id integer not null,
+  approval_pr integer not null,
+  approval_env text not null,
+  approval_login text not null,
+  approval_created integer,
+  unique(approval_repo_id, approval_pr, approval_env, approval_login)
+);
+
+-- +migrate Down
+
+DROP TABLE deployment_approvals;
+DROP TABLE pending_deployments;
@@ -0,0 +1,13 @@
+-- +migrate Up
+
+create table if not exists teams_urls(
+  id integer primary key autoincrement,
+  host_name text not null,
+  user text not null,
+  url text not null,
+  unique(host_name, user)
+);
+
+-- +migrate Down
+
+DROP TABLE teams_urls;
@@ -0,0 +1,14 @@
+-- +migrate Up
+
+create table if not exists webhook_urls(
+  id integer primary key autoincrement,
+  host_name text not null,
+  user text not null,
+  url text not null,
+  secret text not null,
+  unique(host_name, user)
+);
+
+-- +migrate Down
+
+DROP TABLE webhook_urls;
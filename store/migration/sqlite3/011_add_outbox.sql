@@ -0,0 +1,23 @@
+-- +migrate Up
+
+create table if not exists outbox(
+  outbox_id integer primary key autoincrement,
+  outbox_target text not null,
+  outbox_url text,
+  outbox_secret text,
+  outbox_names text,
+  outbox_slug text,
+  outbox_payload text not null,
+  outbox_state text not null,
+  outbox_attempts INTEGER not null,
+  outbox_next_attempt INTEGER not null,
+  outbox_last_error text,
+  outbox_created INTEGER,
+  outbox_updated INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS ix_outbox_state on outbox (outbox_state, outbox_next_attempt);
+
+-- +migrate Down
+
+DROP TABLE outbox;
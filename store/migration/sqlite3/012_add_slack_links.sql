@@ -0,0 +1,14 @@
+-- +migrate Up
+
+create table if not exists slack_links(
+  slack_link_id integer primary key autoincrement,
+  slack_link_team text not null,
+  slack_link_user text not null,
+  slack_link_login text not null,
+  slack_link_created INTEGER,
+  unique(slack_link_team, slack_link_user)
+);
+
+-- +migrate Down
+
+DROP TABLE slack_links;
@@ -0,0 +1,23 @@
+-- +migrate Up
+
+create table if not exists hook_jobs(
+  hook_job_id integer primary key autoincrement,
+  hook_job_delivery text not null,
+  hook_job_event text not null,
+  hook_job_base_url text,
+  hook_job_payload text not null,
+  hook_job_state text not null,
+  hook_job_attempts INTEGER not null,
+  hook_job_next_attempt INTEGER not null,
+  hook_job_last_error text,
+  hook_job_result text,
+  hook_job_created INTEGER,
+  hook_job_updated INTEGER,
+  unique(hook_job_delivery)
+);
+
+CREATE INDEX IF NOT EXISTS ix_hook_job_state on hook_jobs (hook_job_state, hook_job_next_attempt);
+
+-- +migrate Down
+
+DROP TABLE hook_jobs;
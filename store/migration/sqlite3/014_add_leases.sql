@@ -0,0 +1,13 @@
+-- +migrate Up
+
+create table if not exists leases(
+  lease_id integer primary key autoincrement,
+  lease_key text not null,
+  lease_owner text not null,
+  lease_expires INTEGER not null,
+  unique(lease_key)
+);
+
+-- +migrate Down
+
+DROP TABLE leases;
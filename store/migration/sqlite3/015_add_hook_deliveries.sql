@@ -0,0 +1,25 @@
+-- +migrate Up
+
+create table if not exists hook_deliveries(
+  hook_delivery_id integer primary key autoincrement,
+  hook_delivery_guid text,
+  hook_delivery_event text not null,
+  hook_delivery_action text,
+  hook_delivery_repo text,
+  hook_delivery_pr INTEGER,
+  hook_delivery_base_url text,
+  hook_delivery_headers text,
+  hook_delivery_payload text not null,
+  hook_delivery_result text,
+  hook_delivery_error text,
+  hook_delivery_created INTEGER,
+  hook_delivery_processed INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS ix_hook_delivery_repo on hook_deliveries (hook_delivery_repo, hook_delivery_pr);
+CREATE INDEX IF NOT EXISTS ix_hook_delivery_guid on hook_deliveries (hook_delivery_guid);
+CREATE INDEX IF NOT EXISTS ix_hook_delivery_created on hook_deliveries (hook_delivery_created);
+
+-- +migrate Down
+
+DROP TABLE hook_deliveries;
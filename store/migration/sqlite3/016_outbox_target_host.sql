@@ -0,0 +1,36 @@
+-- +migrate Up
+
+ALTER TABLE outbox RENAME TO temp_outbox;
+
+create table if not exists outbox(
+  outbox_id integer primary key autoincrement,
+  outbox_target text not null,
+  outbox_url text,
+  outbox_host text,
+  outbox_user text,
+  outbox_names text,
+  outbox_slug text,
+  outbox_payload text not null,
+  outbox_state text not null,
+  outbox_attempts INTEGER not null,
+  outbox_next_attempt INTEGER not null,
+  outbox_last_error text,
+  outbox_created INTEGER,
+  outbox_updated INTEGER
+);
+
+INSERT INTO outbox
+SELECT
+  outbox_id, outbox_target, outbox_url, '', '', outbox_names, outbox_slug,
+  outbox_payload, outbox_state, outbox_attempts, outbox_next_attempt,
+  outbox_last_error, outbox_created, outbox_updated
+FROM
+  temp_outbox;
+
+DROP TABLE temp_outbox;
+
+CREATE INDEX IF NOT EXISTS ix_outbox_state on outbox (outbox_state, outbox_next_attempt);
+
+-- +migrate Down
+
+ALTER TABLE outbox ADD COLUMN outbox_secret text;
@@ -0,0 +1,6 @@
+-- +migrate Up
+
+-- The webhooks of existing repositories and organizations are
+-- subscribed to the new events by the 017_hook_events.sql migration.
+
+-- +migrate Down
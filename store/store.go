@@ -108,6 +108,135 @@ type Store interface {
 	// Deletes the slack URL for the specified hostname and user
 	// if the user string is blank, the default (admin-level) hostname is deleted
 	DeleteSlackUrl(hostname string, user string) error
+
+	// GetTeamsUrl returns the Teams webhook URL that the user registered
+	// for the hostname. A blank user selects the admin-level URL.
+	// An empty string is returned when no URL is registered.
+	GetTeamsUrl(hostname string, user string) (string, error)
+
+	// AddUpdateTeamsUrl registers the Teams webhook URL of the user
+	// for the hostname. A blank user registers the admin-level URL.
+	AddUpdateTeamsUrl(hostname string, user string, url string) error
+
+	// DeleteTeamsUrl removes the Teams webhook URL of the user
+	// for the hostname. A blank user removes the admin-level URL.
+	DeleteTeamsUrl(hostname string, user string) error
+
+	// GetWebhookTarget returns the webhook URL and signing secret that
+	// the user registered for the hostname. A blank user selects the
+	// admin-level target. Nil is returned when no target is registered.
+	GetWebhookTarget(hostname string, user string) (*model.WebhookTarget, error)
+
+	// AddUpdateWebhookTarget registers the webhook target of the user
+	// for the hostname. A blank user registers the admin-level target.
+	AddUpdateWebhookTarget(hostname string, user string, target *model.WebhookTarget) error
+
+	// DeleteWebhookTarget removes the webhook target of the user
+	// for the hostname. A blank user removes the admin-level target.
+	DeleteWebhookTarget(hostname string, user string) error
+
+	// CreateOutboxMessage queues a notification for delivery.
+	CreateOutboxMessage(msg *model.OutboxMessage) error
+
+	// GetOutboxMessage gets a queued notification by id.
+	GetOutboxMessage(id int64) (*model.OutboxMessage, error)
+
+	// GetDueOutboxMessages gets up to limit pending notifications
+	// whose next attempt is at or before now.
+	GetDueOutboxMessages(now int64, limit int) ([]*model.OutboxMessage, error)
+
+	// ClaimOutboxMessage leases a pending notification by moving its
+	// next attempt from due to lease. It returns false when another
+	// dispatcher claimed the notification first.
+	ClaimOutboxMessage(id int64, due int64, lease int64) (bool, error)
+
+	// GetOutboxMessagesByState gets the queued notifications in a state.
+	GetOutboxMessagesByState(state string) ([]*model.OutboxMessage, error)
+
+	// UpdateOutboxMessage updates a queued notification.
+	UpdateOutboxMessage(msg *model.OutboxMessage) error
+
+	// DeleteOutboxMessage removes a notification from the outbox.
+	DeleteOutboxMessage(id int64) error
+
+	// GetSlackLink gets the GitHub user that is linked to a Slack user.
+	GetSlackLink(team string, user string) (*model.SlackLink, error)
+
+	// CreateSlackLink links a Slack user to a GitHub user.
+	// The previous link of the Slack user is replaced.
+	CreateSlackLink(link *model.SlackLink) error
+
+	// DeleteSlackLink removes the link of a Slack user.
+	DeleteSlackLink(team string, user string) error
+
+	// CreateHookJob queues a webhook delivery for processing.
+	CreateHookJob(job *model.HookJob) error
+
+	// GetHookJobDelivery gets a queued webhook by its delivery id.
+	GetHookJobDelivery(delivery string) (*model.HookJob, error)
+
+	// GetDueHookJobs gets up to limit pending webhooks
+	// that are due for processing.
+	GetDueHookJobs(now int64, limit int) ([]*model.HookJob, error)
+
+	// ClaimHookJob leases a pending webhook by moving its next
+	// attempt from due to lease. It returns false when another
+	// dispatcher claimed the webhook first.
+	ClaimHookJob(id int64, due int64, lease int64) (bool, error)
+
+	// GetHookJobsByState gets the latest queued webhooks in a state.
+	GetHookJobsByState(state string, limit int) ([]*model.HookJob, error)
+
+	// UpdateHookJob updates a queued webhook.
+	UpdateHookJob(job *model.HookJob) error
+
+	// DeleteHookJobsBefore removes the processed webhooks
+	// that were last updated before the time.
+	DeleteHookJobsBefore(updated int64) error
+
+	// CreateHookDelivery archives an incoming webhook.
+	CreateHookDelivery(delivery *model.HookDelivery) error
+
+	// GetHookDelivery gets an archived webhook.
+	GetHookDelivery(id int64) (*model.HookDelivery, error)
+
+	// GetHookDeliveryGUID gets the latest archived webhook
+	// with the delivery id.
+	GetHookDeliveryGUID(guid string) (*model.HookDelivery, error)
+
+	// GetHookDeliveries gets the latest archived webhooks that
+	// match the filter.
+	GetHookDeliveries(filter model.HookDeliveryFilter) ([]*model.HookDelivery, error)
+
+	// UpdateHookDelivery updates an archived webhook.
+	UpdateHookDelivery(delivery *model.HookDelivery) error
+
+	// DeleteHookDeliveriesBefore removes the archived webhooks
+	// that were received before the time.
+	DeleteHookDeliveriesBefore(created int64) error
+
+	// AcquireLease leases the key to the owner until expires. The
+	// lease is granted if the key is free, expired at now or
+	// already leased to the owner.
+	AcquireLease(key, owner string, now, expires int64) (bool, error)
+
+	// ReleaseLease frees the key if it is leased to the owner.
+	ReleaseLease(key, owner string) error
+
+	// CreatePendingDeployment stores a deployment that is waiting for approval.
+	CreatePendingDeployment(*model.PendingDeployment) error
+
+	// GetPendingDeployments gets the deployments of a repo that are waiting for approval.
+	GetPendingDeployments(repoID int64) ([]*model.PendingDeployment, error)
+
+	// DeletePendingDeployment deletes a deployment that is waiting for approval.
+	DeletePendingDeployment(*model.PendingDeployment) error
+
+	// CreateDeploymentApproval stores an approval of the deployments of a pull request.
+	CreateDeploymentApproval(*model.DeploymentApproval) error
+
+	// GetDeploymentApprovals gets the approvals of the deployments of a pull request to an environment.
+	GetDeploymentApprovals(repoID int64, pr int, env string) ([]*model.DeploymentApproval, error)
 }
 
 // GetUser gets a user by unique ID.
@@ -261,3 +390,190 @@ func AddUpdateSlackUrl(c context.Context, hostname string, user string, url stri
 func DeleteSlackUrl(c context.Context, hostname string, user string) error {
 	return FromContext(c).DeleteSlackUrl(hostname, user)
 }
+
+func GetTeamsUrl(c context.Context, hostname string, user string) (string, error) {
+	return FromContext(c).GetTeamsUrl(hostname, user)
+}
+
+func AddUpdateTeamsUrl(c context.Context, hostname string, user string, url string) error {
+	return FromContext(c).AddUpdateTeamsUrl(hostname, user, url)
+}
+
+func DeleteTeamsUrl(c context.Context, hostname string, user string) error {
+	return FromContext(c).DeleteTeamsUrl(hostname, user)
+}
+
+func GetWebhookTarget(c context.Context, hostname string, user string) (*model.WebhookTarget, error) {
+	return FromContext(c).GetWebhookTarget(hostname, user)
+}
+
+func AddUpdateWebhookTarget(c context.Context, hostname string, user string, target *model.WebhookTarget) error {
+	return FromContext(c).AddUpdateWebhookTarget(hostname, user, target)
+}
+
+func DeleteWebhookTarget(c context.Context, hostname string, user string) error {
+	return FromContext(c).DeleteWebhookTarget(hostname, user)
+}
+
+// CreateOutboxMessage queues a notification for delivery.
+func CreateOutboxMessage(c context.Context, msg *model.OutboxMessage) error {
+	return FromContext(c).CreateOutboxMessage(msg)
+}
+
+// GetOutboxMessage gets a queued notification by id.
+func GetOutboxMessage(c context.Context, id int64) (*model.OutboxMessage, error) {
+	return FromContext(c).GetOutboxMessage(id)
+}
+
+// GetDueOutboxMessages gets up to limit pending notifications
+// whose next attempt is at or before now.
+func GetDueOutboxMessages(c context.Context, now int64, limit int) ([]*model.OutboxMessage, error) {
+	return FromContext(c).GetDueOutboxMessages(now, limit)
+}
+
+// ClaimOutboxMessage leases a pending notification by moving its
+// next attempt from due to lease. It returns false when another
+// dispatcher claimed the notification first.
+func ClaimOutboxMessage(c context.Context, id int64, due int64, lease int64) (bool, error) {
+	return FromContext(c).ClaimOutboxMessage(id, due, lease)
+}
+
+// GetOutboxMessagesByState gets the queued notifications in a state.
+func GetOutboxMessagesByState(c context.Context, state string) ([]*model.OutboxMessage, error) {
+	return FromContext(c).GetOutboxMessagesByState(state)
+}
+
+// UpdateOutboxMessage updates a queued notification.
+func UpdateOutboxMessage(c context.Context, msg *model.OutboxMessage) error {
+	return FromContext(c).UpdateOutboxMessage(msg)
+}
+
+// DeleteOutboxMessage removes a notification from the outbox.
+func DeleteOutboxMessage(c context.Context, id int64) error {
+	return FromContext(c).DeleteOutboxMessage(id)
+}
+
+// CreatePendingDeployment stores a deployment that is waiting for approval.
+func CreatePendingDeployment(c context.Context, pending *model.PendingDeployment) error {
+	return FromContext(c).CreatePendingDeployment(pending)
+}
+
+// GetPendingDeployments gets the deployments of a repo that are waiting for approval.
+func GetPendingDeployments(c context.Context, repoID int64) ([]*model.PendingDeployment, error) {
+	return FromContext(c).GetPendingDeployments(repoID)
+}
+
+// DeletePendingDeployment deletes a deployment that is waiting for approval.
+func DeletePendingDeployment(c context.Context, pending *model.PendingDeployment) error {
+	return FromContext(c).DeletePendingDeployment(pending)
+}
+
+// CreateDeploymentApproval stores an approval of the deployments of a pull request.
+func CreateDeploymentApproval(c context.Context, approval *model.DeploymentApproval) error {
+	return FromContext(c).CreateDeploymentApproval(approval)
+}
+
+// GetDeploymentApprovals gets the approvals of the deployments of a pull request to an environment.
+func GetDeploymentApprovals(c context.Context, repoID int64, pr int, env string) ([]*model.DeploymentApproval, error) {
+	return FromContext(c).GetDeploymentApprovals(repoID, pr, env)
+}
+
+// GetSlackLink gets the GitHub user that is linked to a Slack user.
+func GetSlackLink(c context.Context, team string, user string) (*model.SlackLink, error) {
+	return FromContext(c).GetSlackLink(team, user)
+}
+
+// CreateSlackLink links a Slack user to a GitHub user.
+// The previous link of the Slack user is replaced.
+func CreateSlackLink(c context.Context, link *model.SlackLink) error {
+	return FromContext(c).CreateSlackLink(link)
+}
+
+// DeleteSlackLink removes the link of a Slack user.
+func DeleteSlackLink(c context.Context, team string, user string) error {
+	return FromContext(c).DeleteSlackLink(team, user)
+}
+
+// CreateHookJob queues a webhook delivery for processing.
+func CreateHookJob(c context.Context, job *model.HookJob) error {
+	return FromContext(c).CreateHookJob(job)
+}
+
+// GetHookJobDelivery gets a queued webhook by its delivery id.
+func GetHookJobDelivery(c context.Context, delivery string) (*model.HookJob, error) {
+	return FromContext(c).GetHookJobDelivery(delivery)
+}
+
+// GetDueHookJobs gets up to limit pending webhooks
+// that are due for processing.
+func GetDueHookJobs(c context.Context, now int64, limit int) ([]*model.HookJob, error) {
+	return FromContext(c).GetDueHookJobs(now, limit)
+}
+
+// ClaimHookJob leases a pending webhook by moving its next
+// attempt from due to lease. It returns false when another
+// dispatcher claimed the webhook first.
+func ClaimHookJob(c context.Context, id int64, due int64, lease int64) (bool, error) {
+	return FromContext(c).ClaimHookJob(id, due, lease)
+}
+
+// GetHookJobsByState gets the latest queued webhooks in a state.
+func GetHookJobsByState(c context.Context, state string, limit int) ([]*model.HookJob, error) {
+	return FromContext(c).GetHookJobsByState(state, limit)
+}
+
+// UpdateHookJob updates a queued webhook.
+func UpdateHookJob(c context.Context, job *model.HookJob) error {
+	return FromContext(c).UpdateHookJob(job)
+}
+
+// DeleteHookJobsBefore removes the processed webhooks
+// that were last updated before the time.
+func DeleteHookJobsBefore(c context.Context, updated int64) error {
+	return FromContext(c).DeleteHookJobsBefore(updated)
+}
+
+// CreateHookDelivery archives an incoming webhook.
+func CreateHookDelivery(c context.Context, delivery *model.HookDelivery) error {
+	return FromContext(c).CreateHookDelivery(delivery)
+}
+
+// GetHookDelivery gets an archived webhook.
+func GetHookDelivery(c context.Context, id int64) (*model.HookDelivery, error) {
+	return FromContext(c).GetHookDelivery(id)
+}
+
+// GetHookDeliveryGUID gets the latest archived webhook
+// with the delivery id.
+func GetHookDeliveryGUID(c context.Context, guid string) (*model.HookDelivery, error) {
+	return FromContext(c).GetHookDeliveryGUID(guid)
+}
+
+// GetHookDeliveries gets the latest archived webhooks that
+// match the filter.
+func GetHookDeliveries(c context.Context, filter model.HookDeliveryFilter) ([]*model.HookDelivery, error) {
+	return FromContext(c).GetHookDeliveries(filter)
+}
+
+// UpdateHookDelivery updates an archived webhook.
+func UpdateHookDelivery(c context.Context, delivery *model.HookDelivery) error {
+	return FromContext(c).UpdateHookDelivery(delivery)
+}
+
+// DeleteHookDeliveriesBefore removes the archived webhooks
+// that were received before the time.
+func DeleteHookDeliveriesBefore(c context.Context, created int64) error {
+	return FromContext(c).DeleteHookDeliveriesBefore(created)
+}
+
+// AcquireLease leases the key to the owner until expires. The
+// lease is granted if the key is free, expired at now or
+// already leased to the owner.
+func AcquireLease(c context.Context, key, owner string, now, expires int64) (bool, error) {
+	return FromContext(c).AcquireLease(key, owner, now, expires)
+}
+
+// ReleaseLease frees the key if it is leased to the owner.
+func ReleaseLease(c context.Context, key, owner string) error {
+	return FromContext(c).ReleaseLease(key, owner)
+}
@@ -20,10 +20,9 @@ package usage
 
 import (
 	"context"
-	"sync"
-	"time"
 
-	log "github.com/Sirupsen/logrus"
+	"github.com/capitalone/checks-out/metrics"
+	"github.com/capitalone/checks-out/set"
 )
 
 type usageType int
@@ -44,8 +43,8 @@ func GetEventFromContext(c context.Context) string {
 	return e
 }
 
-var lock = sync.Mutex{}
-
+// Usage summarizes the counters of the metrics registry
+// since the service started.
 type Usage struct {
 	Users     map[string]int `json:"users"`
 	HookIn    map[string]int `json:"hook_in"`
@@ -53,86 +52,42 @@ type Usage struct {
 	RemoteReq map[string]int `json:"remote"`
 }
 
-var data Usage
-
-func init() {
-	data = createUsage()
-}
-
-func createUsage() Usage {
-	return Usage{
-		Users:     make(map[string]int),
-		HookIn:    make(map[string]int),
-		HookOut:   make(map[string]int),
-		RemoteReq: make(map[string]int),
-	}
-}
+// webHookEvents are the GitHub events that are counted by name.
+// The event header is sent by the client, so other values are
+// counted as "other" to bound the number of metric labels.
+var webHookEvents = set.New("ping", "pull_request_review", "issue_comment",
+	"status", "pull_request", "repository", "deployment_status",
+	"membership", "team", "organization", "push")
 
 func RecordIncomingWebHook(event string) {
-	lock.Lock()
-	data.HookIn[event]++
-	lock.Unlock()
-}
-
-func RecordApiRequest(user string, event string, req string) {
-	lock.Lock()
-	data.Users[user]++
-	data.HookOut[event]++
-	data.RemoteReq[req]++
-	lock.Unlock()
-}
-
-func copyMap(dst, src map[string]int) {
-	for k, v := range src {
-		dst[k] = v
+	if !webHookEvents.Contains(event) {
+		event = "other"
 	}
+	metrics.HooksReceived.Inc(event)
 }
 
-func GetStats() Usage {
-	stats := createUsage()
-	lock.Lock()
-	copyMap(stats.Users, data.Users)
-	copyMap(stats.HookIn, data.HookIn)
-	copyMap(stats.HookOut, data.HookOut)
-	copyMap(stats.RemoteReq, data.RemoteReq)
-	lock.Unlock()
-	return stats
+// RecordApiRequest records a GitHub API request of the user that
+// was caused by the event. The endpoint and the response status
+// are recorded by the transport of the GitHub client.
+func RecordApiRequest(user string, event string) {
+	metrics.GitHubRequestsByUser.Inc(user)
+	metrics.GitHubRequestsByEvent.Inc(event)
 }
 
-func writeLog() {
-	log.Info("Usage statistics for the past hour")
-	for k, v := range data.Users {
-		log.Infof("User %s : %d api requests", k, v)
-	}
-	for k, v := range data.HookIn {
-		log.Infof("Hook %s : %d incoming requests", k, v)
-	}
-	for k, v := range data.HookOut {
-		log.Infof("Hook %s : %d outgoing requests", k, v)
+// sumByLabel adds up the samples by their first label.
+func sumByLabel(samples []metrics.Sample) map[string]int {
+	out := make(map[string]int)
+	for _, s := range samples {
+		out[s.Labels[0]] += int(s.Value)
 	}
-	for k, v := range data.RemoteReq {
-		log.Infof("Remote request %s : %d requests", k, v)
-	}
-}
-
-func resetStats() {
-	data = createUsage()
+	return out
 }
 
-func usageTask() {
-	wait := 60 - time.Now().Minute()
-	timer := time.NewTimer(time.Duration(wait) * time.Minute)
-	<-timer.C
-	ticker := time.NewTicker(time.Hour)
-	for {
-		lock.Lock()
-		writeLog()
-		resetStats()
-		lock.Unlock()
-		<-ticker.C
+func GetStats() Usage {
+	return Usage{
+		Users:     sumByLabel(metrics.GitHubRequestsByUser.Values()),
+		HookIn:    sumByLabel(metrics.HooksReceived.Values()),
+		HookOut:   sumByLabel(metrics.GitHubRequestsByEvent.Values()),
+		RemoteReq: sumByLabel(metrics.GitHubRequests.Values()),
 	}
 }
-
-func Start() {
-	go usageTask()
-}
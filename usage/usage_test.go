@@ -35,4 +35,15 @@ func TestContextUnique(t *testing.T) {
 	if v2 != "Hello" {
 		t.Errorf("Expected Hello, got %v",v2)
 	}
-}
\ No newline at end of file
+}
+func TestRecordIncomingWebHook(t *testing.T) {
+	RecordIncomingWebHook("push")
+	RecordIncomingWebHook("made-up-event")
+	in := GetStats().HookIn
+	if in["push"] == 0 || in["other"] == 0 {
+		t.Errorf("Expected known and other events to be counted, got %v", in)
+	}
+	if _, ok := in["made-up-event"]; ok {
+		t.Error("Unknown events should not be counted by name")
+	}
+}
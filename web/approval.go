@@ -24,9 +24,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/logstats"
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/remote"
 	"github.com/capitalone/checks-out/set"
@@ -64,6 +66,11 @@ type ApprovalInfo struct {
 	AuditApproved  bool
 	Approvers      set.Set
 	Disapprovers   set.Set
+	PullRequest    *model.PullRequest
+	// people who can still satisfy the approval policy
+	Needed []*model.Person
+	// the author of the pull request with the maintainer details
+	PullRequestAuthor *model.Person
 	CurCommentInfo
 }
 
@@ -73,16 +80,34 @@ type PullRequestFeedback struct {
 	Disapproval []model.Feedback
 }
 
+// approve evaluates the latest state of the pull request. When the
+// status is set the evaluation is serialized with the other events
+// of the pull request. Bursts of events are coalesced unless the
+// event is an approval, whose author affirmation and notifications
+// depend on the comment of the event.
 func approve(c context.Context, params HookParams, id int, setStatus bool) (*ApprovalInfo, error) {
 	user := params.User
 	repo := params.Repo
 
-	pullRequest, err := remote.GetPullRequest(c, user, repo, id)
-	if err != nil {
-		return nil, err
+	eval := func(c context.Context) (*ApprovalInfo, error) {
+		pullRequest, err := remote.GetPullRequest(c, user, repo, id)
+		if err != nil {
+			return nil, err
+		}
+		return approvePullRequest(c, params, id, &pullRequest, setStatus)
 	}
-
-	return approvePullRequest(c, params, id, &pullRequest, setStatus)
+	if !setStatus {
+		return eval(c)
+	}
+	if params.Approval != nil {
+		lc, release, err := lockPullRequest(c, repo, id)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return eval(lc)
+	}
+	return evaluatePullRequest(c, repo, id, eval)
 }
 
 const authorAffirmMsg = "Someone besides the committers and the PR author should approve the pull request. " +
@@ -92,6 +117,7 @@ const authorAffirmMsg = "Someone besides the committers and the PR author should
 var affirmMsgActions = set.New("opened", "reopened", "synchronized")
 
 func approvePullRequest(c context.Context, params HookParams, id int, pullRequest *model.PullRequest, setStatus bool) (*ApprovalInfo, error) {
+	defer metrics.EvaluationSeconds.ObserveSince(time.Now())
 	user := params.User
 	repo := params.Repo
 	config := params.Config
@@ -108,6 +134,7 @@ func approvePullRequest(c context.Context, params HookParams, id int, pullReques
 	if err != nil {
 		return nil, err
 	}
+	approval.PullRequest = pullRequest
 
 	if setStatus {
 		if !approval.AuthorAffirmed {
@@ -138,18 +165,19 @@ func approvePullRequest(c context.Context, params HookParams, id int, pullReques
 func getFeedbackRanges(c context.Context,
 	user *model.User,
 	request *model.ApprovalRequest,
-	policy *model.ApprovalPolicy) (PullRequestFeedback, error) {
+	policy *model.ApprovalPolicy,
+	data *model.PullRequestData) (PullRequestFeedback, error) {
 	var err error
 	config := request.Config
 	fb := PullRequestFeedback{}
-	fb.All, err = getFeedback(c, user, request, policy, model.All, config.Commit.IgnoreUIMerge)
+	fb.All, err = getFeedback(c, user, request, policy, data, model.All, config.Commit.IgnoreUIMerge)
 	if err != nil {
 		return fb, err
 	}
 	if config.Commit.Range == model.All {
 		fb.Approval = fb.All
 	} else {
-		fb.Approval, err = getFeedback(c, user, request, policy, config.Commit.Range, config.Commit.IgnoreUIMerge)
+		fb.Approval, err = getFeedback(c, user, request, policy, data, config.Commit.Range, config.Commit.IgnoreUIMerge)
 	}
 	if err != nil {
 		return fb, err
@@ -157,7 +185,7 @@ func getFeedbackRanges(c context.Context,
 	if config.Commit.Range == config.Commit.AntiRange {
 		fb.Disapproval = fb.Approval
 	} else {
-		fb.Disapproval, err = getFeedback(c, user, request, policy, config.Commit.AntiRange, config.Commit.IgnoreUIMerge)
+		fb.Disapproval, err = getFeedback(c, user, request, policy, data, config.Commit.AntiRange, config.Commit.IgnoreUIMerge)
 	}
 	return fb, err
 }
@@ -204,18 +232,24 @@ func buildApprovers(c context.Context,
 	request *model.ApprovalRequest) (*ApprovalInfo, error) {
 	repo := request.Repository
 	pr := request.PullRequest
-	files, err := getPullRequestFiles(c, user, repo, pr.Number)
-	if err != nil {
-		return nil, err
-	}
-	commits, err := getPullRequestCommits(c, user, repo, pr.Number)
-	if err != nil {
-		return nil, err
+	data := loadPullRequestData(c, user, repo, pr.Number, request.Config.Commit.IgnoreUIMerge)
+	if data != nil {
+		request.Files = data.Files
+		request.Commits = data.Commits
+	} else {
+		files, err := getPullRequestFiles(c, user, repo, pr.Number)
+		if err != nil {
+			return nil, err
+		}
+		commits, err := getPullRequestCommits(c, user, repo, pr.Number)
+		if err != nil {
+			return nil, err
+		}
+		request.Files = files
+		request.Commits = commits
 	}
-	request.Files = files
-	request.Commits = commits
 	policy := model.FindApprovalPolicy(request)
-	feedback, err := getFeedbackRanges(c, user, request, policy)
+	feedback, err := getFeedbackRanges(c, user, request, policy, data)
 	if err != nil {
 		return nil, err
 	}
@@ -337,6 +371,15 @@ func calculateApprovalInfo(request *model.ApprovalRequest, policy *model.Approva
 	}
 	approved = approved && audit && authAffirm
 
+	var needed []*model.Person
+	if !approved {
+		people, err := model.NeededApprovers(request, policy)
+		if err != nil {
+			return nil, err
+		}
+		needed = getPeople(request, people)
+	}
+
 	ai := ApprovalInfo{
 		Policy:         policy,
 		Approved:       approved,
@@ -346,10 +389,12 @@ func calculateApprovalInfo(request *model.ApprovalRequest, policy *model.Approva
 		AuthorAffirmed: authAffirm,
 		Approvers:      approvers,
 		Disapprovers:   disapprovers,
+		Needed:         needed,
 		CurCommentInfo: CurCommentInfo{
 			Author: "",
 			Status: CurCommentNoChange,
 		},
+		PullRequestAuthor: getPeople(request, set.New(request.PullRequest.Author.String()))[0],
 	}
 
 	if !validAudit {
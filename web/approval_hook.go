@@ -38,6 +38,7 @@ func doApprovalHook(c context.Context, hook *ApprovalHook, isApp IsApprover) (*A
 		return nil, err
 	}
 	mw := handleApprovalNotification(hook, &approvalInfo.CurCommentInfo)
+	setMessageDetails(mw, approvalInfo.PullRequest, approvalInfo)
 	notifier.SendMessage(c, params.Config, *mw)
 	approvalOutput := ApprovalOutput{
 		Policy:       approvalInfo.Policy,
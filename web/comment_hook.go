@@ -28,6 +28,9 @@ import (
 )
 
 func (hook *CommentHook) Process(c context.Context) (interface{}, error) {
+	if hook.Closed {
+		return doDeploymentApprovalHook(c, hook)
+	}
 	approvalOutput, e1 := doCommentHook(c, hook)
 	if e1 != nil {
 		e2 := sendErrorStatus(c, &hook.ApprovalHook, e1)
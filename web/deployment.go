@@ -0,0 +1,254 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/store"
+
+	multierror "github.com/mspiegel/go-multierror"
+)
+
+type DeploymentResponse struct {
+	Deployments []string `json:"deployments,omitempty"`
+	Err         string   `json:"error,omitempty"`
+}
+
+func (hook *DeploymentStatusHook) Process(c context.Context) (interface{}, error) {
+
+	if hook.Deployment.State != "success" {
+		return nil, nil
+	}
+
+	params, err := GetHookParameters(c, hook.HookCommon, hook.Repo.Slug)
+	if err != nil {
+		return nil, err
+	}
+	config := params.Config
+	if !config.Deployment.Enable {
+		return nil, nil
+	}
+	deployments := config.Deployment.DeploymentMap.Promotions(hook.Deployment)
+	if len(deployments) == 0 {
+		return nil, nil
+	}
+	mw := &notifier.MessageWrapper{
+		MessageHeader: notifier.MessageHeader{
+			PrNumber: hook.Deployment.PullRequest,
+			Slug:     hook.Repo.Slug,
+		},
+	}
+	result := DeploymentResponse{}
+	for _, di := range deployments {
+		result.Deployments = append(result.Deployments, deploymentDescription(di))
+	}
+	err = doDeployment(c, params, deployments, mw)
+	if err != nil {
+		log.Warnf("Unable to promote deployment: %s", err)
+		result.Err = err.Error()
+	}
+	sendMessage(c, config, mw)
+	return result, nil
+}
+
+// doDeploymentApprovalHook releases the pending deployments
+// of a merged pull request when a comment approves them.
+func doDeploymentApprovalHook(c context.Context, hook *CommentHook) (interface{}, error) {
+	if strings.HasPrefix(hook.Comment, model.CommentPrefix) {
+		return nil, nil
+	}
+	params, err := GetHookParameters(c, hook.HookCommon, hook.Repo.Slug)
+	if err != nil {
+		return nil, err
+	}
+	deploy := &params.Config.Deployment
+	if !deploy.Enable || len(deploy.Approvals) == 0 {
+		return nil, nil
+	}
+	if len(deploy.ApprovalEnvironment(hook.Comment)) == 0 {
+		return nil, nil
+	}
+	mw := &notifier.MessageWrapper{
+		MessageHeader: notifier.MessageHeader{
+			PrName:   hook.Issue.Title,
+			PrNumber: hook.Issue.Number,
+			Slug:     hook.Repo.Slug,
+		},
+	}
+	result := releaseDeployments(c, params, hook.Issue.Number, mw)
+	sendMessage(c, params.Config, mw)
+	return result, nil
+}
+
+// doDeployment schedules each deployment and reports
+// the outcome of each deployment as a notification.
+// Deployments to an environment that requires approval
+// are held until the approval match succeeds.
+func doDeployment(c context.Context, params HookParams,
+	deployments []model.DeploymentInfo, mw *notifier.MessageWrapper) error {
+	var errs error
+	approved := map[string]bool{}
+	for _, di := range deployments {
+		desc := deploymentDescription(di)
+		if params.Config.Deployment.RequiresApproval(di.Environment) {
+			ok, checked := approved[di.Environment]
+			var err error
+			if !checked {
+				ok, err = isDeploymentApproved(c, params, di.PullRequest, di.Environment)
+				approved[di.Environment] = ok
+			}
+			if err == nil && !ok {
+				err = store.CreatePendingDeployment(c, model.NewPendingDeployment(params.Repo.ID, di, time.Now().Unix()))
+				if err == nil {
+					mw.Messages = append(mw.Messages, notifier.MessageInfo{
+						Message: fmt.Sprintf("Deployment %s is waiting for approval", desc),
+						Type:    model.CommentDeployment,
+					})
+					continue
+				}
+			}
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				mw.Messages = append(mw.Messages, notifier.MessageInfo{
+					Message: fmt.Sprintf("Unable to schedule deployment %s: %s", desc, err),
+					Type:    model.CommentDeployment,
+				})
+				continue
+			}
+		}
+		errs = multierror.Append(errs, scheduleDeployment(c, params, di, mw))
+	}
+	return errs
+}
+
+// releaseDeployments schedules the pending deployments of
+// the pull request that have been approved.
+func releaseDeployments(c context.Context, params HookParams, number int, mw *notifier.MessageWrapper) DeploymentResponse {
+	result := DeploymentResponse{}
+	pending, err := store.GetPendingDeployments(c, params.Repo.ID)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	var errs error
+	approved := map[string]bool{}
+	for _, p := range pending {
+		if p.PullRequest != number {
+			continue
+		}
+		ok, checked := approved[p.Environment]
+		if !checked {
+			ok, err = isDeploymentApproved(c, params, number, p.Environment)
+			approved[p.Environment] = ok
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				mw.Messages = append(mw.Messages, notifier.MessageInfo{
+					Message: fmt.Sprintf("Unable to evaluate approval of deployment to environment %s: %s", p.Environment, err),
+					Type:    model.CommentDeployment,
+				})
+			}
+		}
+		if !ok {
+			continue
+		}
+		di := p.DeploymentInfo()
+		result.Deployments = append(result.Deployments, deploymentDescription(di))
+		err = scheduleDeployment(c, params, di, mw)
+		if err != nil {
+			// the deployment remains pending and is retried on the next approval
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		err = store.DeletePendingDeployment(c, p)
+		if err != nil {
+			log.Warnf("Unable to delete pending deployment %d: %s", p.ID, err)
+		}
+	}
+	if errs != nil {
+		result.Err = errs.Error()
+	}
+	return result
+}
+
+// isDeploymentApproved applies the approval match of the environment
+// to the comments of the pull request and the approvals submitted
+// through the API.
+func isDeploymentApproved(c context.Context, params HookParams, number int, env string) (bool, error) {
+	if number <= 0 {
+		return false, fmt.Errorf("Deployment to environment %s requires approval "+
+			"but the originating pull request is unknown", env)
+	}
+	pr, err := remote.GetPullRequest(c, params.User, params.Repo, number)
+	if err != nil {
+		return false, err
+	}
+	comments, err := remote.GetAllComments(c, params.User, params.Repo, number)
+	if err != nil {
+		return false, err
+	}
+	approvals, err := store.GetDeploymentApprovals(c, params.Repo.ID, number, model.NormalizeEnvironment(env))
+	if err != nil {
+		return false, err
+	}
+	deploy := &params.Config.Deployment
+	feedback := append(deploy.DeploymentFeedback(comments, env), model.ApprovalFeedback(approvals)...)
+	req := &model.ApprovalRequest{
+		Config:      params.Config,
+		Maintainer:  params.Snapshot,
+		PullRequest: &pr,
+		Repository:  params.Repo,
+	}
+	return deploy.ApproveDeployment(req, env, feedback, func(model.Feedback, model.ApprovalOp) {})
+}
+
+func scheduleDeployment(c context.Context, params HookParams, di model.DeploymentInfo, mw *notifier.MessageWrapper) error {
+	desc := deploymentDescription(di)
+	err := remote.ScheduleDeployment(c, params.User, params.Repo, di)
+	if err != nil {
+		mw.Messages = append(mw.Messages, notifier.MessageInfo{
+			Message: fmt.Sprintf("Unable to schedule deployment %s: %s", desc, err),
+			Type:    model.CommentDeployment,
+		})
+		return err
+	}
+	mw.Messages = append(mw.Messages, notifier.MessageInfo{
+		Message: fmt.Sprintf("Deployment %s has been triggered", desc),
+		Type:    model.CommentDeployment,
+	})
+	return nil
+}
+
+func deploymentDescription(di model.DeploymentInfo) string {
+	desc := fmt.Sprintf("of %s", di.Ref)
+	if len(di.Task) > 0 {
+		desc += fmt.Sprintf(" with task %s", di.Task)
+	}
+	if len(di.Environment) > 0 {
+		desc += fmt.Sprintf(" to environment %s", di.Environment)
+	}
+	return desc
+}
@@ -0,0 +1,186 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/store"
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type deployR struct {
+	remote.Remote
+	scheduled []model.DeploymentInfo
+	comments  []*model.Comment
+}
+
+func (m *deployR) GetPullRequest(c context.Context, u *model.User, r *model.Repo, number int) (model.PullRequest, error) {
+	return model.PullRequest{Issue: model.Issue{Number: number, Author: lowercase.Create("test_guy")}}, nil
+}
+
+func (m *deployR) GetAllComments(c context.Context, u *model.User, r *model.Repo, number int) ([]*model.Comment, error) {
+	return m.comments, nil
+}
+
+type deployS struct {
+	store.Store
+	pending   []*model.PendingDeployment
+	approvals []*model.DeploymentApproval
+}
+
+func (s *deployS) CreatePendingDeployment(p *model.PendingDeployment) error {
+	p.ID = int64(len(s.pending) + 1)
+	s.pending = append(s.pending, p)
+	return nil
+}
+
+func (s *deployS) GetPendingDeployments(repoID int64) ([]*model.PendingDeployment, error) {
+	return s.pending, nil
+}
+
+func (s *deployS) DeletePendingDeployment(p *model.PendingDeployment) error {
+	var pending []*model.PendingDeployment
+	for _, v := range s.pending {
+		if v.ID != p.ID {
+			pending = append(pending, v)
+		}
+	}
+	s.pending = pending
+	return nil
+}
+
+func (s *deployS) GetDeploymentApprovals(repoID int64, pr int, env string) ([]*model.DeploymentApproval, error) {
+	return s.approvals, nil
+}
+
+func (m *deployR) ScheduleDeployment(c context.Context, u *model.User, r *model.Repo, d model.DeploymentInfo) error {
+	if d.Environment == "broken" {
+		return errors.New("environment is broken")
+	}
+	m.scheduled = append(m.scheduled, d)
+	return nil
+}
+
+func TestDoDeployment(t *testing.T) {
+	c := &gin.Context{}
+	mock := &deployR{}
+	remote.ToContext(c, mock)
+	mw := &notifier.MessageWrapper{}
+	deployments := []model.DeploymentInfo{
+		{Ref: "master", Task: "deploy", Environment: "staging"},
+		{Ref: "1.0.0", Environment: "broken"},
+	}
+	params := HookParams{Config: model.NonEmptyConfig(), Repo: &model.Repo{}}
+	err := doDeployment(c, params, deployments, mw)
+	assert.NotNil(t, err)
+	assert.Equal(t, deployments[:1], mock.scheduled)
+	assert.Equal(t, []notifier.MessageInfo{
+		{
+			Message: "Deployment of master with task deploy to environment staging has been triggered",
+			Type:    model.CommentDeployment,
+		},
+		{
+			Message: "Unable to schedule deployment of 1.0.0 to environment broken: environment is broken",
+			Type:    model.CommentDeployment,
+		},
+	}, mw.Messages)
+}
+
+func TestDeploymentApproval(t *testing.T) {
+	c := &gin.Context{}
+	mock := &deployR{}
+	db := &deployS{}
+	remote.ToContext(c, mock)
+	store.ToContext(c, db)
+	config := model.NonEmptyConfig()
+	universe := model.UniverseMatch{}
+	universe.Approvals = 1
+	config.Deployment.Approvals = map[string]model.MatcherHolder{
+		"production": {Matcher: &universe},
+	}
+	params := HookParams{
+		Config:   config,
+		Snapshot: &model.MaintainerSnapshot{},
+		Repo:     &model.Repo{ID: 1, Slug: "test_guy/test_repo"},
+	}
+	deployments := []model.DeploymentInfo{
+		{Ref: "master", Environment: "staging", PullRequest: 7},
+		{Ref: "master", Environment: "production", PullRequest: 7},
+	}
+	mw := &notifier.MessageWrapper{}
+	err := doDeployment(c, params, deployments, mw)
+	assert.Nil(t, err)
+	assert.Equal(t, deployments[:1], mock.scheduled)
+	assert.Equal(t, 1, len(db.pending))
+	assert.Equal(t, "production", db.pending[0].Environment)
+	assert.Equal(t, "Deployment of master to environment production is waiting for approval", mw.Messages[1].Message)
+
+	// comments that approve another environment are ignored
+	mock.comments = []*model.Comment{
+		{Author: lowercase.Create("test_guy2"), Body: "approve deploy to staging"},
+	}
+	result := releaseDeployments(c, params, 7, mw)
+	assert.Equal(t, "", result.Err)
+	assert.Equal(t, 0, len(result.Deployments))
+	assert.Equal(t, 1, len(db.pending))
+
+	mock.comments = append(mock.comments, &model.Comment{
+		Author: lowercase.Create("test_guy2"), Body: "Approve deployment to PRODUCTION",
+	})
+	result = releaseDeployments(c, params, 7, mw)
+	assert.Equal(t, "", result.Err)
+	assert.Equal(t, []string{"of master to environment production"}, result.Deployments)
+	assert.Equal(t, deployments, mock.scheduled)
+	assert.Equal(t, 0, len(db.pending))
+}
+
+func TestDeploymentApprovalAPI(t *testing.T) {
+	c := &gin.Context{}
+	mock := &deployR{}
+	db := &deployS{
+		approvals: []*model.DeploymentApproval{{Login: "test_guy2"}},
+	}
+	remote.ToContext(c, mock)
+	store.ToContext(c, db)
+	config := model.NonEmptyConfig()
+	universe := model.UniverseMatch{}
+	universe.Approvals = 1
+	config.Deployment.Approvals = map[string]model.MatcherHolder{
+		"production": {Matcher: &universe},
+	}
+	params := HookParams{
+		Config:   config,
+		Snapshot: &model.MaintainerSnapshot{},
+		Repo:     &model.Repo{ID: 1},
+	}
+	approved, err := isDeploymentApproved(c, params, 7, "production")
+	assert.Nil(t, err)
+	assert.True(t, approved)
+	_, err = isDeploymentApproved(c, params, 0, "production")
+	assert.NotNil(t, err)
+}
@@ -19,8 +19,20 @@ See the License for the specific language governing permissions and limitations
 package web
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"path"
 	"strconv"
+	"time"
+
+	"github.com/capitalone/checks-out/api"
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/router/middleware/session"
+	"github.com/capitalone/checks-out/store"
 
 	"github.com/gin-gonic/gin"
 )
@@ -57,3 +69,235 @@ func ApprovalStatus(c *gin.Context) {
 		})
 	}
 }
+
+// PendingDeployments generates a response with the deployments
+// of a repository that are waiting for approval
+func PendingDeployments(c *gin.Context) {
+	var (
+		owner = c.Param("owner")
+		name  = c.Param("repo")
+	)
+	repo, err := store.GetRepoOwnerName(c, owner, name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	pending, err := store.GetPendingDeployments(c, repo.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.IndentedJSON(200, pending)
+}
+
+// ApproveDeployment records the approval of the current user
+// for the deployments of a pull request to an environment and
+// schedules the pending deployments that have been approved
+func ApproveDeployment(c *gin.Context) {
+	var (
+		owner = c.Param("owner")
+		name  = c.Param("repo")
+		id    = c.Param("id")
+		env   = model.NormalizeEnvironment(c.Param("env"))
+		user  = session.User(c)
+	)
+
+	pr, err := strconv.Atoi(id)
+	if err != nil {
+		c.String(400, "Unable to convert pull request id %s to number", id)
+		return
+	}
+
+	params, err := GetHookParameters(c, HookCommon{}, path.Join(owner, name))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !params.Config.Deployment.Enable || !params.Config.Deployment.RequiresApproval(env) {
+		c.String(400, "Deployments to environment %s do not require approval", env)
+		return
+	}
+	approvals, err := store.GetDeploymentApprovals(c, params.Repo.ID, pr, env)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !hasDeploymentApproval(approvals, user.Login) {
+		err = store.CreateDeploymentApproval(c, &model.DeploymentApproval{
+			RepoID:      params.Repo.ID,
+			PullRequest: pr,
+			Environment: env,
+			Login:       user.Login,
+			Created:     time.Now().Unix(),
+		})
+		if err != nil {
+			c.Error(exterror.Create(http.StatusInternalServerError, err))
+			return
+		}
+	}
+	mw := &notifier.MessageWrapper{
+		MessageHeader: notifier.MessageHeader{
+			PrNumber: pr,
+			Slug:     params.Repo.Slug,
+		},
+	}
+	result := releaseDeployments(c, params, pr, mw)
+	sendMessage(c, params.Config, mw)
+	c.IndentedJSON(200, result)
+}
+
+// RetryHookJob returns a failed webhook to the queue.
+func RetryHookJob(c *gin.Context) {
+	job, ok := api.HookJob(c)
+	if !ok {
+		return
+	}
+	err := RequeueHookJob(c, job)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Retrying webhook delivery %s", job.Delivery)))
+		return
+	}
+	c.IndentedJSON(200, job)
+}
+
+// ReplayHookDelivery processes an archived webhook again and
+// returns the output of the processing. The replay is not queued.
+func ReplayHookDelivery(c *gin.Context) {
+	delivery, ok := api.HookDelivery(c)
+	if !ok {
+		return
+	}
+	if delivery.Payload == "" {
+		err := fmt.Errorf("Archived webhook %d has no payload", delivery.ID)
+		c.Error(exterror.Create(http.StatusBadRequest, err))
+		return
+	}
+	var ctx context.Context = c
+	if delivery.BaseURL != "" {
+		ctx = context.WithValue(ctx, "BASE_URL", delivery.BaseURL)
+	}
+	hook, c2, err := createHook(ctx, delivery.Event, delivery.BaseURL, []byte(delivery.Payload))
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Replaying archived webhook %d", delivery.ID)))
+		return
+	}
+	var output interface{}
+	if hook != nil {
+		output, err = processHook(c2, delivery.Event, hook)
+		if err != nil {
+			c.Error(exterror.Append(err, fmt.Sprintf("Replaying archived webhook %d", delivery.ID)))
+			return
+		}
+	}
+	c.IndentedJSON(200, gin.H{"id": delivery.ID, "output": output})
+}
+
+// ReevaluateReport is the response of the re-evaluation endpoints.
+type ReevaluateReport struct {
+	DryRun       bool              `json:"dry_run"`
+	Repositories int               `json:"repositories"`
+	PullRequests int               `json:"pull_requests"`
+	Summary      map[string]int    `json:"summary"`
+	Results      []ReconcileResult `json:"results"`
+}
+
+func reevaluateOptions(c *gin.Context) (reconcileOptions, bool) {
+	opts := reconcileOptions{Force: true}
+	if value := c.Query("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			c.String(400, "Unable to parse dry_run %s", value)
+			return opts, false
+		}
+		opts.DryRun = dryRun
+	}
+	return opts, true
+}
+
+func reevaluateReport(opts reconcileOptions, repos int, results []ReconcileResult) *ReevaluateReport {
+	report := &ReevaluateReport{
+		DryRun:       opts.DryRun,
+		Repositories: repos,
+		Summary:      summarizeReconcile(results),
+		Results:      results,
+	}
+	for _, r := range results {
+		if r.Number > 0 {
+			report.PullRequests++
+		}
+	}
+	return report
+}
+
+// ReevaluatePullRequest re-runs the processing of a pull request:
+// the status is set, the notification of the latest feedback is
+// sent and the pull request is merged if it is approved.
+func ReevaluatePullRequest(c *gin.Context) {
+	var (
+		owner = c.Param("owner")
+		name  = c.Param("repo")
+		id    = c.Param("id")
+	)
+	pr, err := strconv.Atoi(id)
+	if err != nil {
+		c.String(400, "Unable to convert pull request id %s to number", id)
+		return
+	}
+	opts, ok := reevaluateOptions(c)
+	if !ok {
+		return
+	}
+	params, err := GetHookParameters(c, HookCommon{Event: "reevaluate"}, path.Join(owner, name))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	result := reconcilePullRequest(c, params, pr, opts)
+	c.IndentedJSON(200, reevaluateReport(opts, 1, []ReconcileResult{result}))
+}
+
+// ReevaluateRepo re-runs the processing of the open pull
+// requests of a repository.
+func ReevaluateRepo(c *gin.Context) {
+	var (
+		owner = c.Param("owner")
+		name  = c.Param("repo")
+	)
+	opts, ok := reevaluateOptions(c)
+	if !ok {
+		return
+	}
+	repo, err := store.GetRepoOwnerName(c, owner, name)
+	if err != nil {
+		c.Error(exterror.Append(err, fmt.Sprintf("Getting repository %s/%s", owner, name)))
+		return
+	}
+	results := reconcileRepo(c, repo, opts)
+	c.IndentedJSON(200, reevaluateReport(opts, 1, results))
+}
+
+// ReevaluateOrg re-runs the processing of the open pull
+// requests of the enabled repositories of an owner.
+func ReevaluateOrg(c *gin.Context) {
+	owner := c.Param("owner")
+	opts, ok := reevaluateOptions(c)
+	if !ok {
+		return
+	}
+	repos, err := store.GetReposForOrg(c, owner)
+	if err != nil {
+		c.Error(exterror.Create(http.StatusInternalServerError, err))
+		return
+	}
+	results := reconcileRepoList(c, repos, envvars.Env.Reconcile.Workers, opts)
+	c.IndentedJSON(200, reevaluateReport(opts, len(repos), results))
+}
+
+func hasDeploymentApproval(approvals []*model.DeploymentApproval, login string) bool {
+	for _, a := range approvals {
+		if a.Login == login {
+			return true
+		}
+	}
+	return false
+}
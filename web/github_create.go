@@ -23,12 +23,11 @@ import (
 	"context"
 	"encoding/json"
 	"io"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/capitalone/checks-out/exterror"
 	"github.com/capitalone/checks-out/model"
-	"github.com/capitalone/checks-out/shared/httputil"
+	"github.com/capitalone/checks-out/set"
 	"github.com/capitalone/checks-out/strings/lowercase"
 	"github.com/capitalone/checks-out/usage"
 
@@ -39,21 +38,10 @@ import (
 // TODO: move this into its own package when
 // we support backends other than GitHub
 
-func createHook(c context.Context, r *http.Request) (Hook, context.Context, error) {
-
-	// For server requests the Request Body is always non-nil
-	// but will return EOF immediately when no body is present.
-	// The Server will close the request body. The ServeHTTP
-	// Handler does not need to.
-
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return nil, c, err
-	}
-
-	event := r.Header.Get("X-Github-Event")
-	usage.RecordIncomingWebHook(event)
-
+// createHook parses the body of a GitHub webhook. A nil hook is
+// returned for the events and actions that are not processed.
+func createHook(c context.Context, event string, baseURL string, body []byte) (Hook, context.Context, error) {
+	var err error
 	var hook Hook
 	switch event {
 	case "pull_request_review":
@@ -65,7 +53,17 @@ func createHook(c context.Context, r *http.Request) (Hook, context.Context, erro
 	case "pull_request":
 		hook, err = createPRHook(body)
 	case "repository":
-		hook, err = createRepoHook(r, body)
+		hook, err = createRepoHook(baseURL, body)
+	case "deployment_status":
+		hook, err = createDeploymentStatusHook(body)
+	case "membership":
+		hook, err = createMembershipHook(body)
+	case "team":
+		hook, err = createTeamHook(body)
+	case "organization":
+		hook, err = createOrganizationHook(body)
+	case "push":
+		hook, err = createPushHook(body)
 	}
 	if hook != nil {
 		hook.SetEvent(event)
@@ -136,10 +134,10 @@ func createCommentHook(body []byte) (Hook, error) {
 	log.Infof("repository %s pr %d issue_comment state %s",
 		data.Repo.GetFullName(), data.Issue.GetNumber(),
 		data.Issue.GetState())
-	// don't process comments on closed pull requests
-	if data.Issue.GetState() == "closed" {
-		log.Debugf("PR %s is closed -- not processing comments for it any more", *(data.Issue.Title))
-		return nil, nil
+	// comments on closed pull requests are only used for deployment approvals
+	closed := data.Issue.GetState() == "closed"
+	if closed {
+		log.Debugf("PR %s is closed -- only processing deployment approvals", data.Issue.GetTitle())
 	}
 
 	hook := &CommentHook{
@@ -156,6 +154,7 @@ func createCommentHook(body []byte) (Hook, error) {
 			},
 		},
 		Comment: data.Comment.GetBody(),
+		Closed:  closed,
 	}
 
 	return hook, nil
@@ -192,6 +191,39 @@ func createStatusHook(body []byte) (Hook, error) {
 	return hook, nil
 }
 
+func createDeploymentStatusHook(body []byte) (Hook, error) {
+
+	data := github.DeploymentStatusEvent{}
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&data)
+	if err != nil {
+		err = createError("Getting deployment status hook", body, err)
+		return nil, err
+	}
+
+	log.Infof("repository %s deployment %d environment %s state %s",
+		data.Repo.GetFullName(), data.Deployment.GetID(),
+		data.Deployment.GetEnvironment(),
+		data.DeploymentStatus.GetState())
+
+	hook := &DeploymentStatusHook{
+		Repo: &model.Repo{
+			Owner: data.Repo.Owner.GetLogin(),
+			Name:  data.Repo.GetName(),
+			Slug:  data.Repo.GetFullName(),
+		},
+		Deployment: model.DeploymentEvent{
+			SHA:         data.Deployment.GetSHA(),
+			Ref:         data.Deployment.GetRef(),
+			Task:        data.Deployment.GetTask(),
+			Environment: data.Deployment.GetEnvironment(),
+			State:       data.DeploymentStatus.GetState(),
+			PullRequest: model.ParseDeploymentDescription(data.Deployment.GetDescription()),
+		},
+	}
+
+	return hook, nil
+}
+
 func createPRHook(body []byte) (Hook, error) {
 
 	data := github.PullRequestEvent{}
@@ -254,7 +286,119 @@ func createPRHook(body []byte) (Hook, error) {
 	return hook, nil
 }
 
-func createRepoHook(r *http.Request, body []byte) (Hook, error) {
+func createMembershipHook(body []byte) (Hook, error) {
+
+	data := github.MembershipEvent{}
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&data)
+	if err != nil {
+		err = createError("Getting membership hook", body, err)
+		return nil, err
+	}
+
+	log.Infof("organization %s team %s member %s %s",
+		data.Org.GetLogin(), data.Team.GetName(),
+		data.Member.GetLogin(), data.GetAction())
+
+	hook := &MembershipHook{
+		HookCommon: HookCommon{
+			Action: data.GetAction(),
+		},
+		Owner: data.Org.GetLogin(),
+		Login: data.Member.GetLogin(),
+	}
+
+	return hook, nil
+}
+
+func createTeamHook(body []byte) (Hook, error) {
+
+	data := github.TeamEvent{}
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&data)
+	if err != nil {
+		err = createError("Getting team hook", body, err)
+		return nil, err
+	}
+
+	log.Infof("organization %s team %s %s repository %s",
+		data.Org.GetLogin(), data.Team.GetName(),
+		data.GetAction(), data.Repo.GetName())
+
+	hook := &MembershipHook{
+		HookCommon: HookCommon{
+			Action: data.GetAction(),
+		},
+		Owner: data.Org.GetLogin(),
+		Name:  data.Repo.GetName(),
+	}
+
+	return hook, nil
+}
+
+func createOrganizationHook(body []byte) (Hook, error) {
+
+	data := github.OrganizationEvent{}
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&data)
+	if err != nil {
+		err = createError("Getting organization hook", body, err)
+		return nil, err
+	}
+
+	log.Infof("organization %s %s member %s",
+		data.Organization.GetLogin(), data.GetAction(),
+		data.Membership.GetUser().GetLogin())
+
+	hook := &MembershipHook{
+		HookCommon: HookCommon{
+			Action: data.GetAction(),
+		},
+		Owner: data.Organization.GetLogin(),
+		Login: data.Membership.GetUser().GetLogin(),
+	}
+
+	return hook, nil
+}
+
+func createPushHook(body []byte) (Hook, error) {
+
+	data := github.PushEvent{}
+	err := json.NewDecoder(bytes.NewReader(body)).Decode(&data)
+	if err != nil {
+		err = createError("Getting push hook", body, err)
+		return nil, err
+	}
+
+	log.Debugf("repository %s push %s", data.Repo.GetFullName(), data.GetRef())
+	// the configuration is read from the default branch
+	if data.GetRef() != "refs/heads/"+data.Repo.GetDefaultBranch() {
+		return nil, nil
+	}
+	// the configured files are only known once the
+	// configuration is read when the hook is processed
+	files := set.Empty()
+	for _, commit := range data.Commits {
+		for _, list := range [][]string{commit.Added, commit.Removed, commit.Modified} {
+			files.AddAll(set.New(list...))
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	hook := &PushHook{
+		Repo: &model.Repo{
+			Owner: data.Repo.Owner.GetName(),
+			Name:  data.Repo.GetName(),
+			Slug:  data.Repo.GetFullName(),
+		},
+		Files: files.KeysSorted(func(s1, s2 string) bool {
+			return s1 < s2
+		}),
+	}
+
+	return hook, nil
+}
+
+func createRepoHook(baseURL string, body []byte) (Hook, error) {
 
 	data := github.RepositoryEvent{}
 	err := json.NewDecoder(bytes.NewReader(body)).Decode(&data)
@@ -271,7 +415,7 @@ func createRepoHook(r *http.Request, body []byte) (Hook, error) {
 		},
 		Name:    data.Repo.GetName(),
 		Owner:   data.Repo.Owner.GetLogin(),
-		BaseURL: httputil.GetURL(r),
+		BaseURL: baseURL,
 	}
 
 	return hook, nil
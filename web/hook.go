@@ -20,9 +20,13 @@ package web
 
 import (
 	"context"
+	"io/ioutil"
 
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/shared/httputil"
 	"github.com/capitalone/checks-out/strings/lowercase"
+	"github.com/capitalone/checks-out/usage"
 	"github.com/gin-gonic/gin"
 )
 
@@ -49,6 +53,7 @@ type ApprovalHook struct {
 type CommentHook struct {
 	ApprovalHook
 	Comment string
+	Closed  bool
 }
 
 type ReviewHook struct {
@@ -75,6 +80,29 @@ type StatusHook struct {
 	Repo   *model.Repo
 }
 
+type DeploymentStatusHook struct {
+	HookCommon
+	Repo       *model.Repo
+	Deployment model.DeploymentEvent
+}
+
+// MembershipHook is a change to the teams or members of an
+// organization. Name is the repository of a team change.
+type MembershipHook struct {
+	HookCommon
+	Owner string
+	Login string
+	Name  string
+}
+
+// PushHook is a push to the default branch of a repository
+// with the files that the push changed.
+type PushHook struct {
+	HookCommon
+	Repo  *model.Repo
+	Files []string
+}
+
 type HookParams struct {
 	Repo     *model.Repo
 	User     *model.User
@@ -86,14 +114,52 @@ type HookParams struct {
 	Approval IsApprover
 }
 
+// ProcessHook validates a GitHub webhook and queues it for the hook
+// workers. Deliveries that were already received are not queued again.
+// The webhook is processed during the request when the workers are
+// not running or when GitHub does not send a delivery id.
 func ProcessHook(c *gin.Context) {
-	hook, c2, err := createHook(c, c.Request)
+	var (
+		event    = c.Request.Header.Get("X-Github-Event")
+		delivery = c.Request.Header.Get("X-Github-Delivery")
+		baseURL  = httputil.GetURL(c.Request)
+	)
+
+	// For server requests the Request Body is always non-nil
+	// but will return EOF immediately when no body is present.
+	// The Server will close the request body. The ServeHTTP
+	// Handler does not need to.
+
+	body, err := ioutil.ReadAll(c.Request.Body)
 	if err != nil {
 		c.Error(err)
+		return
+	}
+	usage.RecordIncomingWebHook(event)
+	archive := archiveHook(c, delivery, event, baseURL, c.Request.Header, body)
+
+	hook, c2, err := createHook(c, event, baseURL, body)
+	if err != nil {
+		recordHookResult(c, archive, nil, err)
+		c.Error(err)
 	} else if hook == nil {
+		recordHookResult(c, archive, nil, nil)
 		c.String(200, "pong")
+	} else if delivery != "" && hookQueueRunning() {
+		job, created, err := queueHook(c, delivery, event, baseURL, body)
+		if err != nil {
+			recordHookResult(c, archive, nil, err)
+			c.Error(err)
+		} else if created {
+			c.IndentedJSON(202, job)
+		} else {
+			// the result of a duplicate is the result of the queued webhook
+			recordHookResult(c, archive, job, nil)
+			c.IndentedJSON(200, job)
+		}
 	} else {
-		output, err := hook.Process(c2)
+		output, err := processHook(c2, event, hook)
+		recordHookResult(c, archive, output, err)
 		if err != nil {
 			c.Error(err)
 		} else {
@@ -107,6 +173,13 @@ func ProcessHook(c *gin.Context) {
 	}
 }
 
+// processHook processes the hook and records the outcome.
+func processHook(c context.Context, event string, hook Hook) (interface{}, error) {
+	output, err := hook.Process(c)
+	metrics.HooksProcessed.Inc(event, metrics.Outcome(err))
+	return output, err
+}
+
 func (h *CommentHook) IsApproval(req *model.ApprovalRequest) bool {
 	c := model.Comment{
 		Body: h.Comment,
@@ -0,0 +1,216 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const redacted = "[redacted]"
+
+var (
+	// redactedFields are the payload fields whose values are not
+	// archived. A field is redacted if its name contains one of them.
+	redactedFields = []string{"token", "secret", "password", "email", "key"}
+	// redactedHeaders are the request headers whose values are
+	// not archived.
+	redactedHeaders = []string{"Authorization", "Cookie", "X-Hub-Signature", "X-Hub-Signature-256"}
+
+	archivePrunedMu sync.Mutex
+	archivePruned   time.Time
+)
+
+// hookSubject is the part of a webhook payload that
+// identifies the repository and pull request.
+type hookSubject struct {
+	Action     string `json:"action"`
+	Number     int    `json:"number"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	PullRequest *struct {
+		Number int `json:"number"`
+	} `json:"pull_request"`
+	Issue *struct {
+		Number int `json:"number"`
+	} `json:"issue"`
+}
+
+// archiveHook stores the incoming webhook with its headers and
+// payload redacted. It returns nil when the archive is disabled
+// or the webhook could not be stored.
+func archiveHook(c context.Context, delivery, event, baseURL string, header http.Header, body []byte) *model.HookDelivery {
+	if envvars.Env.Hook.ArchiveRetention <= 0 {
+		return nil
+	}
+	pruneHookArchive(c)
+	archive := &model.HookDelivery{
+		Delivery: delivery,
+		Event:    event,
+		BaseURL:  baseURL,
+		Headers:  redactHeaders(header),
+		Created:  hookNow().Unix(),
+	}
+	var subject hookSubject
+	if err := json.Unmarshal(body, &subject); err == nil {
+		archive.Action = subject.Action
+		if subject.Repository != nil {
+			archive.Repo = subject.Repository.FullName
+		}
+		switch {
+		case subject.PullRequest != nil:
+			archive.PullRequest = subject.PullRequest.Number
+		case subject.Issue != nil:
+			archive.PullRequest = subject.Issue.Number
+		default:
+			archive.PullRequest = subject.Number
+		}
+	}
+	if len(body) <= envvars.Env.Hook.ArchiveMaxPayload {
+		archive.Payload = redactPayload(body)
+	}
+	if err := store.CreateHookDelivery(c, archive); err != nil {
+		log.Warnf("Unable to archive %s webhook %s: %s", event, delivery, err)
+		return nil
+	}
+	return archive
+}
+
+// recordHookResult stores the result of the processing of an
+// archived webhook.
+func recordHookResult(c context.Context, archive *model.HookDelivery, output interface{}, err error) {
+	if archive == nil {
+		return
+	}
+	archive.Processed = hookNow().Unix()
+	archive.Result = ""
+	archive.Error = ""
+	if err != nil {
+		archive.Error = err.Error()
+	} else if output != nil {
+		result, _ := json.Marshal(output)
+		archive.Result = string(result)
+	}
+	if err := store.UpdateHookDelivery(c, archive); err != nil {
+		log.Warnf("Unable to update archived webhook %d: %s", archive.ID, err)
+	}
+}
+
+// recordHookJobResult stores the result of a queued webhook
+// in the latest archive of its delivery.
+func recordHookJobResult(c context.Context, job *model.HookJob, output interface{}, err error) {
+	if envvars.Env.Hook.ArchiveRetention <= 0 {
+		return
+	}
+	archive, err2 := store.GetHookDeliveryGUID(c, job.Delivery)
+	if err2 != nil {
+		return
+	}
+	recordHookResult(c, archive, output, err)
+}
+
+// pruneHookArchive removes the webhooks that are older than
+// HOOK_ARCHIVE_RETENTION, at most once per prune interval.
+func pruneHookArchive(c context.Context) {
+	cur := hookNow()
+	archivePrunedMu.Lock()
+	if cur.Sub(archivePruned) < hookPruneInterval {
+		archivePrunedMu.Unlock()
+		return
+	}
+	archivePruned = cur
+	archivePrunedMu.Unlock()
+	err := store.DeleteHookDeliveriesBefore(c, cur.Add(-envvars.Env.Hook.ArchiveRetention).Unix())
+	if err != nil {
+		log.Warnf("Unable to remove archived webhooks: %s", err)
+	}
+}
+
+func redactHeaders(header http.Header) string {
+	out := http.Header{}
+	for name, values := range header {
+		out[name] = values
+	}
+	for _, name := range redactedHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	result, _ := json.Marshal(out)
+	return string(result)
+}
+
+// redactPayload replaces the values of the sensitive fields of a
+// JSON payload. Payloads that are not JSON are not archived.
+func redactPayload(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// keep the ids exact
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return ""
+	}
+	result, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return ""
+	}
+	return string(result)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if redactedField(name) {
+				if field != nil {
+					v[name] = redacted
+				}
+				continue
+			}
+			v[name] = redactValue(field)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return value
+}
+
+func redactedField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range redactedFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactPayload(t *testing.T) {
+	body := `{"id":123456789012,"installation":{"access_tokens_url":"x"},
+		"comment":{"body":"I approve","user":{"login":"alice","email":"alice@example.com"}},
+		"commits":[{"author":{"email":"bob@example.com","name":"bob"}}],"hook":{"config":{"secret":null}}}`
+	payload := redactPayload([]byte(body))
+	var out map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out["id"].(json.Number).String() != "123456789012" {
+		t.Errorf("Expected the id to be kept exactly but was %v", out["id"])
+	}
+	for _, secret := range []string{"alice@example.com", "bob@example.com", "access_tokens_url\":\"x"} {
+		if strings.Contains(payload, secret) {
+			t.Errorf("Expected %s to be redacted in %s", secret, payload)
+		}
+	}
+	if !strings.Contains(payload, "I approve") || !strings.Contains(payload, `"secret":null`) {
+		t.Errorf("Expected the comment and empty fields to be kept in %s", payload)
+	}
+	if redactPayload([]byte("not json")) != "" {
+		t.Error("Expected a payload that is not JSON to be dropped")
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Github-Event", "issue_comment")
+	header.Set("X-Hub-Signature", "sha1=abc")
+	headers := redactHeaders(header)
+	if !strings.Contains(headers, "issue_comment") || strings.Contains(headers, "sha1=abc") {
+		t.Errorf("Unexpected headers %s", headers)
+	}
+	if header.Get("X-Hub-Signature") != "sha1=abc" {
+		t.Error("Expected the request headers to be unchanged")
+	}
+}
+
+func TestProcessHookArchive(t *testing.T) {
+	hs, c := setupHookQueue(t)
+	retention := envvars.Env.Hook.ArchiveRetention
+	envvars.Env.Hook.ArchiveRetention = time.Hour
+	defer func() { envvars.Env.Hook.ArchiveRetention = retention }()
+
+	body := `{"action":"created","issue":{"number":12},"repository":{"full_name":"octocat/hello"}}`
+	w := httptest.NewRecorder()
+	gc, _ := gin.CreateTestContext(w)
+	gc.Request = httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+	gc.Request.Header.Set("X-Github-Event", "unknown")
+	gc.Request.Header.Set("X-Github-Delivery", "d1")
+	gc.Set("store", c.Value("store"))
+	ProcessHook(gc)
+
+	if len(hs.deliveries) != 1 {
+		t.Fatalf("Expected the webhook to be archived but was %v", hs.deliveries)
+	}
+	d := hs.deliveries[0]
+	if d.Delivery != "d1" || d.Event != "unknown" || d.Action != "created" || d.Repo != "octocat/hello" || d.PullRequest != 12 {
+		t.Errorf("Unexpected archived webhook %v", d)
+	}
+	if d.Payload == "" || d.Processed == 0 {
+		t.Errorf("Expected the payload and the processing time to be archived but was %v", d)
+	}
+
+	envvars.Env.Hook.ArchiveRetention = 0
+	if archiveHook(c, "d2", "unknown", "", http.Header{}, []byte(body)) != nil || len(hs.deliveries) != 1 {
+		t.Error("Expected a disabled archive to store nothing")
+	}
+}
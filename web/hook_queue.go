@@ -0,0 +1,269 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+var (
+	// hookRunning is set while the hook workers are running.
+	// Otherwise webhooks are processed during the request.
+	hookRunning int32
+	// hookWakeup signals the dispatcher that a webhook was queued.
+	hookWakeup = make(chan struct{}, 1)
+
+	hookPollInterval = time.Second
+	// hookLeaseDuration is how long a dispatched webhook is hidden
+	// from the dispatcher. A webhook whose worker is interrupted
+	// is processed again after the lease expires.
+	hookLeaseDuration = 10 * time.Minute
+	hookMaxBackoff    = time.Hour
+	hookPruneInterval = time.Hour
+	hookNow           = time.Now
+)
+
+// StartHookWorkers starts the dispatcher and the workers that process
+// the queued webhooks. The context must carry the store, remote and
+// cache that are used by the hooks. The dispatcher stops when stop is
+// closed. Each webhook is processed on a context that is not cancelled
+// by stop and expires with its lease. The returned channel is closed
+// when the workers have finished the webhooks they were processing.
+func StartHookWorkers(c context.Context, stop <-chan struct{}, workers int) <-chan struct{} {
+	done := make(chan struct{})
+	if workers <= 0 {
+		close(done)
+		return done
+	}
+	jobs := make(chan *model.HookJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				jc, cancel := context.WithTimeout(c, hookLeaseDuration)
+				processHookJob(jc, job)
+				cancel()
+			}
+		}()
+	}
+	atomic.StoreInt32(&hookRunning, 1)
+	go func() {
+		defer close(done)
+		dispatchHooks(c, stop, jobs, workers)
+		atomic.StoreInt32(&hookRunning, 0)
+		close(jobs)
+		wg.Wait()
+	}()
+	return done
+}
+
+func hookQueueRunning() bool {
+	return atomic.LoadInt32(&hookRunning) == 1
+}
+
+// queueHook stores the webhook for the workers. A delivery that was
+// already received returns the existing job and is not queued again,
+// unless it has failed and GitHub is asked to redeliver it.
+func queueHook(c context.Context, delivery, event, baseURL string, body []byte) (*model.HookJob, bool, error) {
+	job, err := store.GetHookJobDelivery(c, delivery)
+	if err == nil {
+		if job.State == model.HookJobFailed {
+			err = RequeueHookJob(c, job)
+			return job, err == nil, err
+		}
+		log.Infof("Ignoring duplicate %s webhook delivery %s", event, delivery)
+		return job, false, nil
+	}
+	cur := hookNow().Unix()
+	job = &model.HookJob{
+		Delivery:    delivery,
+		Event:       event,
+		BaseURL:     baseURL,
+		Payload:     string(body),
+		State:       model.HookJobPending,
+		NextAttempt: cur,
+		Created:     cur,
+		Updated:     cur,
+	}
+	err = store.CreateHookJob(c, job)
+	if err != nil {
+		// a concurrent redelivery may have been stored first
+		if existing, err2 := store.GetHookJobDelivery(c, delivery); err2 == nil {
+			return existing, false, nil
+		}
+		return nil, false, exterror.Create(http.StatusInternalServerError, err)
+	}
+	hookWake()
+	return job, true, nil
+}
+
+// RequeueHookJob returns a failed webhook to the
+// queue for immediate processing.
+func RequeueHookJob(c context.Context, job *model.HookJob) error {
+	cur := hookNow().Unix()
+	job.State = model.HookJobPending
+	job.Attempts = 0
+	job.NextAttempt = cur
+	job.Updated = cur
+	err := store.UpdateHookJob(c, job)
+	if err == nil {
+		hookWake()
+	}
+	return err
+}
+
+func hookWake() {
+	select {
+	case hookWakeup <- struct{}{}:
+	default:
+	}
+}
+
+func dispatchHooks(c context.Context, stop <-chan struct{}, jobs chan<- *model.HookJob, batch int) {
+	ticker := time.NewTicker(hookPollInterval)
+	defer ticker.Stop()
+	var pruned time.Time
+	for {
+		if hookNow().Sub(pruned) > hookPruneInterval {
+			pruned = hookNow()
+			err := store.DeleteHookJobsBefore(c, pruned.Add(-envvars.Env.Hook.Retention).Unix())
+			if err != nil {
+				log.Warnf("Unable to remove processed webhooks: %v", err)
+			}
+		}
+		dispatchDueHooks(c, stop, jobs, batch)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-hookWakeup:
+		}
+	}
+}
+
+// dispatchDueHooks leases the webhooks that are due
+// and hands them to the workers.
+func dispatchDueHooks(c context.Context, stop <-chan struct{}, jobs chan<- *model.HookJob, batch int) {
+	for {
+		cur := hookNow()
+		due, err := store.GetDueHookJobs(c, cur.Unix(), batch)
+		if err != nil {
+			log.Warnf("Unable to read the webhook queue: %v", err)
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+		for _, job := range due {
+			lease := cur.Add(hookLeaseDuration).Unix()
+			ok, err := store.ClaimHookJob(c, job.ID, job.NextAttempt, lease)
+			if err != nil {
+				log.Warnf("Unable to lease webhook %s: %v", job.Delivery, err)
+				return
+			}
+			if !ok {
+				// leased by another dispatcher
+				continue
+			}
+			job.NextAttempt = lease
+			select {
+			case jobs <- job:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+// processHookJob runs the hook of a queued webhook and records the
+// result. Failed webhooks are retried with exponential backoff until
+// they run out of attempts. Client errors such as a repository that
+// is not enabled are not retried.
+func processHookJob(c context.Context, job *model.HookJob) {
+	output, err := runHookJob(c, job)
+	recordHookJobResult(c, job, output, err)
+	cur := hookNow()
+	job.Attempts++
+	job.Updated = cur.Unix()
+	if err == nil {
+		job.State = model.HookJobDone
+		job.LastError = ""
+		if output != nil {
+			result, _ := json.Marshal(output)
+			job.Result = string(result)
+		}
+	} else {
+		job.LastError = err.Error()
+		if !retryable(err) || job.Attempts >= envvars.Env.Hook.MaxAttempts {
+			job.State = model.HookJobFailed
+			log.Warnf("Webhook %s %s failed after %d attempts: %v", job.Event, job.Delivery, job.Attempts, err)
+		} else {
+			job.NextAttempt = cur.Add(hookBackoff(job.Attempts)).Unix()
+			log.Debugf("Webhook %s %s failed, retrying: %v", job.Event, job.Delivery, err)
+		}
+	}
+	err = store.UpdateHookJob(c, job)
+	if err != nil {
+		log.Warnf("Unable to update webhook %s: %v", job.Delivery, err)
+	}
+}
+
+func runHookJob(c context.Context, job *model.HookJob) (interface{}, error) {
+	if job.BaseURL != "" {
+		c = context.WithValue(c, "BASE_URL", job.BaseURL)
+	}
+	hook, c2, err := createHook(c, job.Event, job.BaseURL, []byte(job.Payload))
+	if err != nil || hook == nil {
+		return nil, err
+	}
+	return processHook(c2, job.Event, hook)
+}
+
+func retryable(err error) bool {
+	ext, ok := err.(exterror.ExtError)
+	if !ok {
+		return true
+	}
+	return ext.Status == http.StatusTooManyRequests || ext.Status < 400 || ext.Status >= 500
+}
+
+func hookBackoff(attempts int) time.Duration {
+	d := envvars.Env.Hook.Backoff
+	for i := 1; i < attempts && d < hookMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > hookMaxBackoff {
+		d = hookMaxBackoff
+	}
+	return d
+}
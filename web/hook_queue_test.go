@@ -0,0 +1,267 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type hookStore struct {
+	store.Store
+	jobs       map[string]*model.HookJob
+	deliveries []*model.HookDelivery
+	orgErr     error
+}
+
+func (hs *hookStore) CreateHookDelivery(delivery *model.HookDelivery) error {
+	hs.deliveries = append(hs.deliveries, delivery)
+	delivery.ID = int64(len(hs.deliveries))
+	return nil
+}
+
+func (hs *hookStore) GetHookDeliveryGUID(guid string) (*model.HookDelivery, error) {
+	for i := len(hs.deliveries) - 1; i >= 0; i-- {
+		if hs.deliveries[i].Delivery == guid {
+			return hs.deliveries[i], nil
+		}
+	}
+	return nil, exterror.Create(http.StatusNotFound, errors.New("not found"))
+}
+
+func (hs *hookStore) UpdateHookDelivery(delivery *model.HookDelivery) error {
+	hs.deliveries[delivery.ID-1] = delivery
+	return nil
+}
+
+func (hs *hookStore) DeleteHookDeliveriesBefore(created int64) error {
+	return nil
+}
+
+func (hs *hookStore) CreateHookJob(job *model.HookJob) error {
+	if _, ok := hs.jobs[job.Delivery]; ok {
+		return errors.New("duplicate delivery")
+	}
+	job.ID = int64(len(hs.jobs) + 1)
+	hs.jobs[job.Delivery] = job
+	return nil
+}
+
+func (hs *hookStore) GetHookJobDelivery(delivery string) (*model.HookJob, error) {
+	job, ok := hs.jobs[delivery]
+	if !ok {
+		return nil, exterror.Create(http.StatusNotFound, errors.New("not found"))
+	}
+	return job, nil
+}
+
+func (hs *hookStore) GetDueHookJobs(now int64, limit int) ([]*model.HookJob, error) {
+	var result []*model.HookJob
+	for id := int64(1); id <= int64(len(hs.jobs)) && len(result) < limit; id++ {
+		for _, job := range hs.jobs {
+			if job.ID == id && job.State == model.HookJobPending && job.NextAttempt <= now {
+				cp := *job
+				result = append(result, &cp)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (hs *hookStore) ClaimHookJob(id int64, due int64, lease int64) (bool, error) {
+	for _, job := range hs.jobs {
+		if job.ID == id && job.State == model.HookJobPending && job.NextAttempt == due {
+			job.NextAttempt = lease
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// racingHookStore claims every webhook on behalf of
+// another dispatcher between the read and the claim.
+type racingHookStore struct {
+	*hookStore
+}
+
+func (rs racingHookStore) GetDueHookJobs(now int64, limit int) ([]*model.HookJob, error) {
+	due, err := rs.hookStore.GetDueHookJobs(now, limit)
+	for _, job := range due {
+		rs.hookStore.ClaimHookJob(job.ID, job.NextAttempt, now+60)
+	}
+	return due, err
+}
+
+func (hs *hookStore) UpdateHookJob(job *model.HookJob) error {
+	hs.jobs[job.Delivery] = job
+	return nil
+}
+
+func (hs *hookStore) GetOrgByName(owner string) (*model.OrgDb, error) {
+	if hs.orgErr != nil {
+		return nil, hs.orgErr
+	}
+	return &model.OrgDb{Owner: owner, UserID: 1}, nil
+}
+
+func (hs *hookStore) GetUser(id int64) (*model.User, error) {
+	return &model.User{ID: id, Login: "octocat"}, nil
+}
+
+const repoHookBody = `{"action":"edited","repository":{"name":"hello","owner":{"login":"octocat"}}}`
+
+func setupHookQueue(t *testing.T) (*hookStore, context.Context) {
+	hs := &hookStore{jobs: make(map[string]*model.HookJob)}
+	envvars.Env.Hook.MaxAttempts = 3
+	envvars.Env.Hook.Backoff = time.Second
+	return hs, context.WithValue(context.Background(), "store", hs)
+}
+
+func TestQueueHookDuplicate(t *testing.T) {
+	hs, c := setupHookQueue(t)
+	job, created, err := queueHook(c, "d1", "repository", "https://checks-out.example.com", []byte(repoHookBody))
+	if err != nil || !created || job.State != model.HookJobPending {
+		t.Fatalf("Expected queued job but was %v %v %v", job, created, err)
+	}
+	_, created, err = queueHook(c, "d1", "repository", "https://checks-out.example.com", []byte(repoHookBody))
+	if err != nil || created || len(hs.jobs) != 1 {
+		t.Errorf("Expected duplicate delivery to be ignored but was %v %v", created, err)
+	}
+	job.State = model.HookJobFailed
+	job.Attempts = 3
+	_, created, err = queueHook(c, "d1", "repository", "https://checks-out.example.com", []byte(repoHookBody))
+	if err != nil || !created || job.State != model.HookJobPending || job.Attempts != 0 {
+		t.Errorf("Expected failed delivery to be queued again but was %v %v", job, err)
+	}
+}
+
+func TestProcessHookJob(t *testing.T) {
+	hs, c := setupHookQueue(t)
+	queueHook(c, "d1", "repository", "", []byte(repoHookBody))
+	job := hs.jobs["d1"]
+
+	hs.orgErr = errors.New("connection reset")
+	processHookJob(c, job)
+	if job.State != model.HookJobPending || job.Attempts != 1 || job.LastError != "connection reset" {
+		t.Errorf("Expected job to be retried but was %v", job)
+	}
+
+	hs.orgErr = nil
+	processHookJob(c, job)
+	if job.State != model.HookJobDone || job.LastError != "" || job.Attempts != 2 {
+		t.Errorf("Expected job to be done but was %v", job)
+	}
+}
+
+func TestProcessHookJobFailed(t *testing.T) {
+	hs, c := setupHookQueue(t)
+	queueHook(c, "d1", "repository", "", []byte(repoHookBody))
+	queueHook(c, "d2", "repository", "", []byte(repoHookBody))
+
+	hs.orgErr = exterror.Create(http.StatusNotFound, errors.New("org not found"))
+	processHookJob(c, hs.jobs["d1"])
+	if job := hs.jobs["d1"]; job.State != model.HookJobFailed || job.Attempts != 1 {
+		t.Errorf("Expected client error not to be retried but was %v", job)
+	}
+
+	hs.orgErr = errors.New("connection reset")
+	for i := 0; i < 3; i++ {
+		processHookJob(c, hs.jobs["d2"])
+	}
+	if job := hs.jobs["d2"]; job.State != model.HookJobFailed || job.Attempts != 3 {
+		t.Errorf("Expected job to fail after max attempts but was %v", job)
+	}
+}
+
+func TestDispatchDueHooks(t *testing.T) {
+	hs, c := setupHookQueue(t)
+	queueHook(c, "d1", "repository", "", []byte(repoHookBody))
+	queueHook(c, "d2", "repository", "", []byte(repoHookBody))
+	jobs := make(chan *model.HookJob, 2)
+	dispatchDueHooks(c, nil, jobs, 1)
+	if len(jobs) != 2 {
+		t.Fatalf("Expected both webhooks to be dispatched but was %d", len(jobs))
+	}
+	job := <-jobs
+	if lease := hs.jobs[job.Delivery].NextAttempt; lease != job.NextAttempt || lease <= hookNow().Unix() {
+		t.Errorf("Expected dispatched webhook to be leased but was %d", lease)
+	}
+
+	queueHook(c, "d3", "repository", "", []byte(repoHookBody))
+	c = store.AddToContext(context.Background(), racingHookStore{hs})
+	jobs = make(chan *model.HookJob, 1)
+	dispatchDueHooks(c, nil, jobs, 1)
+	if len(jobs) != 0 {
+		t.Error("Webhook claimed by another replica should not be dispatched")
+	}
+}
+
+func TestHookBackoff(t *testing.T) {
+	envvars.Env.Hook.Backoff = 10 * time.Second
+	data := map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		4:  80 * time.Second,
+		20: time.Hour,
+	}
+	for attempts, expected := range data {
+		if actual := hookBackoff(attempts); actual != expected {
+			t.Errorf("Expected backoff %s after %d attempts but was %s", expected, attempts, actual)
+		}
+	}
+}
+
+func TestProcessHookAccepted(t *testing.T) {
+	hs, c := setupHookQueue(t)
+	atomic.StoreInt32(&hookRunning, 1)
+	defer atomic.StoreInt32(&hookRunning, 0)
+
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		gc, _ := gin.CreateTestContext(w)
+		gc.Request = httptest.NewRequest("POST", "/hook", strings.NewReader(repoHookBody))
+		gc.Request.Header.Set("X-Github-Event", "repository")
+		gc.Request.Header.Set("X-Github-Delivery", "d1")
+		gc.Set("store", c.Value("store"))
+		ProcessHook(gc)
+		return w
+	}
+	if w := send(); w.Code != 202 {
+		t.Errorf("Expected 202 accepted but was %d %s", w.Code, w.Body.String())
+	}
+	if w := send(); w.Code != 200 {
+		t.Errorf("Expected 200 for duplicate delivery but was %d %s", w.Code, w.Body.String())
+	}
+	if len(hs.jobs) != 1 {
+		t.Errorf("Expected one queued job but was %d", len(hs.jobs))
+	}
+}
@@ -0,0 +1,262 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// keyLock serializes the holders of a key within the instance.
+// Evaluations of a pull request that arrive while another one
+// is waiting for the lock join the waiting evaluation.
+type keyLock struct {
+	sem  chan struct{}
+	refs int
+	next *evaluation
+}
+
+// evaluation is an approval evaluation that is shared by the
+// requests that were coalesced into it.
+type evaluation struct {
+	done chan struct{}
+	info *ApprovalInfo
+	err  error
+}
+
+var (
+	keyLocksMu sync.Mutex
+	keyLocks   = map[string]*keyLock{}
+
+	// lockOwner identifies the leases held by this instance.
+	lockOwner = newLockOwner()
+
+	lockPollInterval = 250 * time.Millisecond
+	lockNow          = time.Now
+)
+
+func newLockOwner() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+func pullRequestLockKey(repo *model.Repo, number int) string {
+	return fmt.Sprintf("pr:%s/%s#%d", repo.Owner, repo.Name, number)
+}
+
+func tagLockKey(repo *model.Repo) string {
+	return fmt.Sprintf("tag:%s/%s", repo.Owner, repo.Name)
+}
+
+func refKeyLock(key string) *keyLock {
+	keyLocksMu.Lock()
+	defer keyLocksMu.Unlock()
+	l, ok := keyLocks[key]
+	if !ok {
+		l = &keyLock{sem: make(chan struct{}, 1)}
+		keyLocks[key] = l
+	}
+	l.refs++
+	return l
+}
+
+func unrefKeyLock(key string, l *keyLock) {
+	keyLocksMu.Lock()
+	defer keyLocksMu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(keyLocks, key)
+	}
+}
+
+// lockPullRequest waits until the pull request can be processed
+// exclusively. The work must use the returned context, which is
+// cancelled when the lease of the lock is lost. The returned
+// function releases the lock.
+func lockPullRequest(c context.Context, repo *model.Repo, number int) (context.Context, func(), error) {
+	return lockKey(c, pullRequestLockKey(repo, number))
+}
+
+// lockKey waits for the key within the instance and, when
+// LOCK_LEASE is enabled, for the lease of the key in the database.
+// Waiting fails after LOCK_WAIT.
+func lockKey(c context.Context, key string) (context.Context, func(), error) {
+	l := refKeyLock(key)
+	lc, release, err := acquireKeyLock(c, key, l)
+	if err != nil {
+		unrefKeyLock(key, l)
+		return nil, nil, err
+	}
+	return lc, func() {
+		release()
+		unrefKeyLock(key, l)
+	}, nil
+}
+
+func acquireKeyLock(c context.Context, key string, l *keyLock) (context.Context, func(), error) {
+	timeout := time.NewTimer(envvars.Env.Lock.Wait)
+	defer timeout.Stop()
+	select {
+	case l.sem <- struct{}{}:
+	case <-timeout.C:
+		return nil, nil, lockTimeout(key)
+	case <-c.Done():
+		return nil, nil, c.Err()
+	}
+	if !envvars.Env.Lock.Lease {
+		return c, func() { <-l.sem }, nil
+	}
+	lc, stop, err := acquireLease(c, key, timeout.C)
+	if err != nil {
+		<-l.sem
+		return nil, nil, err
+	}
+	return lc, func() {
+		stop()
+		<-l.sem
+	}, nil
+}
+
+// acquireLease polls the database until the key is leased to this
+// instance. The lease is renewed until the returned function is
+// called, so that long evaluations do not lose it. The returned
+// context is cancelled when the lease is lost: another instance
+// took it, or it could not be renewed before it expired.
+func acquireLease(c context.Context, key string, timeout <-chan time.Time) (context.Context, func(), error) {
+	ttl := envvars.Env.Lock.LeaseTTL
+	for {
+		now := lockNow()
+		ok, err := store.AcquireLease(c, key, lockOwner, now.Unix(), now.Add(ttl).Unix())
+		if err != nil {
+			return nil, nil, exterror.Create(http.StatusInternalServerError, err)
+		}
+		if ok {
+			break
+		}
+		select {
+		case <-time.After(lockPollInterval):
+		case <-timeout:
+			return nil, nil, lockTimeout(key)
+		case <-c.Done():
+			return nil, nil, c.Err()
+		}
+	}
+	lc, cancel := context.WithCancel(c)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		renewed := lockNow()
+		for {
+			select {
+			case <-ticker.C:
+				now := lockNow()
+				ok, err := store.AcquireLease(c, key, lockOwner, now.Unix(), now.Add(ttl).Unix())
+				if err == nil && ok {
+					renewed = now
+					continue
+				}
+				if err == nil || now.Sub(renewed) >= ttl {
+					log.Warnf("Lost lease of %s: %v", key, err)
+					cancel()
+					return
+				}
+				log.Warnf("Unable to renew lease of %s: %v", key, err)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return lc, func() {
+		close(done)
+		<-stopped
+		cancel()
+		if err := store.ReleaseLease(c, key, lockOwner); err != nil {
+			log.Warnf("Unable to release lease of %s: %s", key, err)
+		}
+	}, nil
+}
+
+func lockTimeout(key string) error {
+	return exterror.Create(http.StatusServiceUnavailable,
+		fmt.Errorf("Timed out waiting for the lock of %s", key))
+}
+
+// evaluatePullRequest runs the evaluation of the pull request while
+// holding its lock. A burst of events for the same pull request is
+// coalesced: requests that arrive while an evaluation is waiting for
+// the lock share the result of that evaluation instead of queueing
+// their own. The waiting evaluation starts after the events were
+// received, so it sees the latest state of the pull request. Each
+// coalesced request receives its own copy of the result.
+func evaluatePullRequest(c context.Context, repo *model.Repo, number int,
+	eval func(context.Context) (*ApprovalInfo, error)) (*ApprovalInfo, error) {
+	key := pullRequestLockKey(repo, number)
+	l := refKeyLock(key)
+	defer unrefKeyLock(key, l)
+
+	keyLocksMu.Lock()
+	if e := l.next; e != nil {
+		keyLocksMu.Unlock()
+		log.Debugf("Coalescing evaluation of %s", key)
+		select {
+		case <-e.done:
+			if e.info == nil {
+				return nil, e.err
+			}
+			info := *e.info
+			return &info, e.err
+		case <-c.Done():
+			return nil, c.Err()
+		}
+	}
+	e := &evaluation{done: make(chan struct{})}
+	l.next = e
+	keyLocksMu.Unlock()
+
+	lc, release, err := acquireKeyLock(c, key, l)
+	keyLocksMu.Lock()
+	l.next = nil
+	keyLocksMu.Unlock()
+	if err == nil {
+		e.info, e.err = eval(lc)
+		release()
+	} else {
+		e.err = err
+	}
+	close(e.done)
+	return e.info, e.err
+}
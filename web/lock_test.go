@@ -0,0 +1,265 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/store"
+)
+
+type leaseStore struct {
+	store.Store
+	mu       sync.Mutex
+	owner    string
+	refused  int
+	released int
+}
+
+func (ls *leaseStore) AcquireLease(key, owner string, now, expires int64) (bool, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.owner != "" && ls.owner != owner {
+		ls.refused++
+		return false, nil
+	}
+	ls.owner = owner
+	return true, nil
+}
+
+func (ls *leaseStore) ReleaseLease(key, owner string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.owner == owner {
+		ls.owner = ""
+	}
+	ls.released++
+	return nil
+}
+
+func (ls *leaseStore) setOwner(owner string) {
+	ls.mu.Lock()
+	ls.owner = owner
+	ls.mu.Unlock()
+}
+
+var lockRepo = &model.Repo{Owner: "octocat", Name: "hello"}
+
+func waitForRefs(t *testing.T, key string, refs int) {
+	for i := 0; i < 1000; i++ {
+		keyLocksMu.Lock()
+		l, ok := keyLocks[key]
+		cur := 0
+		if ok {
+			cur = l.refs
+		}
+		keyLocksMu.Unlock()
+		if cur == refs {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d references to %s", refs, key)
+}
+
+func TestLockPullRequestSerializes(t *testing.T) {
+	var active, overlap int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, release, err := lockPullRequest(context.Background(), lockRepo, 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if atomic.AddInt32(&active, 1) > 1 {
+				atomic.StoreInt32(&overlap, 1)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			release()
+		}()
+	}
+	wg.Wait()
+	if overlap != 0 {
+		t.Error("Expected the pull request to be locked by one holder at a time")
+	}
+	if len(keyLocks) != 0 {
+		t.Errorf("Expected the locks to be removed, got %d", len(keyLocks))
+	}
+}
+
+func TestEvaluatePullRequestCoalesces(t *testing.T) {
+	key := pullRequestLockKey(lockRepo, 2)
+	_, release, err := lockPullRequest(context.Background(), lockRepo, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evals int32
+	eval := func(context.Context) (*ApprovalInfo, error) {
+		atomic.AddInt32(&evals, 1)
+		return &ApprovalInfo{Approved: true}, nil
+	}
+	results := make(chan *ApprovalInfo, 5)
+	seen := map[*ApprovalInfo]bool{}
+	for i := 0; i < 5; i++ {
+		go func() {
+			info, err := evaluatePullRequest(context.Background(), lockRepo, 2, eval)
+			if err != nil {
+				t.Error(err)
+			}
+			results <- info
+		}()
+	}
+	waitForRefs(t, key, 6)
+	release()
+	for i := 0; i < 5; i++ {
+		info := <-results
+		if info == nil || !info.Approved {
+			t.Errorf("Expected the shared evaluation result, got %v", info)
+		}
+		if seen[info] {
+			t.Error("Expected each request to receive its own copy of the result")
+		}
+		seen[info] = true
+	}
+	if evals != 1 {
+		t.Errorf("Expected a single evaluation, got %d", evals)
+	}
+}
+
+func TestEvaluatePullRequestAfterRunning(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var evals int32
+	go evaluatePullRequest(context.Background(), lockRepo, 3, func(context.Context) (*ApprovalInfo, error) {
+		atomic.AddInt32(&evals, 1)
+		close(started)
+		<-finish
+		return nil, nil
+	})
+	<-started
+	// an event received during an evaluation is evaluated again
+	done := make(chan struct{})
+	go func() {
+		evaluatePullRequest(context.Background(), lockRepo, 3, func(context.Context) (*ApprovalInfo, error) {
+			atomic.AddInt32(&evals, 1)
+			return nil, nil
+		})
+		close(done)
+	}()
+	waitForRefs(t, pullRequestLockKey(lockRepo, 3), 2)
+	close(finish)
+	<-done
+	if evals != 2 {
+		t.Errorf("Expected two evaluations, got %d", evals)
+	}
+}
+
+func TestLockLease(t *testing.T) {
+	lease, pollInterval, wait := envvars.Env.Lock.Lease, lockPollInterval, envvars.Env.Lock.Wait
+	defer func() {
+		envvars.Env.Lock.Lease, lockPollInterval, envvars.Env.Lock.Wait = lease, pollInterval, wait
+	}()
+	envvars.Env.Lock.Lease = true
+	envvars.Env.Lock.Wait = time.Second
+	lockPollInterval = time.Millisecond
+
+	ls := &leaseStore{owner: "other-instance"}
+	c := context.WithValue(context.Background(), "store", ls)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ls.setOwner("")
+	}()
+	_, release, err := lockPullRequest(c, lockRepo, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ls.refused == 0 {
+		t.Error("Expected to wait for the lease of the other instance")
+	}
+	if ls.owner != lockOwner {
+		t.Errorf("Expected lease owner %s, got %s", lockOwner, ls.owner)
+	}
+	release()
+	if ls.owner != "" || ls.released != 1 {
+		t.Error("Expected the lease to be released")
+	}
+}
+
+func TestLockLeaseTimeout(t *testing.T) {
+	lease, pollInterval, wait := envvars.Env.Lock.Lease, lockPollInterval, envvars.Env.Lock.Wait
+	defer func() {
+		envvars.Env.Lock.Lease, lockPollInterval, envvars.Env.Lock.Wait = lease, pollInterval, wait
+	}()
+	envvars.Env.Lock.Lease = true
+	envvars.Env.Lock.Wait = 20 * time.Millisecond
+	lockPollInterval = time.Millisecond
+
+	ls := &leaseStore{owner: "other-instance"}
+	c := context.WithValue(context.Background(), "store", ls)
+	_, _, err := lockPullRequest(c, lockRepo, 5)
+	if ee, ok := err.(exterror.ExtError); !ok || ee.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected lock timeout, got %v", err)
+	}
+	// the lock within the instance is not kept
+	envvars.Env.Lock.Lease = false
+	_, release, err := lockPullRequest(c, lockRepo, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestLockLeaseLost(t *testing.T) {
+	lease, ttl := envvars.Env.Lock.Lease, envvars.Env.Lock.LeaseTTL
+	defer func() {
+		envvars.Env.Lock.Lease, envvars.Env.Lock.LeaseTTL = lease, ttl
+	}()
+	envvars.Env.Lock.Lease = true
+	envvars.Env.Lock.LeaseTTL = 30 * time.Millisecond
+
+	ls := &leaseStore{}
+	c := context.WithValue(context.Background(), "store", ls)
+	lc, release, err := lockPullRequest(c, lockRepo, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if lc.Err() != nil {
+		t.Fatal("Expected the context of the lock to be active")
+	}
+	// another instance took the lease after it expired
+	ls.setOwner("other-instance")
+	select {
+	case <-lc.Done():
+	case <-time.After(time.Second):
+		t.Error("Expected the context of the lock to be cancelled when the lease is lost")
+	}
+}
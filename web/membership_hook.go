@@ -0,0 +1,189 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/set"
+	"github.com/capitalone/checks-out/snapshot"
+	"github.com/capitalone/checks-out/store"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// InvalidationOutput reports the cache entries that were
+// evicted and the pull requests that were evaluated again.
+type InvalidationOutput struct {
+	Event       string   `json:"event"`
+	Action      string   `json:"action"`
+	Owner       string   `json:"owner"`
+	Name        string   `json:"name,omitempty"`
+	Evicted     []string `json:"evicted"`
+	Reevaluated []string `json:"reevaluated"`
+}
+
+func (hook *MembershipHook) Process(c context.Context) (interface{}, error) {
+	return doMembershipHook(c, hook)
+}
+
+func (hook *PushHook) Process(c context.Context) (interface{}, error) {
+	return doPushHook(c, hook)
+}
+
+// doMembershipHook evicts the cached teams and permissions that are
+// affected by the change. The open pull requests of the repository of a
+// team change, or of all the repositories in the organization, are
+// evaluated again because approvers may have been added or removed.
+func doMembershipHook(c context.Context, hook *MembershipHook) (*InvalidationOutput, error) {
+	output := &InvalidationOutput{
+		Event:  hook.Event,
+		Action: hook.Action,
+		Owner:  hook.Owner,
+		Name:   hook.Name,
+	}
+	if hook.Owner == "" {
+		return nil, nil
+	}
+	output.Evicted = remote.EvictMember(c, hook.Owner, hook.Login)
+
+	var repos []*model.Repo
+	if hook.Name != "" {
+		repo, err := store.GetRepoOwnerName(c, hook.Owner, hook.Name)
+		if err != nil {
+			log.Debugf("Repository %s/%s is not enabled: %v", hook.Owner, hook.Name, err)
+			return output, nil
+		}
+		repos = append(repos, repo)
+	} else {
+		var err error
+		repos, err = store.GetReposForOrg(c, hook.Owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+	output.Reevaluated = reevaluateRepos(c, repos)
+	return output, nil
+}
+
+// doPushHook evaluates the open pull requests of the repository again
+// when a push changes the files that its configuration is read from.
+// A push that changes the template files of the organization repository
+// evaluates the open pull requests of the whole organization again.
+func doPushHook(c context.Context, hook *PushHook) (*InvalidationOutput, error) {
+	if hook.Repo.Name == snapshot.OrgRepoName {
+		changed := changedFiles(hook.Files, snapshot.OrgConfigFiles())
+		if len(changed) > 0 {
+			return doOrgConfigPush(c, hook, changed)
+		}
+	}
+	repo, user, caps, err := GetRepoAndUser(c, hook.Repo.Slug)
+	if err != nil {
+		log.Debugf("Repository %s is not enabled: %v", hook.Repo.Slug, err)
+		return nil, nil
+	}
+	// a configuration that cannot be read is reported
+	// on the open pull requests when they are evaluated
+	config, err := snapshot.GetConfig(c, user, caps, repo)
+	if err == nil {
+		changed := changedFiles(hook.Files, snapshot.ConfigFiles(config))
+		if len(changed) == 0 {
+			return nil, nil
+		}
+		log.Infof("repository %s push changed %s", repo.Slug, strings.Join(changed, ", "))
+	}
+	output := &InvalidationOutput{
+		Event:   hook.Event,
+		Owner:   repo.Owner,
+		Name:    repo.Name,
+		Evicted: remote.EvictRepo(c, repo.Owner, repo.Name),
+	}
+	output.Reevaluated = reevaluateRepos(c, []*model.Repo{repo})
+	return output, nil
+}
+
+func doOrgConfigPush(c context.Context, hook *PushHook, changed []string) (*InvalidationOutput, error) {
+	log.Infof("organization %s push changed %s", hook.Repo.Owner, strings.Join(changed, ", "))
+	repos, err := store.GetReposForOrg(c, hook.Repo.Owner)
+	if err != nil {
+		return nil, err
+	}
+	output := &InvalidationOutput{
+		Event:   hook.Event,
+		Owner:   hook.Repo.Owner,
+		Evicted: []string{},
+	}
+	for _, repo := range repos {
+		output.Evicted = append(output.Evicted, remote.EvictRepo(c, repo.Owner, repo.Name)...)
+	}
+	output.Reevaluated = reevaluateRepos(c, repos)
+	return output, nil
+}
+
+// changedFiles returns the files of a push that are watched.
+func changedFiles(files []string, watched set.Set) []string {
+	clean := set.Empty()
+	for file := range watched {
+		clean.Add(cleanPath(file))
+	}
+	var changed []string
+	for _, file := range files {
+		if clean.Contains(cleanPath(file)) {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+func cleanPath(file string) string {
+	return strings.TrimPrefix(path.Clean("/"+file), "/")
+}
+
+// reevaluateRepos updates the status of the open pull requests of the
+// repositories. Errors are logged so that one repository does not
+// prevent the others from being evaluated.
+func reevaluateRepos(c context.Context, repos []*model.Repo) []string {
+	reevaluated := []string{}
+	for _, repo := range repos {
+		params, err := GetHookParametersBasic(c, repo.Slug)
+		if err != nil {
+			log.Warnf("Unable to evaluate repository %s: %v", repo.Slug, err)
+			continue
+		}
+		prs, err := remote.ListOpenPullRequests(c, params.User, params.Repo)
+		if err != nil {
+			log.Warnf("Unable to list pull requests of %s: %v", repo.Slug, err)
+			continue
+		}
+		for _, pr := range prs {
+			_, err = approve(c, params, pr.Number, true)
+			if err != nil {
+				log.Warnf("Unable to evaluate %s#%d: %v", repo.Slug, pr.Number, err)
+				continue
+			}
+			reevaluated = append(reevaluated, fmt.Sprintf("%s#%d", repo.Slug, pr.Number))
+		}
+	}
+	return reevaluated
+}
@@ -0,0 +1,157 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/cache"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/set"
+	"github.com/capitalone/checks-out/store"
+)
+
+type membershipStore struct {
+	store.Store
+	repos []*model.Repo
+}
+
+func (ms *membershipStore) GetReposForOrg(owner string) ([]*model.Repo, error) {
+	return ms.repos, nil
+}
+
+func (ms *membershipStore) GetRepoSlug(slug string) (*model.Repo, error) {
+	return nil, errors.New("not found")
+}
+
+func TestCreatePushHook(t *testing.T) {
+	body := `{"ref":"refs/heads/main",
+		"repository":{"name":"hello","full_name":"octocat/hello","default_branch":"main","owner":{"name":"octocat"}},
+		"commits":[{"added":["docs/MAINTAINERS"],"modified":["README.md",".checks-out"]}]}`
+	hook, err := createPushHook([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	push, ok := hook.(*PushHook)
+	if !ok {
+		t.Fatalf("Expected push hook but was %v", hook)
+	}
+	if push.Repo.Owner != "octocat" || push.Repo.Slug != "octocat/hello" {
+		t.Errorf("Unexpected repository %v", push.Repo)
+	}
+	if len(push.Files) != 3 || push.Files[0] != ".checks-out" || push.Files[1] != "README.md" || push.Files[2] != "docs/MAINTAINERS" {
+		t.Errorf("Unexpected files %v", push.Files)
+	}
+
+	data := []string{
+		// not the default branch
+		`{"ref":"refs/heads/feature","repository":{"default_branch":"main"},"commits":[{"modified":[".checks-out"]}]}`,
+		// no changed files
+		`{"ref":"refs/heads/main","repository":{"default_branch":"main"},"commits":[]}`,
+	}
+	for _, d := range data {
+		hook, err = createPushHook([]byte(d))
+		if err != nil || hook != nil {
+			t.Errorf("Expected push to be ignored but was %v %v", hook, err)
+		}
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	watched := set.New(".checks-out", "/docs/MAINTAINERS", "deploy.json")
+	changed := changedFiles([]string{"README.md", "docs/MAINTAINERS", ".checks-out", "MAINTAINERS"}, watched)
+	if len(changed) != 2 || changed[0] != "docs/MAINTAINERS" || changed[1] != ".checks-out" {
+		t.Errorf("Unexpected changed files %v", changed)
+	}
+	if changed = changedFiles([]string{"main.go"}, watched); len(changed) != 0 {
+		t.Errorf("Expected no changed files but was %v", changed)
+	}
+}
+
+func TestCreateMembershipHooks(t *testing.T) {
+	hook, err := createMembershipHook([]byte(`{"action":"removed","member":{"login":"alice"},"team":{"name":"dev"},"organization":{"login":"octocat"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := hook.(*MembershipHook); m.Owner != "octocat" || m.Login != "alice" || m.Action != "removed" {
+		t.Errorf("Unexpected membership hook %v", m)
+	}
+	hook, err = createTeamHook([]byte(`{"action":"removed_from_repository","team":{"name":"dev"},"repository":{"name":"hello"},"organization":{"login":"octocat"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := hook.(*MembershipHook); m.Owner != "octocat" || m.Name != "hello" {
+		t.Errorf("Unexpected team hook %v", m)
+	}
+	hook, err = createOrganizationHook([]byte(`{"action":"member_removed","membership":{"user":{"login":"bob"}},"organization":{"login":"octocat"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := hook.(*MembershipHook); m.Owner != "octocat" || m.Login != "bob" {
+		t.Errorf("Unexpected organization hook %v", m)
+	}
+}
+
+func TestDoMembershipHook(t *testing.T) {
+	ch := cache.NewTTL(time.Minute)
+	c := context.WithValue(context.Background(), "store", &membershipStore{})
+	c = context.WithValue(c, "cache", ch)
+	ch.Set("teams:octocat", set.New("dev"))
+	ch.Set("orgs:alice", []*model.GitHubOrg{{Login: "octocat"}})
+	ch.Set("perms:octocat/hello:alice", &model.Perm{Push: true})
+	ch.Set("perms:octocat/hello:bob", &model.Perm{Push: true})
+	ch.Set("perms:other/hello:alice", &model.Perm{Push: true})
+	cache.Longterm().Set("people:alice", &model.Person{Login: "alice"})
+
+	output, err := doMembershipHook(c, &MembershipHook{
+		HookCommon: HookCommon{Event: "membership", Action: "removed"},
+		Owner:      "octocat",
+		Login:      "alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"teams:octocat", "orgs:alice", "perms:octocat/hello:alice", "perms:octocat/hello:bob"} {
+		if _, err := ch.Get(key); err == nil {
+			t.Errorf("Expected %s to be evicted", key)
+		}
+	}
+	if _, err := cache.Longterm().Get("people:alice"); err == nil {
+		t.Error("Expected people:alice to be evicted")
+	}
+	if _, err := ch.Get("perms:other/hello:alice"); err != nil {
+		t.Error("Expected permissions of other organization to remain")
+	}
+	if len(output.Evicted) != 4 || len(output.Reevaluated) != 0 {
+		t.Errorf("Unexpected output %v", output)
+	}
+
+	// team changes to a repository that is not enabled
+	output, err = doMembershipHook(c, &MembershipHook{
+		HookCommon: HookCommon{Event: "team", Action: "added_to_repository"},
+		Owner:      "octocat",
+		Name:       "hello",
+	})
+	if err != nil || output == nil || len(output.Reevaluated) != 0 {
+		t.Errorf("Unexpected output %v %v", output, err)
+	}
+}
@@ -20,10 +20,13 @@ package web
 
 import (
 	"context"
+	"sort"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/set"
 )
 
 func isBehind(c context.Context, user *model.User, repo *model.Repo, branch model.Branch) (bool, error) {
@@ -35,27 +38,70 @@ func isBehind(c context.Context, user *model.User, repo *model.Repo, branch mode
 }
 
 func doMerge(c context.Context, user *model.User,
-	hook *StatusHook, req *model.ApprovalRequest, policy *model.ApprovalPolicy, mergeMethod string) (string, error) {
+	hook *StatusHook, req *model.ApprovalRequest, policy *model.ApprovalPolicy, mergeConfig *model.MergeConfig) (string, error) {
 	approvals, err := buildApprovers(c, user, req)
 	if err != nil {
 		return "", err
 	}
+	message, err := getMergeMessage(req, policy, approvals, mergeConfig)
+	if err != nil {
+		return "", err
+	}
+	log.Debugf("Constructed merge message: %v", message)
+
+	SHA, err := remote.MergePR(c, user, hook.Repo, *req.PullRequest, message, mergeConfig.Method)
+	if err != nil {
+		return "", err
+	}
+	return SHA, nil
+}
+
+func getMergeMessage(req *model.ApprovalRequest, policy *model.ApprovalPolicy,
+	approvals *ApprovalInfo, mergeConfig *model.MergeConfig) (string, error) {
+	comment := getCommitComment(req, policy)
+	log.Debugf("parsed out commit comment message, got: %v", comment)
+	body := model.TemplateMerge{
+		Title:     req.PullRequest.Title,
+		Body:      req.PullRequest.Body,
+		Number:    req.PullRequest.Number,
+		Author:    req.PullRequest.Author.String(),
+		Policy:    policyDescription(approvals),
+		Comment:   comment,
+		MergedBy:  envvars.Env.Branding.ShortName,
+		Approvers: getPeople(req, approvals.Approvers),
+		Issues:    req.Issues,
+		CoAuthors: getCoAuthors(req),
+	}
+	return mergeConfig.GenerateMessage(body)
+}
+
+// getPeople looks up the logins in the maintainers file
+// and returns the people sorted by login.
+func getPeople(req *model.ApprovalRequest, logins set.Set) []*model.Person {
 	var people []*model.Person
-	for id := range approvals.Approvers {
+	ids := logins.Keys()
+	sort.Strings(ids)
+	for _, id := range ids {
 		if p, ok := req.Maintainer.People[id]; ok {
 			people = append(people, p)
 		} else {
 			people = append(people, &model.Person{Login: id})
 		}
 	}
-	message := getCommitComment(req, policy)
-	log.Debugf("parsed out commit comment message, got: %v", message)
+	return people
+}
 
-	SHA, err := remote.MergePR(c, user, hook.Repo, *req.PullRequest, people, message, mergeMethod)
-	if err != nil {
-		return "", err
+// getCoAuthors returns the commit authors that are not
+// the author of the pull request.
+func getCoAuthors(req *model.ApprovalRequest) []*model.Person {
+	authors := set.Empty()
+	for _, commit := range req.Commits {
+		author := commit.Author.String()
+		if len(author) > 0 && commit.Author != req.PullRequest.Author {
+			authors.Add(author)
+		}
 	}
-	return SHA, nil
+	return getPeople(req, authors)
 }
 
 func doMergeDelete(c context.Context, user *model.User,
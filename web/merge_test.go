@@ -0,0 +1,88 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"testing"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/set"
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMergeMessage(t *testing.T) {
+	config := model.NonEmptyConfig()
+	m := &model.MaintainerSnapshot{
+		People: map[string]*model.Person{
+			"test_guy2": &model.Person{
+				Name:  "Test Guy",
+				Email: "test_guy2@mail.com",
+				Login: "test_guy2",
+			},
+		},
+	}
+	i := model.PullRequest{
+		Issue: model.Issue{
+			Number: 7,
+			Title:  "Add a feature",
+			Author: lowercase.Create("test_guy"),
+		},
+	}
+	comments := []model.Feedback{
+		&model.Comment{
+			Author: lowercase.Create("test_guy2"),
+			Body:   "I approve comment:Hi there",
+		},
+	}
+	request := &model.ApprovalRequest{
+		Config:           config,
+		Maintainer:       m,
+		PullRequest:      &i,
+		ApprovalComments: comments,
+		Issues:           []*model.Issue{{Number: 3}},
+		Commits: []model.Commit{
+			{Author: lowercase.Create("test_guy")},
+			{Author: lowercase.Create("test_guy2")},
+			{Author: lowercase.Create("test_guy3")},
+		},
+	}
+	policy := config.Approvals[0]
+	approvals := &ApprovalInfo{
+		Policy:    policy,
+		Approvers: set.New("test_guy3", "test_guy2"),
+	}
+	message, err := getMergeMessage(request, policy, approvals, &config.Merge)
+	assert.Nil(t, err)
+	assert.Equal(t, "Hi there\nMerged by checks-out\nApproved by:\n"+
+		"Test Guy <test_guy2@mail.com> (@test_guy2)\n(@test_guy3)\n", message)
+
+	mergeConfig := model.MergeConfig{TemplateRaw: `{{.Title}} (#{{.Number}}) [{{.Policy}}]
+{{range .Issues}}
+Closes #{{.Number}}{{end}}
+{{range .CoAuthors}}
+{{coauthor .}}{{end}}`}
+	assert.Nil(t, mergeConfig.Compile())
+	message, err = getMergeMessage(request, policy, approvals, &mergeConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "Add a feature (#7) [# 1]\n\nCloses #3\n\n"+
+		"Co-authored-by: Test Guy <test_guy2@mail.com>\n"+
+		"Co-authored-by: test_guy3 <test_guy3@users.noreply.github.com>", message)
+}
@@ -19,8 +19,6 @@ See the License for the specific language governing permissions and limitations
 package web
 
 import (
-	"fmt"
-
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/notifier"
 
@@ -36,32 +34,66 @@ func handleApprovalNotification(hook *ApprovalHook, curCommentInfo *CurCommentIn
 		},
 	}
 	if curCommentInfo != nil {
-		var mi notifier.MessageInfo
+		data := model.MessageData{
+			Author: hook.Issue.Author.String(),
+			Actor:  curCommentInfo.Author,
+		}
+		var msgType model.CommentMessage
 		switch curCommentInfo.Status {
 		case CurCommentNoChange:
 			// do nothing
 		case CurCommentApproval:
-			mi.Message = fmt.Sprintf("approval added by %s.", curCommentInfo.Author)
-			mi.Type = model.CommentApprove
+			data.Reason = model.ReasonComment
+			msgType = model.CommentApprove
 		case CurCommentDisapproval:
-			mi.Message = fmt.Sprintf("blocked by %s.", curCommentInfo.Author)
-			mi.Type = model.CommentBlock
+			data.Reason = model.ReasonComment
+			msgType = model.CommentBlock
 		case CurCommentPRAuthor:
-			mi.Message = fmt.Sprintf("blocked because it was created by unapproved author %s.", hook.Issue.Author)
-			mi.Type = model.CommentBlock
+			data.Reason = model.ReasonAuthor
+			msgType = model.CommentBlock
 		case CurCommentPRTitle:
-			mi.Message = "blocked because its title indicates that it should not be merged"
-			mi.Type = model.CommentBlock
+			data.Reason = model.ReasonTitle
+			msgType = model.CommentBlock
 		case CurCommentPRAudit:
-			mi.Message = "blocked by gap in audit chain"
-			mi.Type = model.CommentBlock
+			data.Reason = model.ReasonAudit
+			msgType = model.CommentBlock
 		default:
 			log.Warnf("Invalid curCommentInfo.Status found, skipping: %v", curCommentInfo.Status)
 		}
 
-		if mi.Message != "" {
-			mw.Messages = append(mw.Messages, mi)
+		if msgType.Known() {
+			mw.Messages = append(mw.Messages, notifier.NewMessage(msgType, data))
 		}
 	}
 	return mw
 }
+
+// setMessageDetails records the pull request and approval details
+// in the message header for the structured notification targets.
+func setMessageDetails(mw *notifier.MessageWrapper, pr *model.PullRequest, ai *ApprovalInfo) {
+	if pr != nil {
+		mw.HeadSHA = pr.Branch.CompareSHA
+		mw.BaseSHA = pr.Branch.BaseSHA
+		mw.MergeSHA = pr.Branch.MergeCommitSHA
+	}
+	if ai != nil {
+		if ai.Policy != nil {
+			mw.Policy = policyDescription(ai)
+		}
+		mw.Approvers = ai.Approvers.KeysSorted(func(s1, s2 string) bool {
+			return s1 < s2
+		})
+		mw.Disapprovers = ai.Disapprovers.KeysSorted(func(s1, s2 string) bool {
+			return s1 < s2
+		})
+		mw.NeededApprovers = ai.Needed
+		mw.Author = ai.PullRequestAuthor
+		mw.Requirements = []notifier.Requirement{
+			{Description: "audit chain is approved", Met: ai.AuditApproved},
+			{Description: "pull request title is allowed", Met: ai.TitleApproved},
+			{Description: "pull request author is allowed", Met: ai.AuthorApproved},
+			{Description: "non-committer or PR author has approved", Met: ai.AuthorAffirmed},
+			{Description: "approval policy is satisfied", Met: ai.Approved},
+		}
+	}
+}
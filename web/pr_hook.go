@@ -98,7 +98,12 @@ func prOpened(c context.Context, hook *PRHook, params HookParams) error {
 
 func prHandle(c context.Context, hook *PRHook, params HookParams) (*ApprovalOutput, *notifier.MessageWrapper, error) {
 	var approvalOutput *ApprovalOutput
-	approvalInfo, err := approvePullRequest(c, params, hook.Issue.Number, hook.PullRequest, true)
+	lc, release, err := lockPullRequest(c, params.Repo, hook.Issue.Number)
+	if err != nil {
+		return nil, nil, err
+	}
+	approvalInfo, err := approvePullRequest(lc, params, hook.Issue.Number, hook.PullRequest, true)
+	release()
 
 	if err == nil {
 		approvalOutput = &ApprovalOutput{
@@ -165,29 +170,31 @@ func handleNotification(c context.Context, prHook *PRHook, params HookParams, ai
 			Slug:     prHook.Repo.Slug,
 		},
 	}
+	setMessageDetails(mw, prHook.PullRequest, ai)
 	if ai == nil {
 		return mw
 	}
-	var mi notifier.MessageInfo
+	data := model.MessageData{
+		Author: prHook.Issue.Author.String(),
+		Policy: policyDescription(ai),
+	}
+	if prHook.PullRequest != nil {
+		data.PullRequest.Branch = prHook.PullRequest.Branch.CompareName
+	}
+	var msgType model.CommentMessage
 	switch prHook.Action {
 	case "opened":
-		desc := policyDescription(ai)
-		mi.Message = "opened"
-		if len(desc) > 0 {
-			mi.Message += fmt.Sprintf(" Applying approval policy %s", desc)
-		}
-		mi.Type = model.CommentOpen
+		data.Reason = model.ReasonOpened
+		msgType = model.CommentOpen
 	case "closed":
 		if prHook.PullRequest.Branch.Merged {
-			mi.Message = "merged"
-			mi.Type = model.CommentAccept
+			msgType = model.CommentAccept
 		} else {
-			mi.Message = "closed without being merged"
-			mi.Type = model.CommentClose
+			msgType = model.CommentClose
 		}
 	case "reopened":
-		mi.Message = "reopened"
-		mi.Type = model.CommentOpen
+		data.Reason = model.ReasonReopened
+		msgType = model.CommentOpen
 	case "synchronize":
 		if params.Config.Commit.Range == model.Head {
 			if params.Config.Commit.IgnoreUIMerge {
@@ -196,20 +203,17 @@ func handleNotification(c context.Context, prHook *PRHook, params HookParams, ai
 					log.Warnf("Unable to test HEAD of pull request %s/%s/%d",
 						prHook.Repo.Owner, prHook.Repo.Name, prHook.Issue.Number)
 				} else if merge {
-					mi.Message = "merged through the user interface. Merge commit ignored."
-					mi.Type = model.CommentPushIgnore
+					msgType = model.CommentPushIgnore
 				} else {
-					mi.Message = "updated. No comments before this one will count for approval."
-					mi.Type = model.CommentReset
+					msgType = model.CommentReset
 				}
 			} else {
-				mi.Message = "updated. No comments before this one will count for approval."
-				mi.Type = model.CommentReset
+				msgType = model.CommentReset
 			}
 		}
 	}
-	if mi.Message != "" {
-		mw.Messages = append(mw.Messages, mi)
+	if msgType.Known() {
+		mw.Messages = append(mw.Messages, notifier.NewMessage(msgType, data))
 	}
 	return mw
 }
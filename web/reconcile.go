@@ -0,0 +1,379 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/store"
+	"github.com/capitalone/checks-out/usage"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// The results of the reconciliation of a pull request.
+const (
+	reconcileUnchanged = "unchanged"
+	reconcileRepaired  = "repaired"
+	reconcileMerged    = "merged"
+	reconcileSkipped   = "skipped"
+	reconcileError     = "error"
+	// reconcileStale is the result of a dry run that
+	// would have repaired the status
+	reconcileStale = "stale"
+)
+
+// reconcileLeaseKey elects the instance that reconciles when
+// several instances share the database.
+const reconcileLeaseKey = "reconcile"
+
+// reconcileTimeout bounds the reconciliation of a pull request,
+// which keeps running when the reconciler is stopped.
+const reconcileTimeout = 10 * time.Minute
+
+// ReconcileResult is the outcome of the reconciliation of
+// a pull request. Errors and skips of a whole repository
+// have no pull request number.
+type ReconcileResult struct {
+	Repository string          `json:"repository"`
+	Number     int             `json:"number,omitempty"`
+	Result     string          `json:"result"`
+	Previous   string          `json:"previous_status,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Merge      *StatusResponse `json:"merge,omitempty"`
+	Err        string          `json:"error,omitempty"`
+}
+
+func (r *ReconcileResult) fail(err error) ReconcileResult {
+	r.Result = reconcileError
+	r.Err = err.Error()
+	metrics.Reconciled.Inc(r.Result)
+	return *r
+}
+
+// reconcileOptions changes how the pull requests are reconciled.
+type reconcileOptions struct {
+	// DryRun evaluates the pull requests without setting
+	// statuses, sending notifications or merging.
+	DryRun bool
+	// Force sets the status and sends the notifications even
+	// when the status is unchanged, and ignores the rate limit
+	// reserve of RECONCILE_RATE_RESERVE.
+	Force bool
+	// Stop ends the reconciliation before the next pull request
+	// when it is closed. The pull request in progress finishes.
+	Stop <-chan struct{}
+}
+
+// stopped reports whether the reconciliation should end
+// before the next pull request.
+func (opts reconcileOptions) stopped(c context.Context) bool {
+	select {
+	case <-opts.Stop:
+		return true
+	default:
+		return c.Err() != nil
+	}
+}
+
+// StartReconciler periodically re-evaluates the open pull requests
+// of the enabled repositories, so that statuses that are stale
+// because of missed webhooks are repaired and approved pull
+// requests are merged. The context must carry the store, remote
+// and cache. The reconciler stops when stop is closed, after the
+// pull requests in progress. The returned channel is closed when
+// the reconciler has stopped.
+func StartReconciler(c context.Context, stop <-chan struct{}, interval time.Duration, workers int) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 || workers <= 0 {
+		close(done)
+		return done
+	}
+	c = usage.AddEventToContext(c, "reconcile")
+	go func() {
+		defer close(done)
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+			case <-stop:
+				return
+			}
+			if reconcileElected(c, interval) {
+				reconcileRepos(c, stop, workers)
+			}
+			timer.Reset(interval)
+		}
+	}()
+	return done
+}
+
+// reconcileElected returns true when this instance holds the
+// reconciliation lease for the interval. Without database leases
+// every instance reconciles.
+func reconcileElected(c context.Context, interval time.Duration) bool {
+	if !envvars.Env.Lock.Lease {
+		return true
+	}
+	now := lockNow()
+	ok, err := store.AcquireLease(c, reconcileLeaseKey, lockOwner, now.Unix(), now.Add(interval).Unix())
+	if err != nil {
+		log.Warnf("Unable to acquire the reconciliation lease: %s", err)
+		return false
+	}
+	return ok
+}
+
+// reconcileRepos reconciles the enabled repositories with the
+// given number of workers. It returns early when stop is closed.
+func reconcileRepos(c context.Context, stop <-chan struct{}, workers int) {
+	start := time.Now()
+	repos, err := store.GetAllRepos(c)
+	if err != nil {
+		log.Warnf("Unable to list repositories for reconciliation: %s", err)
+		return
+	}
+	results := reconcileRepoList(c, repos, workers, reconcileOptions{Stop: stop})
+	var summary []string
+	for result, count := range summarizeReconcile(results) {
+		summary = append(summary, fmt.Sprintf("%d %s", count, result))
+	}
+	sort.Strings(summary)
+	log.Infof("Reconciled %d repositories in %s: %s", len(repos),
+		time.Since(start), strings.Join(summary, ", "))
+}
+
+// reconcileRepoList reconciles the repositories with the given
+// number of workers. The results are in the order of the
+// repositories.
+func reconcileRepoList(c context.Context, repos []*model.Repo, workers int, opts reconcileOptions) []ReconcileResult {
+	if workers <= 0 {
+		workers = 1
+	}
+	results := make([][]ReconcileResult, len(repos))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = reconcileRepo(c, repos[idx], opts)
+			}
+		}()
+	}
+loop:
+	for idx := range repos {
+		if opts.stopped(c) {
+			break loop
+		}
+		select {
+		case jobs <- idx:
+		case <-opts.Stop:
+			break loop
+		case <-c.Done():
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	var all []ReconcileResult
+	for _, r := range results {
+		all = append(all, r...)
+	}
+	return all
+}
+
+func summarizeReconcile(results []ReconcileResult) map[string]int {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Result]++
+	}
+	return counts
+}
+
+// reconcileBudget returns false when the remaining core rate limit
+// of the token is below RECONCILE_RATE_RESERVE. The reserve is left
+// for the webhooks.
+func reconcileBudget(user *model.User) bool {
+	state := remote.RateLimit(user)
+	if state == nil || state.Limit == 0 {
+		return true
+	}
+	if time.Unix(state.Reset, 0).Before(time.Now()) {
+		return true
+	}
+	return state.Remaining > envvars.Env.Reconcile.RateReserve
+}
+
+// reconcileRepo reconciles the open pull requests of the repository.
+func reconcileRepo(c context.Context, repo *model.Repo, opts reconcileOptions) []ReconcileResult {
+	params, err := GetHookParameters(c, HookCommon{Event: "reconcile"}, repo.Slug)
+	if err != nil {
+		log.Warnf("Unable to reconcile repository %s: %s", repo.Slug, err)
+		result := ReconcileResult{Repository: repo.Slug}
+		return []ReconcileResult{result.fail(err)}
+	}
+	if !opts.Force && !reconcileBudget(params.User) {
+		log.Infof("Skipping reconciliation of %s, the rate limit of %s is low", repo.Slug, params.User.Login)
+		metrics.Reconciled.Inc(reconcileSkipped)
+		return []ReconcileResult{{Repository: repo.Slug, Result: reconcileSkipped}}
+	}
+	prs, err := remote.ListOpenPullRequests(c, params.User, params.Repo)
+	if err != nil {
+		log.Warnf("Unable to list pull requests of %s: %s", repo.Slug, err)
+		result := ReconcileResult{Repository: repo.Slug}
+		return []ReconcileResult{result.fail(err)}
+	}
+	var results []ReconcileResult
+	for _, pr := range prs {
+		if opts.stopped(c) {
+			break
+		}
+		if !opts.Force && !reconcileBudget(params.User) {
+			metrics.Reconciled.Inc(reconcileSkipped)
+			results = append(results, ReconcileResult{Repository: repo.Slug, Result: reconcileSkipped})
+			break
+		}
+		pc, cancel := context.WithTimeout(c, reconcileTimeout)
+		results = append(results, reconcilePullRequest(pc, params, pr.Number, opts))
+		cancel()
+	}
+	return results
+}
+
+// reconcilePullRequest evaluates the pull request and sets its
+// status when it differs from the status on GitHub. A pull request
+// whose statuses are all successful is merged if the merge is
+// enabled, in case the status webhook that should have merged it
+// was missed.
+func reconcilePullRequest(c context.Context, params HookParams, number int, opts reconcileOptions) ReconcileResult {
+	user := params.User
+	repo := params.Repo
+	result := ReconcileResult{Repository: repo.Slug, Number: number}
+	lc, release, err := lockPullRequest(c, repo, number)
+	if err != nil {
+		return result.fail(err)
+	}
+	defer release()
+	// the work stops when the lease of the lock is lost
+	c = lc
+	info, err := approve(c, params, number, false)
+	if err != nil {
+		if opts.Force && !opts.DryRun {
+			notifier.SendErrorMessage(c, params.Config, "Unknown", number, repo.Slug, err.Error())
+		}
+		return result.fail(err)
+	}
+	pr := info.PullRequest
+	combined, err := remote.GetStatus(c, user, repo, pr.Branch.CompareSHA)
+	if err != nil {
+		return result.fail(err)
+	}
+	status, desc := generateStatus(info)
+	current := combined.Statuses[model.ServiceName]
+	result.Previous = current.State
+	result.Status = status
+	result.Result = reconcileUnchanged
+	changed := statusDiffers(current, status, desc)
+	if opts.DryRun {
+		if changed {
+			result.Result = reconcileStale
+		}
+		return result
+	}
+	if changed || opts.Force {
+		err = remote.SetStatus(c, user, repo, pr.Branch.CompareSHA, model.ServiceName, status, desc)
+		if err != nil {
+			return result.fail(err)
+		}
+		recordStats(info, repo, number)
+	}
+	if changed {
+		log.Infof("Repaired status of %s#%d from '%s' to '%s'", repo.Slug, number, current.State, status)
+		result.Result = reconcileRepaired
+	}
+	if opts.Force {
+		mw := reevaluateNotification(repo, pr, info)
+		if len(mw.Messages) > 0 {
+			setMessageDetails(mw, pr, info)
+			sendMessage(c, params.Config, mw)
+		}
+	}
+	if status == "success" && otherStatusesSucceeded(combined) && pr.Branch.Mergeable {
+		current.State = status
+		hook := &StatusHook{
+			HookCommon: HookCommon{Event: "status"},
+			SHA:        pr.Branch.CompareSHA,
+			Status:     &current,
+			Repo:       repo,
+		}
+		merge := hook.mergePullRequest(c, params, *pr)
+		result.Merge = &merge
+		if merge.SHA != "" {
+			log.Infof("Merged %s#%d during reconciliation", repo.Slug, number)
+			result.Result = reconcileMerged
+		}
+	}
+	metrics.Reconciled.Inc(result.Result)
+	return result
+}
+
+// reevaluateNotification builds the notification of the latest
+// approval or blocking feedback of the pull request, as it would
+// have been sent for the comment that caused it.
+func reevaluateNotification(repo *model.Repo, pr *model.PullRequest, info *ApprovalInfo) *notifier.MessageWrapper {
+	hook := &ApprovalHook{
+		HookCommon: HookCommon{Event: "reevaluate"},
+		Repo:       repo,
+		Issue:      &pr.Issue,
+	}
+	return handleApprovalNotification(hook, &info.CurCommentInfo)
+}
+
+// otherStatusesSucceeded returns true when the statuses of the
+// other services are successful.
+func otherStatusesSucceeded(combined model.CombinedStatus) bool {
+	for name, s := range combined.Statuses {
+		if name != model.ServiceName && s.State != "success" {
+			return false
+		}
+	}
+	return true
+}
+
+// statusDiffers compares the status on GitHub with the evaluated
+// status. GitHub truncates long descriptions.
+func statusDiffers(current model.CommitStatus, status, desc string) bool {
+	if current.State != status {
+		return true
+	}
+	return !strings.HasPrefix(desc, strings.TrimSuffix(current.Description, "..."))
+}
@@ -0,0 +1,119 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReevaluateNotification(t *testing.T) {
+	repo := &model.Repo{Owner: "octocat", Name: "hello", Slug: "octocat/hello"}
+	pr := &model.PullRequest{Issue: model.Issue{Number: 12, Title: "feature", Author: lowercase.Create("bob")}}
+	info := &ApprovalInfo{CurCommentInfo: CurCommentInfo{Status: CurCommentApproval, Author: "alice"}}
+	mw := reevaluateNotification(repo, pr, info)
+	if mw.PrNumber != 12 || mw.Slug != "octocat/hello" || len(mw.Messages) != 1 || mw.Messages[0].Type != model.CommentApprove {
+		t.Errorf("Expected an approval notification but was %v", mw)
+	}
+	info.Status = CurCommentNoChange
+	if mw = reevaluateNotification(repo, pr, info); len(mw.Messages) != 0 {
+		t.Errorf("Expected no notification but was %v", mw.Messages)
+	}
+}
+
+func TestStatusDiffers(t *testing.T) {
+	current := model.CommitStatus{State: "success", Description: "approved by alice"}
+	if statusDiffers(current, "success", "approved by alice") {
+		t.Error("Expected the same status")
+	}
+	if !statusDiffers(current, "pending", "approved by alice") {
+		t.Error("Expected a different state")
+	}
+	if !statusDiffers(current, "success", "approved by bob") {
+		t.Error("Expected a different description")
+	}
+	// GitHub truncates long descriptions
+	current.Description = "approved by alice,bo..."
+	if statusDiffers(current, "success", "approved by alice,bob,carol") {
+		t.Error("Expected the truncated description to match")
+	}
+}
+
+func TestReconcileRepoError(t *testing.T) {
+	c := context.WithValue(context.Background(), "store", &membershipStore{})
+	results := reconcileRepo(c, &model.Repo{Owner: "octocat", Name: "hello", Slug: "octocat/hello"}, reconcileOptions{})
+	if len(results) != 1 || results[0].Result != reconcileError || results[0].Repository != "octocat/hello" {
+		t.Errorf("Expected an error for the repository but was %v", results)
+	}
+}
+
+func TestStartReconcilerStops(t *testing.T) {
+	select {
+	case <-StartReconciler(context.Background(), nil, 0, 2):
+	case <-time.After(time.Second):
+		t.Error("Expected a disabled reconciler to stop")
+	}
+	stop := make(chan struct{})
+	done := StartReconciler(context.Background(), stop, time.Hour, 2)
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected the reconciler to stop when stop is closed")
+	}
+}
+
+func TestReevaluateOptions(t *testing.T) {
+	for query, expected := range map[string]bool{"": false, "?dry_run=true": true, "?dry_run=0": false} {
+		gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+		gc.Request = httptest.NewRequest("POST", "/admin/reevaluate/octocat"+query, nil)
+		opts, ok := reevaluateOptions(gc)
+		if !ok || opts.DryRun != expected || !opts.Force {
+			t.Errorf("Unexpected options %v for %s", opts, query)
+		}
+	}
+	w := httptest.NewRecorder()
+	gc, _ := gin.CreateTestContext(w)
+	gc.Request = httptest.NewRequest("POST", "/admin/reevaluate/octocat?dry_run=maybe", nil)
+	if _, ok := reevaluateOptions(gc); ok || w.Code != 400 {
+		t.Errorf("Expected a bad request for an invalid dry_run but was %d", w.Code)
+	}
+}
+
+func TestReevaluateReport(t *testing.T) {
+	results := []ReconcileResult{
+		{Repository: "octocat/hello", Number: 1, Result: reconcileStale},
+		{Repository: "octocat/hello", Number: 2, Result: reconcileUnchanged},
+		{Repository: "octocat/world", Result: reconcileError, Err: "not found"},
+	}
+	report := reevaluateReport(reconcileOptions{DryRun: true}, 2, results)
+	if !report.DryRun || report.Repositories != 2 || report.PullRequests != 2 {
+		t.Errorf("Unexpected report %v", report)
+	}
+	if report.Summary[reconcileStale] != 1 || report.Summary[reconcileUnchanged] != 1 || report.Summary[reconcileError] != 1 {
+		t.Errorf("Unexpected summary %v", report.Summary)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/hashicorp/go-version"
+)
+
+func releaseIfEnabled(c context.Context, user *model.User, hook *StatusHook,
+	req *model.ApprovalRequest, policy *model.ApprovalPolicy, tag string) (string, error) {
+
+	var tagConfig *model.TagConfig
+
+	if policy.Tag == nil {
+		tagConfig = &req.Config.Tag
+	} else {
+		tagConfig = policy.Tag
+	}
+
+	if tagConfig.Release.Enable {
+		return doRelease(c, user, hook, req, tagConfig, tag)
+	}
+	return "", nil
+}
+
+func doRelease(c context.Context, user *model.User, hook *StatusHook,
+	req *model.ApprovalRequest, tagConfig *model.TagConfig, tag string) (string, error) {
+	releaseConfig := &tagConfig.Release
+	tags, err := remote.ListTags(c, user, hook.Repo)
+	if err != nil {
+		return "", err
+	}
+	current := strings.TrimPrefix(tag, tagConfig.Prefix)
+	previous := getPreviousTag(filterTags(tags, tagConfig.Prefix), current)
+	if len(previous) > 0 {
+		previous = tagConfig.Prefix + previous
+	}
+	prs, err := remote.ListMergedPullRequests(c, user, hook.Repo,
+		req.PullRequest.Branch.BaseName, previous, tag)
+	if err != nil {
+		return "", err
+	}
+	notes, err := releaseConfig.GenerateNotes(tag, previous, prs)
+	if err != nil {
+		return "", err
+	}
+	release := model.Release{
+		Tag:        tag,
+		Name:       tag,
+		Body:       notes,
+		Draft:      releaseConfig.Draft,
+		Prerelease: releaseConfig.Prerelease || isPrerelease(current),
+	}
+	log.Debugf("Creating release for tag %s since %s", tag, previous)
+	return remote.CreateRelease(c, user, hook.Repo, release)
+}
+
+func isPrerelease(tag string) bool {
+	ver, err := version.NewVersion(tag)
+	return err == nil && ver.Prerelease() != ""
+}
+
+func tagMessage(tag, release string) notifier.MessageInfo {
+	return notifier.NewMessage(model.CommentTag, model.MessageData{
+		Tag:     tag,
+		Release: release,
+	})
+}
+
+// getPreviousTag returns the highest semantic version tag
+// that is lower than the current tag. If the current tag is not
+// a semantic version then the highest semantic version tag is returned.
+// If no version is found, the function returns an empty string.
+func getPreviousTag(tags []model.Tag, current string) string {
+	curVer, curErr := version.NewVersion(current)
+	var maxVer *version.Version
+	var maxTag string
+	for _, v := range tags {
+		if string(v) == current {
+			continue
+		}
+		ver, err := version.NewVersion(string(v))
+		if err != nil {
+			continue
+		}
+		if curErr == nil && !ver.LessThan(curVer) {
+			continue
+		}
+		if maxVer == nil || ver.GreaterThan(maxVer) {
+			maxVer = ver
+			maxTag = string(v)
+		}
+	}
+	return maxTag
+}
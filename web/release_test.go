@@ -0,0 +1,102 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"context"
+	"testing"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/strings/lowercase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPreviousTag(t *testing.T) {
+	tags := []model.Tag{"a", "0.0.1", "0.1.0", "0.1.1", "1.0.0"}
+	assert.Equal(t, "0.1.0", getPreviousTag(tags, "0.1.1"))
+	assert.Equal(t, "1.0.0", getPreviousTag(tags, "1.0.1"))
+	assert.Equal(t, "1.0.0", getPreviousTag(tags, "2017-10-10T10.10.10Z"))
+	assert.Equal(t, "", getPreviousTag(tags, "0.0.1"))
+	assert.Equal(t, "", getPreviousTag(nil, "0.0.1"))
+}
+
+type releaseR struct {
+	remote.Remote
+	base, from, to string
+	release        model.Release
+}
+
+func (m *releaseR) ListTags(c context.Context, u *model.User, r *model.Repo) ([]model.Tag, error) {
+	return []model.Tag{"0.0.1", "0.1.0", "0.1.1"}, nil
+}
+
+func (m *releaseR) ListMergedPullRequests(c context.Context, u *model.User, r *model.Repo, base, from, to string) ([]model.PullRequest, error) {
+	m.base, m.from, m.to = base, from, to
+	return []model.PullRequest{
+		{Issue: model.Issue{Number: 7, Title: "fix: the thing", Author: lowercase.Create("test_guy")}},
+	}, nil
+}
+
+func (m *releaseR) CreateRelease(c context.Context, u *model.User, r *model.Repo, release model.Release) (string, error) {
+	m.release = release
+	return "https://github.com/test_guy/test_repo/releases/tag/0.1.1", nil
+}
+
+func TestDoRelease(t *testing.T) {
+	c := &gin.Context{}
+	mock := &releaseR{}
+	remote.ToContext(c, mock)
+	config := model.NonEmptyConfig()
+	config.Tag.Enable = true
+	config.Tag.Release.Enable = true
+	config.Tag.Release.Prerelease = true
+	hook := &StatusHook{
+		Repo: &model.Repo{
+			Owner: "test_guy",
+			Name:  "test_repo",
+		},
+	}
+	request := &model.ApprovalRequest{
+		Config: config,
+		PullRequest: &model.PullRequest{
+			Branch: model.Branch{BaseName: "master"},
+		},
+	}
+	url, err := releaseIfEnabled(c, &model.User{}, hook, request, model.DefaultApprovalPolicy(), "0.1.1")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://github.com/test_guy/test_repo/releases/tag/0.1.1", url)
+	assert.Equal(t, "master", mock.base)
+	assert.Equal(t, "0.1.0", mock.from)
+	assert.Equal(t, "0.1.1", mock.to)
+	assert.Equal(t, model.Release{
+		Tag:        "0.1.1",
+		Name:       "0.1.1",
+		Body:       "## Bug Fixes\n\n* fix: the thing (#7) @test_guy\n\n",
+		Prerelease: true,
+	}, mock.release)
+	assert.Equal(t, "Tag 0.1.1 has been added with release "+url, tagMessage("0.1.1", url).Message)
+
+	config.Tag.Release.Enable = false
+	url, err = releaseIfEnabled(c, &model.User{}, hook, request, model.DefaultApprovalPolicy(), "0.1.2")
+	assert.Nil(t, err)
+	assert.Equal(t, "", url)
+}
@@ -24,9 +24,12 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/capitalone/checks-out/envvars"
 	"github.com/capitalone/checks-out/exterror"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/remote"
+
+	log "github.com/Sirupsen/logrus"
 )
 
 func getPullRequestFiles(c context.Context, user *model.User, repo *model.Repo, num int) ([]model.CommitFile, error) {
@@ -49,10 +52,27 @@ func getPullRequestCommits(c context.Context, user *model.User, repo *model.Repo
 	return commits, nil
 }
 
+// loadPullRequestData returns the evaluation data of the pull
+// request loaded with GraphQL. It returns nil when GraphQL is
+// disabled or the query fails and the data must be loaded with
+// the REST API.
+func loadPullRequestData(c context.Context, user *model.User, repo *model.Repo, num int, noUIMerge bool) *model.PullRequestData {
+	if !envvars.Env.Github.GraphQL {
+		return nil
+	}
+	data, err := remote.LoadPullRequestData(c, user, repo, num, noUIMerge)
+	if err != nil {
+		log.Warnf("Unable to load %s pr %d with GraphQL, falling back to REST: %s", repo.Slug, num, err)
+		return nil
+	}
+	return data
+}
+
 func getFeedback(c context.Context,
 	user *model.User,
 	request *model.ApprovalRequest,
 	policy *model.ApprovalPolicy,
+	data *model.PullRequestData,
 	crange model.CommitRange,
 	noUIMerge bool) ([]model.Feedback, error) {
 	repo := request.Repository
@@ -60,6 +80,9 @@ func getFeedback(c context.Context,
 	var feedback []model.Feedback
 	var err error
 	fbConfig := request.Config.GetFeedbackConfig(policy)
+	if data != nil {
+		return getDataFeedback(data, crange, fbConfig.Types)
+	}
 	switch crange {
 	case model.All:
 		feedback, err = getAllFeedback(c, user, repo, pr.Number, fbConfig.Types)
@@ -100,6 +123,32 @@ func sortFeedback(feedback []model.Feedback) {
 	})
 }
 
+func getDataFeedback(data *model.PullRequestData, crange model.CommitRange, types []model.FeedbackType) ([]model.Feedback, error) {
+	var feedback []model.Feedback
+	comments, reviews := data.Comments, data.Reviews
+	switch crange {
+	case model.All:
+	case model.Head:
+		comments, reviews = data.HeadComments, data.HeadReviews
+	default:
+		return nil, fmt.Errorf("Unknown commit range '%s' in configuration",
+			crange.String())
+	}
+	if hasFeedbackType(types, model.CommentType) {
+		for _, c := range comments {
+			feedback = append(feedback, c)
+		}
+	}
+	if hasFeedbackType(types, model.ReviewType) {
+		for _, r := range reviews {
+			feedback = append(feedback, r)
+		}
+	}
+	feedback = filterFeedback(feedback)
+	sortFeedback(feedback)
+	return feedback, nil
+}
+
 func getAllFeedback(c context.Context, user *model.User, repo *model.Repo, num int, types []model.FeedbackType) ([]model.Feedback, error) {
 	var feedback []model.Feedback
 	var comments []*model.Comment
@@ -0,0 +1,60 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"testing"
+
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/strings/lowercase"
+)
+
+func TestFilterFeedbackSticky(t *testing.T) {
+	feedback := []model.Feedback{
+		&model.Comment{Author: lowercase.Create("alice"), Body: "I approve"},
+		&model.Comment{Author: lowercase.Create("bot"), Body: model.CommentPrefix + " " + model.StickyMarker + "\n#### Status\n\nI approve"},
+		&model.Comment{Author: lowercase.Create("bot"), Body: model.CommentPrefix + " merged"},
+	}
+	filtered := filterFeedback(feedback)
+	if len(filtered) != 1 || filtered[0].GetBody() != "I approve" {
+		t.Errorf("Expected only the comment by alice but was %v", filtered)
+	}
+}
+
+func TestGetDataFeedback(t *testing.T) {
+	comment := &model.Comment{Author: lowercase.Create("alice"), Body: "I approve"}
+	review := &model.Review{Author: lowercase.Create("bob"), State: lowercase.Create("approved")}
+	data := &model.PullRequestData{
+		Comments:     []*model.Comment{comment, {Author: lowercase.Create("bot"), Body: model.CommentPrefix + " merged"}},
+		Reviews:      []*model.Review{review},
+		HeadComments: []*model.Comment{comment},
+	}
+	all, err := getDataFeedback(data, model.All, []model.FeedbackType{model.CommentType, model.ReviewType})
+	if err != nil || len(all) != 2 {
+		t.Errorf("Expected the comment and the review but was %v %v", all, err)
+	}
+	head, err := getDataFeedback(data, model.Head, []model.FeedbackType{model.ReviewType})
+	if err != nil || len(head) != 0 {
+		t.Errorf("Expected no reviews since the head but was %v %v", head, err)
+	}
+	head, err = getDataFeedback(data, model.Head, []model.FeedbackType{model.CommentType})
+	if err != nil || len(head) != 1 || head[0] != comment {
+		t.Errorf("Expected the comment since the head but was %v %v", head, err)
+	}
+}
@@ -0,0 +1,388 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/capitalone/checks-out/cache"
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/exterror"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/notifier/slack"
+	"github.com/capitalone/checks-out/remote"
+	"github.com/capitalone/checks-out/router/middleware/session"
+	"github.com/capitalone/checks-out/shared/httputil"
+	"github.com/capitalone/checks-out/shared/token"
+	"github.com/capitalone/checks-out/store"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	slackLinkToken = "slack-link"
+	// Slack requests older than this are rejected to prevent replays
+	slackMaxRequestAge = 5 * time.Minute
+	slackLinkExpires   = 15 * time.Minute
+	slackHelp          = "Usage:\n" +
+		"`status owner/repo#123` shows the approval status of a pull request\n" +
+		"`link` links your Slack account to your GitHub account\n" +
+		"`unlink` removes the link to your GitHub account"
+)
+
+var (
+	slackNow         = time.Now
+	slackHttpClient  = &http.Client{Timeout: 30 * time.Second}
+	slackPullRequest = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+)
+
+type slackResponse struct {
+	ResponseType    string `json:"response_type,omitempty"`
+	ReplaceOriginal bool   `json:"replace_original"`
+	Text            string `json:"text"`
+}
+
+func ephemeral(text string) slackResponse {
+	return slackResponse{ResponseType: "ephemeral", Text: text}
+}
+
+// SlackCommand answers the checks-out slash command of Slack.
+func SlackCommand(c *gin.Context) {
+	err := verifySlackRequest(c.Request)
+	if err != nil {
+		log.Warnf("Rejected Slack command: %v", err)
+		c.String(401, err.Error())
+		return
+	}
+	var (
+		team        = c.PostForm("team_id")
+		user        = c.PostForm("user_id")
+		responseURL = c.PostForm("response_url")
+		fields      = strings.Fields(c.PostForm("text"))
+	)
+	var cmd string
+	if len(fields) > 0 {
+		cmd = fields[0]
+	}
+	switch {
+	case cmd == "status" && len(fields) == 2:
+		reply, _ := startSlackStatus(c, team, user, fields[1], responseURL, false)
+		c.JSON(200, ephemeral(reply))
+	case cmd == "link":
+		c.JSON(200, ephemeral(slackLinkMessage(c, team, user)))
+	case cmd == "unlink":
+		err = store.DeleteSlackLink(c, team, user)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(200, ephemeral("Your Slack account is no longer linked to GitHub."))
+	default:
+		c.JSON(200, ephemeral(slackHelp))
+	}
+}
+
+// SlackAction handles the buttons of the interactive Slack messages.
+// The result is posted to the response url of the action.
+func SlackAction(c *gin.Context) {
+	err := verifySlackRequest(c.Request)
+	if err != nil {
+		log.Warnf("Rejected Slack action: %v", err)
+		c.String(401, err.Error())
+		return
+	}
+	var payload struct {
+		Type string `json:"type"`
+		User struct {
+			ID     string `json:"id"`
+			TeamID string `json:"team_id"`
+		} `json:"user"`
+		ResponseURL string `json:"response_url"`
+		Actions     []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	err = json.Unmarshal([]byte(c.PostForm("payload")), &payload)
+	if err != nil {
+		c.String(400, "Unable to parse Slack payload. %s", err)
+		return
+	}
+	for _, action := range payload.Actions {
+		if action.ActionID != slack.RecheckAction {
+			continue
+		}
+		reply, started := startSlackStatus(c, payload.User.TeamID, payload.User.ID, action.Value, payload.ResponseURL, true)
+		if !started {
+			go postSlackResponse(payload.ResponseURL, reply)
+		}
+	}
+	c.Status(200)
+}
+
+// SlackLink shows the page where the current user confirms that
+// the Slack user of the token is linked to their GitHub account.
+// The link is only created by the confirmation, which is posted
+// with the CSRF token of the session.
+func SlackLink(c *gin.Context) {
+	user := session.User(c)
+	raw := c.Query("token")
+	ids, err := parseSlackLinkToken(raw)
+	if err != nil {
+		c.String(400, "Invalid or expired Slack link. Run the link command again. %s", err)
+		return
+	}
+	csrf, err := token.New(token.CsrfToken, user.Login).Sign(user.Secret)
+	if err != nil {
+		c.Error(exterror.Create(http.StatusInternalServerError, err))
+		return
+	}
+	c.HTML(200, "slack_link.html", gin.H{
+		"user":  user,
+		"team":  ids[0],
+		"slack": ids[1],
+		"token": raw,
+		"csrf":  csrf,
+	})
+}
+
+// SlackLinkConfirm links the Slack user of the token
+// to the GitHub account of the current user.
+func SlackLinkConfirm(c *gin.Context) {
+	user := session.User(c)
+	ids, err := parseSlackLinkToken(c.PostForm("token"))
+	if err != nil {
+		c.String(400, "Invalid or expired Slack link. Run the link command again. %s", err)
+		return
+	}
+	err = store.CreateSlackLink(c, &model.SlackLink{
+		Team:    ids[0],
+		User:    ids[1],
+		Login:   user.Login,
+		Created: slackNow().Unix(),
+	})
+	if err != nil {
+		c.Error(exterror.Create(http.StatusInternalServerError, err))
+		return
+	}
+	c.String(200, "Your Slack account is linked to GitHub user %s", user.Login)
+}
+
+// parseSlackLinkToken returns the Slack team and user of a link token.
+func parseSlackLinkToken(raw string) ([]string, error) {
+	t, err := token.Parse(raw, func(t *token.Token) (string, error) {
+		if t.Kind != slackLinkToken {
+			return "", errors.New("Invalid token type")
+		}
+		if envvars.Env.Slack.LinkSecret == "" {
+			return "", errors.New("SLACK_LINK_SECRET is not configured")
+		}
+		return envvars.Env.Slack.LinkSecret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := strings.SplitN(t.Text, ":", 2)
+	if len(ids) != 2 {
+		return nil, errors.New("Invalid Slack link")
+	}
+	return ids, nil
+}
+
+// verifySlackRequest checks the signature of a request from Slack
+// with the signing secret. The body of the request is restored so
+// that the form values can be parsed.
+func verifySlackRequest(r *http.Request) error {
+	secret := envvars.Env.Slack.SigningSecret
+	if secret == "" {
+		return errors.New("SLACK_SIGNING_SECRET is not configured")
+	}
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid Slack timestamp %q", ts)
+	}
+	age := slackNow().Sub(time.Unix(sec, 0))
+	if age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return errors.New("Slack request is too old")
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Slack-Signature"))) {
+		return errors.New("Invalid Slack signature")
+	}
+	return nil
+}
+
+// parseSlackPullRequest parses a pull request in the owner/repo#123 format.
+func parseSlackPullRequest(ref string) (string, string, int, error) {
+	m := slackPullRequest.FindStringSubmatch(ref)
+	if m == nil {
+		return "", "", 0, fmt.Errorf("Expected a pull request as owner/repo#123 but was `%s`", ref)
+	}
+	num, err := strconv.Atoi(m[3])
+	if err != nil {
+		return "", "", 0, err
+	}
+	return m[1], m[2], num, nil
+}
+
+// startSlackStatus evaluates the approval status of the pull request in the
+// background and posts the result to the response url. Slack expects an answer
+// within three seconds. It returns the reply to the Slack user and whether
+// the evaluation was started.
+func startSlackStatus(c *gin.Context, team, user, ref, responseURL string, recheck bool) (string, bool) {
+	owner, name, num, err := parseSlackPullRequest(ref)
+	if err != nil {
+		return err.Error(), false
+	}
+	link, err := store.GetSlackLink(c, team, user)
+	if err != nil {
+		if exterror.Convert(err).Status == http.StatusNotFound {
+			return "Your Slack account is not linked to GitHub. Use the `link` command first.", false
+		}
+		log.Warnf("Unable to get Slack link of %s: %v", user, err)
+		return "Unable to get your GitHub account.", false
+	}
+	ctx := detach(c)
+	go func() {
+		text, err := slackStatus(ctx, link.Login, owner, name, num, recheck)
+		if err != nil {
+			log.Warnf("Unable to evaluate %s for Slack user %s: %v", ref, user, err)
+			text = fmt.Sprintf("Unable to evaluate %s. %s", ref, err)
+		}
+		postSlackResponse(responseURL, text)
+	}()
+	return fmt.Sprintf("Checking %s...", ref), true
+}
+
+// detach returns a context with the store, remote and cache of the
+// request that remains usable after the request is finished.
+func detach(c context.Context) context.Context {
+	ctx := store.AddToContext(context.Background(), store.FromContext(c))
+	ctx = remote.AddToContext(ctx, remote.FromContext(c))
+	return cache.AddToContext(ctx, cache.FromContext(c))
+}
+
+// slackStatus evaluates the pull request on behalf of the GitHub user. The
+// user must have read access to the repository. A recheck also updates the
+// status of the pull request.
+func slackStatus(c context.Context, login, owner, name string, num int, recheck bool) (string, error) {
+	user, err := store.GetUserLogin(c, login)
+	if err != nil {
+		return "", fmt.Errorf("GitHub user %s is not registered with %s", login, envvars.Env.Branding.ShortName)
+	}
+	perm, err := remote.GetPerm(c, user, owner, name)
+	if err != nil {
+		return "", err
+	}
+	if !perm.Pull {
+		return "", fmt.Errorf("GitHub user %s does not have access to %s/%s", login, owner, name)
+	}
+	params, err := GetHookParametersBasic(c, owner+"/"+name)
+	if err != nil {
+		return "", err
+	}
+	approval, err := approve(c, params, num, recheck)
+	if err != nil {
+		return "", err
+	}
+	return formatSlackStatus(params.Repo.Slug, num, approval), nil
+}
+
+func formatSlackStatus(slug string, num int, approval *ApprovalInfo) string {
+	status, desc := generateStatus(approval)
+	lines := []string{
+		fmt.Sprintf("<%s/%s/pull/%d|%s#%d> is *%s*: %s",
+			envvars.Env.Github.Url, slug, num, slug, num, status, desc),
+	}
+	if approval.Policy != nil && approval.Policy.Name != "" {
+		lines = append(lines, fmt.Sprintf("Policy: %s", approval.Policy.Name))
+	}
+	if len(approval.Approvers) > 0 {
+		lines = append(lines, "Approved by: "+approval.Approvers.Print(", "))
+	}
+	if len(approval.Disapprovers) > 0 {
+		lines = append(lines, "Blocked by: "+approval.Disapprovers.Print(", "))
+	}
+	if !approval.Approved && len(approval.Needed) > 0 {
+		var logins []string
+		for _, p := range approval.Needed {
+			logins = append(logins, p.Login)
+		}
+		lines = append(lines, "Can still approve: "+strings.Join(logins, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// slackLinkMessage returns a link that connects the Slack user to the
+// GitHub account that is logged in to checks-out.
+func slackLinkMessage(c *gin.Context, team, user string) string {
+	if envvars.Env.Slack.LinkSecret == "" {
+		return "Linking Slack accounts is not configured."
+	}
+	t := token.New(slackLinkToken, team+":"+user)
+	signed, err := t.SignExpires(envvars.Env.Slack.LinkSecret, slackNow().Add(slackLinkExpires).Unix())
+	if err != nil {
+		log.Warnf("Unable to sign Slack link: %v", err)
+		return "Unable to create a link to GitHub."
+	}
+	return fmt.Sprintf("<%s/slack/link?token=%s|Link your GitHub account>. The link expires in %d minutes.",
+		httputil.GetURL(c.Request), signed, int(slackLinkExpires.Minutes()))
+}
+
+func postSlackResponse(url string, text string) {
+	if url == "" {
+		return
+	}
+	body, err := json.Marshal(ephemeral(text))
+	if err != nil {
+		log.Warnf("Unable to convert Slack response to JSON: %v", err)
+		return
+	}
+	resp, err := slackHttpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Warnf("Unable to post Slack response: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Warnf("Slack response returned %s", resp.Status)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+
+SPDX-Copyright: Copyright (c) Capital One Services, LLC
+SPDX-License-Identifier: Apache-2.0
+Copyright 2017 Capital One Services, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and limitations under the License.
+
+*/
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/model"
+	"github.com/capitalone/checks-out/set"
+	"github.com/capitalone/checks-out/shared/token"
+	"github.com/capitalone/checks-out/store"
+	"github.com/capitalone/checks-out/web/template"
+
+	"github.com/gin-gonic/gin"
+)
+
+func signedSlackRequest(secret string, ts time.Time, body string) *http.Request {
+	req := httptest.NewRequest("POST", "/slack/command", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", stamp, body)
+	req.Header.Set("X-Slack-Request-Timestamp", stamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func withSlackSecret(t *testing.T) {
+	envvars.Env.Slack.SigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+	now := time.Unix(1531420618, 0)
+	slackNow = func() time.Time { return now }
+	t.Cleanup(func() {
+		envvars.Env.Slack.SigningSecret = ""
+		slackNow = time.Now
+	})
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	withSlackSecret(t)
+	secret := envvars.Env.Slack.SigningSecret
+	body := "command=%2Fchecks-out&text=help"
+
+	req := signedSlackRequest(secret, slackNow(), body)
+	if err := verifySlackRequest(req); err != nil {
+		t.Fatalf("Expected valid request but was %v", err)
+	}
+	req.ParseForm()
+	if req.PostForm.Get("text") != "help" {
+		t.Errorf("Expected body to be restored but was %v", req.PostForm)
+	}
+
+	req = signedSlackRequest("wrong", slackNow(), body)
+	if err := verifySlackRequest(req); err == nil {
+		t.Error("Expected invalid signature to be rejected")
+	}
+
+	req = signedSlackRequest(secret, slackNow().Add(-10*time.Minute), body)
+	if err := verifySlackRequest(req); err == nil {
+		t.Error("Expected old request to be rejected")
+	}
+
+	envvars.Env.Slack.SigningSecret = ""
+	req = signedSlackRequest("", slackNow(), body)
+	if err := verifySlackRequest(req); err == nil {
+		t.Error("Expected request to be rejected without a signing secret")
+	}
+}
+
+func TestParseSlackPullRequest(t *testing.T) {
+	owner, name, num, err := parseSlackPullRequest("octocat/hello-world#42")
+	if err != nil || owner != "octocat" || name != "hello-world" || num != 42 {
+		t.Errorf("Unexpected result %s %s %d %v", owner, name, num, err)
+	}
+	for _, ref := range []string{"octocat/hello-world", "hello-world#42", "octocat/hello-world#abc"} {
+		if _, _, _, err := parseSlackPullRequest(ref); err == nil {
+			t.Errorf("Expected %s to be invalid", ref)
+		}
+	}
+}
+
+func TestSlackCommandHelp(t *testing.T) {
+	withSlackSecret(t)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = signedSlackRequest(envvars.Env.Slack.SigningSecret, slackNow(), "team_id=T1&user_id=U1&text=")
+	SlackCommand(c)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "Usage") {
+		t.Errorf("Expected usage but was %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = signedSlackRequest("wrong", slackNow(), "team_id=T1&user_id=U1&text=")
+	SlackCommand(c)
+	if w.Code != 401 {
+		t.Errorf("Expected unsigned command to be rejected but was %d", w.Code)
+	}
+}
+
+func withSlackLinkSecret(t *testing.T) {
+	envvars.Env.Slack.LinkSecret = "d1a9c0e5b7f24c38a6e0b3f1c2d4e5f6"
+	t.Cleanup(func() {
+		envvars.Env.Slack.LinkSecret = ""
+	})
+}
+
+func TestSlackLinkToken(t *testing.T) {
+	withSlackSecret(t)
+	withSlackLinkSecret(t)
+	slackNow = time.Now
+	c := &gin.Context{Request: httptest.NewRequest("POST", "http://checks-out.example.com/slack/command", nil)}
+	msg := slackLinkMessage(c, "T1", "U1")
+	start := strings.Index(msg, "token=")
+	end := strings.Index(msg, "|")
+	if !strings.HasPrefix(msg, "<http://checks-out.example.com/slack/link?token=") || start < 0 || end < start {
+		t.Fatalf("Unexpected link message %s", msg)
+	}
+	raw, _ := url.QueryUnescape(msg[start+len("token=") : end])
+	ids, err := parseSlackLinkToken(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids[0] != "T1" || ids[1] != "U1" {
+		t.Errorf("Unexpected token %v", ids)
+	}
+	_, err = token.Parse(raw, func(t *token.Token) (string, error) {
+		return envvars.Env.Slack.SigningSecret, nil
+	})
+	if err == nil {
+		t.Error("Slack link should not be signed with the Slack signing secret")
+	}
+
+	envvars.Env.Slack.LinkSecret = ""
+	if _, err = parseSlackLinkToken(raw); err == nil {
+		t.Error("Expected link to be rejected without a link secret")
+	}
+	if msg = slackLinkMessage(c, "T1", "U1"); strings.Contains(msg, "token=") {
+		t.Errorf("Expected no link without a link secret but was %s", msg)
+	}
+}
+
+type slackLinkStore struct {
+	store.Store
+	links []*model.SlackLink
+}
+
+func (ss *slackLinkStore) CreateSlackLink(link *model.SlackLink) error {
+	ss.links = append(ss.links, link)
+	return nil
+}
+
+func TestSlackLinkConfirm(t *testing.T) {
+	withSlackLinkSecret(t)
+	signed, _ := token.New(slackLinkToken, "T1:U1").SignExpires(envvars.Env.Slack.LinkSecret, time.Now().Add(time.Minute).Unix())
+	ss := &slackLinkStore{}
+	user := &model.User{Login: "octocat", Secret: "s3cret"}
+
+	// the link is only shown for confirmation
+	w := httptest.NewRecorder()
+	c, e := gin.CreateTestContext(w)
+	e.SetHTMLTemplate(template.Template())
+	store.ToContext(c, ss)
+	c.Set("user", user)
+	c.Request = httptest.NewRequest("GET", "/slack/link?token="+signed, nil)
+	SlackLink(c)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "X-CSRF-TOKEN") {
+		t.Errorf("Expected confirmation page but was %d %s", w.Code, w.Body.String())
+	}
+	if len(ss.links) != 0 {
+		t.Fatalf("Slack user should not be linked without confirmation %v", ss.links)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	store.ToContext(c, ss)
+	c.Set("user", user)
+	c.Request = httptest.NewRequest("POST", "/slack/link", strings.NewReader("token="+signed))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	SlackLinkConfirm(c)
+	if w.Code != 200 || len(ss.links) != 1 {
+		t.Fatalf("Expected Slack user to be linked but was %d %v", w.Code, ss.links)
+	}
+	if link := ss.links[0]; link.Team != "T1" || link.User != "U1" || link.Login != "octocat" {
+		t.Errorf("Unexpected link %+v", link)
+	}
+}
+
+func TestFormatSlackStatus(t *testing.T) {
+	envvars.Env.Github.Url = "https://github.com"
+	approval := &ApprovalInfo{
+		Policy:         &model.ApprovalPolicy{Name: "core"},
+		AuthorApproved: true,
+		AuthorAffirmed: true,
+		TitleApproved:  true,
+		AuditApproved:  true,
+		Approvers:      set.New("alice"),
+		Disapprovers:   set.Empty(),
+		Needed:         []*model.Person{{Login: "bob"}, {Login: "carol"}},
+	}
+	actual := formatSlackStatus("octocat/hello-world", 42, approval)
+	expected := "<https://github.com/octocat/hello-world/pull/42|octocat/hello-world#42> is *pending*: " +
+		"more approvals needed. " + envvars.Env.Branding.ShortName + ": alice\n" +
+		"Policy: core\n" +
+		"Approved by: alice\n" +
+		"Can still approve: bob, carol"
+	if actual != expected {
+		t.Errorf("Expected\n%s\nbut was\n%s", expected, actual)
+	}
+}
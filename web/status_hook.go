@@ -23,16 +23,18 @@ import (
 	"fmt"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/notifier"
 	"github.com/capitalone/checks-out/remote"
 )
 
 type StatusResponse struct {
-	SHA  string `json:"sha,omitempty"`
-	Tag  string `json:"tag,omitempty"`
-	Err  string `json:"error,omitempty"`
-	Info string `json:"info,omitempty"`
+	SHA     string `json:"sha,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+	Release string `json:"release,omitempty"`
+	Err     string `json:"error,omitempty"`
+	Info    string `json:"info,omitempty"`
 }
 
 func generateError(msg string, err error, v model.PullRequest, slug string,
@@ -55,7 +57,6 @@ func (hook *StatusHook) Process(c context.Context) (interface{}, error) {
 	repo := params.Repo
 	user := params.User
 	config := params.Config
-	maintainer := params.Snapshot
 
 	merged := map[string]StatusResponse{}
 
@@ -70,138 +71,184 @@ func (hook *StatusHook) Process(c context.Context) (interface{}, error) {
 
 	//check the statuses of all of the checks on the branches for this commit
 	for _, v := range pullRequests {
-		mw := &notifier.MessageWrapper{
-			MessageHeader: notifier.MessageHeader{
-				PrName:   v.Issue.Title,
-				PrNumber: v.Issue.Number,
-				Slug:     hook.Repo.Slug,
-			},
-		}
-
 		id := fmt.Sprintf("%d", v.Number)
 		//if all of the statuses are success, then merge and create a tag for the version
 		if v.Branch.Mergeable {
-			result := StatusResponse{}
-
-			files, err := getPullRequestFiles(c, user, hook.Repo, v.Number)
+			lc, release, err := lockPullRequest(c, hook.Repo, v.Number)
 			if err != nil {
-				generateError("Unable to get pull request files", err, v, hook.Repo.Slug, &result, mw)
-				merged[id] = result
-				sendMessage(c, config, mw)
+				merged[id] = StatusResponse{Err: err.Error()}
 				continue
 			}
+			merged[id] = hook.mergePullRequest(lc, params, v)
+			release()
+		}
+	}
+	log.Debugf("processed status for %s. received %v ", repo.Slug, hook)
 
-			req := &model.ApprovalRequest{
-				Config:      config,
-				Maintainer:  maintainer,
-				PullRequest: &v,
-				Repository:  repo,
-				Files:       files,
-			}
+	return merged, nil
+}
 
-			policy := model.FindApprovalPolicy(req)
-			mergeConfig := req.Config.GetMergeConfig(policy)
+// mergePullRequest merges the pull request if it is approved and
+// creates the tag, release and deployments. The caller must hold
+// the lock of the pull request and pass the context of the lock.
+func (hook *StatusHook) mergePullRequest(c context.Context, params HookParams, v model.PullRequest) StatusResponse {
+	repo := params.Repo
+	user := params.User
+	config := params.Config
+	maintainer := params.Snapshot
+	result := StatusResponse{}
 
-			if !mergeConfig.Enable {
-				result.Info = "merge config not enabled"
-				merged[id] = result
-				sendMessage(c, config, mw)
-				continue
-			}
+	// another event may have merged or updated the pull request
+	// while waiting for the lock
+	latest, err := remote.GetPullRequest(c, user, hook.Repo, v.Number)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	if latest.Branch.Merged {
+		result.Info = "pull request is already merged"
+		return result
+	}
+	if latest.Branch.CompareSHA != hook.SHA {
+		result.Info = "pull request has new commits"
+		return result
+	}
+	v = latest
+
+	mw := &notifier.MessageWrapper{
+		MessageHeader: notifier.MessageHeader{
+			PrName:   v.Issue.Title,
+			PrNumber: v.Issue.Number,
+			Slug:     hook.Repo.Slug,
+		},
+	}
+	setMessageDetails(mw, &v, nil)
 
-			success, err := remote.HasRequiredStatus(c, user, hook.Repo, v.Branch.BaseName, v.Branch.CompareSHA)
+	files, err := getPullRequestFiles(c, user, hook.Repo, v.Number)
+	if err != nil {
+		generateError("Unable to get pull request files", err, v, hook.Repo.Slug, &result, mw)
+		sendMessage(c, config, mw)
+		return result
+	}
 
-			if err != nil {
-				generateError("Unable to test commit statuses", err, v, hook.Repo.Slug, &result, mw)
-				merged[id] = result
-				sendMessage(c, config, mw)
-				continue
-			}
+	req := &model.ApprovalRequest{
+		Config:      config,
+		Maintainer:  maintainer,
+		PullRequest: &v,
+		Repository:  repo,
+		Files:       files,
+	}
 
-			if !success {
-				result.Info = "required status checks are not passed"
-				merged[id] = result
-				sendMessage(c, config, mw)
-				continue
-			}
+	policy := model.FindApprovalPolicy(req)
+	mergeConfig := req.Config.GetMergeConfig(policy)
 
-			if mergeConfig.UpToDate {
-				behind, err2 := isBehind(c, user, repo, v.Branch)
-				if err2 != nil {
-					generateError("Unable to compare branches", err2, v, hook.Repo.Slug, &result, mw)
-					merged[id] = result
-					sendMessage(c, config, mw)
-					continue
-				}
-				if behind {
-					result.Info = "compare branch is behind base branch"
-					merged[id] = result
-					sendMessage(c, config, mw)
-					continue
-				}
-			}
+	if !mergeConfig.Enable {
+		result.Info = "merge config not enabled"
+		sendMessage(c, config, mw)
+		return result
+	}
 
-			SHA, err := doMerge(c, user, hook, req, policy, mergeConfig.Method)
+	success, err := remote.HasRequiredStatus(c, user, hook.Repo, v.Branch.BaseName, v.Branch.CompareSHA)
 
-			if err != nil {
-				generateError("Unable to merge pull request", err, v, hook.Repo.Slug, &result, mw)
-				merged[id] = result
-				sendMessage(c, config, mw)
-				continue
-			}
+	if err != nil {
+		generateError("Unable to test commit statuses", err, v, hook.Repo.Slug, &result, mw)
+		sendMessage(c, config, mw)
+		return result
+	}
 
-			mw.Messages = append(mw.Messages, notifier.MessageInfo{
-				Message: "merged",
-				Type:    model.CommentMerge,
-			})
+	if !success {
+		result.Info = "required status checks are not passed"
+		sendMessage(c, config, mw)
+		return result
+	}
 
-			result.SHA = SHA
+	if mergeConfig.UpToDate {
+		behind, err2 := isBehind(c, user, repo, v.Branch)
+		if err2 != nil {
+			generateError("Unable to compare branches", err2, v, hook.Repo.Slug, &result, mw)
+			sendMessage(c, config, mw)
+			return result
+		}
+		if behind {
+			result.Info = "compare branch is behind base branch"
+			sendMessage(c, config, mw)
+			return result
+		}
+	}
 
-			tag, err := tagIfEnabled(c, user, hook, req, policy, SHA)
-			if err != nil {
-				generateError("Unable to tag", err, v, hook.Repo.Slug, &result, mw)
-				merged[id] = result
-				sendMessage(c, config, mw)
-				continue
-			}
+	// another instance may process the pull request once the
+	// lease of the lock is lost
+	if err = c.Err(); err != nil {
+		generateError("Lost the lock of the pull request", err, v, hook.Repo.Slug, &result, mw)
+		sendMessage(c, config, mw)
+		return result
+	}
+	SHA, err := doMerge(c, user, hook, req, policy, mergeConfig)
+	metrics.Merges.Inc(metrics.Outcome(err))
 
-			result.Tag = tag
+	if err != nil {
+		generateError("Unable to merge pull request", err, v, hook.Repo.Slug, &result, mw)
+		sendMessage(c, config, mw)
+		return result
+	}
 
-			if tag != "" {
-				mw.Messages = append(mw.Messages, notifier.MessageInfo{
-					Message: fmt.Sprintf("Tag %s has been added", tag),
-					Type:    model.CommentTag,
-				})
-			}
+	mw.Messages = append(mw.Messages, notifier.NewMessage(model.CommentMerge, model.MessageData{
+		Author: v.Author.String(),
+	}))
+	mw.MergeSHA = SHA
+	if approvals, err := calculateApprovalInfo(req, policy, true); err == nil {
+		setMessageDetails(mw, nil, approvals)
+	}
 
-			if mergeConfig.Delete && eligibleForDeletion(req, mw) {
-				err = doMergeDelete(c, user, hook, req)
-				if err != nil {
-					generateError("Unable to delete merged branch", err, v, hook.Repo.Slug, &result, mw)
-					merged[id] = result
-					sendMessage(c, config, mw)
-					continue
-				}
-				mw.Messages = append(mw.Messages, notifier.MessageInfo{
-					Message: fmt.Sprintf("Branch %s has been deleted", req.PullRequest.Branch.CompareName),
-					Type:    model.CommentDelete,
-				})
-			}
+	result.SHA = SHA
 
-			if config.Deployment.Enable {
-				doDeployment(c, user, config, hook, v.Branch.BaseName)
-				mw.Messages = append(mw.Messages, notifier.MessageInfo{
-					Message: fmt.Sprintf("Deployment has been triggered from branch %s", v.Branch.BaseName),
-					Type:    model.CommentDeployment,
-				})
-			}
-			merged[id] = result
+	tag, err := tagIfEnabled(c, user, hook, req, policy, SHA)
+	if err != nil {
+		generateError("Unable to tag", err, v, hook.Repo.Slug, &result, mw)
+		sendMessage(c, config, mw)
+		return result
+	}
+
+	result.Tag = tag
+
+	if tag != "" {
+		release, err := releaseIfEnabled(c, user, hook, req, policy, tag)
+		result.Release = release
+		mw.Messages = append(mw.Messages, tagMessage(tag, release))
+		// The pull request is already merged and tagged, so a failed
+		// release must not hold back branch deletion or deployments.
+		if err != nil {
+			generateError("Unable to create release", err, v, hook.Repo.Slug, &result, mw)
+		}
+	}
+
+	if mergeConfig.Delete && eligibleForDeletion(req, mw) {
+		err = doMergeDelete(c, user, hook, req)
+		if err != nil {
+			generateError("Unable to delete merged branch", err, v, hook.Repo.Slug, &result, mw)
 			sendMessage(c, config, mw)
+			return result
 		}
+		mw.Messages = append(mw.Messages, notifier.NewMessage(model.CommentDelete, model.MessageData{
+			PullRequest: model.MessagePullRequest{
+				Branch: req.PullRequest.Branch.CompareName,
+			},
+		}))
 	}
-	log.Debugf("processed status for %s. received %v ", repo.Slug, hook)
 
-	return merged, nil
+	if config.Deployment.Enable {
+		deployments := config.Deployment.DeploymentMap.Deployments(v.Branch.BaseName, tag)
+		for i := range deployments {
+			deployments[i].PullRequest = v.Number
+		}
+		err = doDeployment(c, params, deployments, mw)
+		if err != nil {
+			log.Warnf("Unable to schedule deployments: %s", err)
+			result.Err = err.Error()
+		}
+	}
+	sendMessage(c, config, mw)
+	return result
 }
 
 func sendMessage(c context.Context, config *model.Config, mw *notifier.MessageWrapper) {
@@ -222,76 +269,40 @@ func finalPolicyEligible(p *model.ApprovalPolicy) bool {
 
 func eligibleForDeletion(req *model.ApprovalRequest, mw *notifier.MessageWrapper) bool {
 	if req.PullRequest.Branch.CompareOwner != req.Repository.Owner {
-		mw.Messages = append(mw.Messages, notifier.MessageInfo{
-			Message: fmt.Sprintf(
-				msgBlockDeletion+"It belongs to another owner",
-				req.PullRequest.Branch.CompareName),
-			Type: model.CommentDelete,
-		})
+		mw.Messages = append(mw.Messages, blockDeletionMessage(req,
+			"It belongs to another owner"))
 		return false
 	}
 	cfg := req.Config
 	for _, p := range cfg.Approvals {
 		if p.Scope.ValidateFinal() == nil {
 			if !finalPolicyEligible(p) {
-				mw.Messages = append(mw.Messages, notifier.MessageInfo{
-					Message: fmt.Sprintf(
-						msgBlockDeletion+
-							"Approval policy %s must either have match policy "+
-							"'off' or merge deletion disabled",
-						req.PullRequest.Branch.CompareName, p.Name),
-					Type: model.CommentDelete,
-				})
+				mw.Messages = append(mw.Messages, blockDeletionMessage(req, fmt.Sprintf(
+					"Approval policy %s must either have match policy "+
+						"'off' or merge deletion disabled", p.Name)))
 				return false
 			}
 			return true
 		}
 		if p.Scope.Branches.Contains(req.PullRequest.Branch.CompareName) {
-			mw.Messages = append(mw.Messages, notifier.MessageInfo{
-				Message: fmt.Sprintf(
-					msgBlockDeletion+"It is mentioned in approval policy %s",
-					req.PullRequest.Branch.CompareName, p.Name),
-				Type: model.CommentDelete,
-			})
+			mw.Messages = append(mw.Messages, blockDeletionMessage(req, fmt.Sprintf(
+				"It is mentioned in approval policy %s", p.Name)))
 			return false
 		}
 	}
-	mw.Messages = append(mw.Messages, notifier.MessageInfo{
-		Message: fmt.Sprintf(
-			msgBlockDeletion+"Unable to find default approval policy",
-			req.PullRequest.Branch.CompareName),
-		Type: model.CommentDelete,
-	})
+	mw.Messages = append(mw.Messages, blockDeletionMessage(req,
+		"Unable to find default approval policy"))
 	return false
 }
 
-func doDeployment(c context.Context, user *model.User, config *model.Config, hook *StatusHook, baseName string) error {
-	var err error
-	if dc, ok := config.Deployment.DeploymentMap[baseName]; ok {
-		//if we are
-		env := ""
-		if dc.Environment != nil {
-			env = *dc.Environment
-		}
-		if len(dc.Tasks) == 0 && env != "" {
-			scheduleDeployment(c, user, hook.Repo, baseName, nil, env)
-		} else {
-			for _, task := range dc.Tasks {
-				scheduleDeployment(c, user, hook.Repo, baseName, &task, env)
-			}
-		}
-	}
-	return err
-}
-
-func scheduleDeployment(c context.Context, user *model.User, repo *model.Repo, baseName string, task *string, env string) {
-	di := model.DeploymentInfo{
-		Ref:         baseName,
-		Task:        *task,
-		Environment: env,
-	}
-	err := remote.ScheduleDeployment(c, user, repo, di)
-	if err != nil {
-		log.Warnf("Unable to schedule deployment %v: %s", di, err)
-	}
+// blockDeletionMessage reports why the merged branch was not deleted.
+func blockDeletionMessage(req *model.ApprovalRequest, reason string) notifier.MessageInfo {
+	branch := req.PullRequest.Branch.CompareName
+	return notifier.NewMessage(model.CommentDelete, model.MessageData{
+		PullRequest: model.MessagePullRequest{
+			Branch: branch,
+		},
+		Reason:  model.ReasonBlocked,
+		Default: fmt.Sprintf(msgBlockDeletion, branch) + reason,
+	})
 }
@@ -373,7 +373,7 @@ func TestHandleSemver(t *testing.T) {
 		PullRequest: pr,
 		Repository:  repo,
 	}
-	ver, err := handleSemver(c, user, hook, request, model.DefaultApprovalPolicy())
+	ver, err := handleSemver(c, user, hook, request, model.DefaultApprovalPolicy(), model.TemplateTag{})
 	if err != nil {
 		t.Fatalf("unexpected error %s", err)
 	}
@@ -462,7 +462,7 @@ func TestHandleSemver2(t *testing.T) {
 		ApprovalComments:    testComments,
 		DisapprovalComments: testComments,
 	}
-	ver, err := handleSemver(c, user, hook, request, model.DefaultApprovalPolicy())
+	ver, err := handleSemver(c, user, hook, request, model.DefaultApprovalPolicy(), model.TemplateTag{})
 	if err != nil {
 		t.Fatalf("unexpected error %s", err)
 	}
@@ -549,7 +549,7 @@ func TestHandleSemver3(t *testing.T) {
 		ApprovalComments:    testComments,
 		DisapprovalComments: testComments,
 	}
-	ver, err := handleSemver(c, user, hook, request, model.DefaultApprovalPolicy())
+	ver, err := handleSemver(c, user, hook, request, model.DefaultApprovalPolicy(), model.TemplateTag{})
 	if err != nil {
 		t.Fatalf("unexpected error %s", err)
 	}
@@ -934,3 +934,216 @@ func TestGetLastVersionCommentNoSelfApproval(t *testing.T) {
 		t.Errorf("Expected 0.0.1, got %s", ver)
 	}
 }
+
+func TestGetIncrement(t *testing.T) {
+	config := model.NonEmptyConfig()
+	config.Pattern = rxserde.RegexSerde{Regex: regexp.MustCompile(`(?i)^I approve\s*(bump:\s*(?P<bump>\S+))?`)}
+	config.Tag.Increment = model.Patch
+	pr := &model.PullRequest{
+		Issue: model.Issue{
+			Author: lowercase.Create("test_guy"),
+		},
+		Labels: []string{"bug", "semver:minor"},
+	}
+	request := &model.ApprovalRequest{
+		Config:      config,
+		Maintainer:  &model.MaintainerSnapshot{People: map[string]*model.Person{"test_guy2": {}}},
+		PullRequest: pr,
+		Commits: []model.Commit{
+			{Message: "fix: typo"},
+			{Message: "Merge branch 'master'"},
+		},
+		ApprovalComments: []model.Feedback{
+			&model.Comment{
+				Author: lowercase.Create("test_guy2"),
+				Body:   "I approve bump:major",
+			},
+		},
+	}
+	policy := model.DefaultApprovalPolicy()
+	tagConfig := &config.Tag
+	assert.Equal(t, model.Patch, getIncrement(request, policy, tagConfig))
+	tagConfig.Bump = []string{"commit"}
+	assert.Equal(t, model.Patch, getIncrement(request, policy, tagConfig))
+	tagConfig.Bump = []string{"commit", "label"}
+	assert.Equal(t, model.Minor, getIncrement(request, policy, tagConfig))
+	tagConfig.Bump = []string{"commit", "label", "comment"}
+	assert.Equal(t, model.Major, getIncrement(request, policy, tagConfig))
+	request.Commits = append(request.Commits, model.Commit{Message: "feat!: drop v1"})
+	tagConfig.Bump = []string{"commit"}
+	assert.Equal(t, model.Major, getIncrement(request, policy, tagConfig))
+	tagConfig.Increment = model.Minor
+	pr.Labels = nil
+	tagConfig.Bump = []string{"label"}
+	assert.Equal(t, model.Minor, getIncrement(request, policy, tagConfig))
+	pr.Labels = []string{"semver:none"}
+	assert.Equal(t, model.None, getIncrement(request, policy, tagConfig))
+}
+
+type myRPrefix struct {
+	remote.Remote
+}
+
+func (m *myRPrefix) ListTags(c context.Context, u *model.User, r *model.Repo) ([]model.Tag, error) {
+	return []model.Tag{
+		"web/v3.0.0",
+		"api/v1.1.0",
+		"api/v1.2.0",
+		"api/v1.3.0-rc.1",
+		"api/v1.3.0-rc.2",
+		"api/v1.3.0-beta.1",
+	}, nil
+}
+
+func TestHandleSemverPrefix(t *testing.T) {
+	c := &gin.Context{}
+	remote.ToContext(c, &myRPrefix{})
+	config := model.NonEmptyConfig()
+	config.Tag.Enable = true
+	config.Tag.Prefix = "api/"
+	config.Tag.TemplateRaw = "api/v{{.Version}}"
+	hook := &StatusHook{
+		Repo: &model.Repo{
+			Owner: "test_guy",
+			Name:  "test_repo",
+		},
+	}
+	request := &model.ApprovalRequest{
+		Config:      config,
+		Maintainer:  &model.MaintainerSnapshot{},
+		PullRequest: &model.PullRequest{Issue: model.Issue{Author: lowercase.Create("test_guy")}},
+		Repository:  &model.Repo{},
+	}
+	policy := model.DefaultApprovalPolicy()
+	// the highest prerelease is promoted to a release
+	ver, err := handleSemver(c, &model.User{}, hook, request, policy, model.TemplateTag{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.3.0", ver)
+	// the prerelease counter is incremented
+	config.Tag.PrereleaseRaw = "rc"
+	assert.Nil(t, config.Tag.Compile())
+	ver, err = handleSemver(c, &model.User{}, hook, request, policy, model.TemplateTag{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.3.0-rc.3", ver)
+	// a new identifier starts a new counter
+	config.Tag.PrereleaseRaw = "{{.Branch}}"
+	assert.Nil(t, config.Tag.Compile())
+	ver, err = handleSemver(c, &model.User{}, hook, request, policy, model.TemplateTag{Branch: "release/1.3"})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.3.0-release-1.3.1", ver)
+	// a larger increment takes precedence over the prerelease
+	config.Tag.Increment = model.Major
+	ver, err = handleSemver(c, &model.User{}, hook, request, policy, model.TemplateTag{Branch: "release"})
+	assert.Nil(t, err)
+	assert.Equal(t, "2.0.0-release.1", ver)
+}
+
+func TestFilterTags(t *testing.T) {
+	tags := []model.Tag{"web/v3.0.0", "api/v1.1.0", "1.0.0"}
+	assert.Equal(t, tags, filterTags(tags, ""))
+	assert.Equal(t, []model.Tag{"v1.1.0"}, filterTags(tags, "api/"))
+	assert.Nil(t, filterTags(tags, "cli/"))
+}
+
+func TestNewTemplateTag(t *testing.T) {
+	request := &model.ApprovalRequest{
+		PullRequest: &model.PullRequest{
+			Issue:  model.Issue{Number: 12},
+			Branch: model.Branch{BaseName: "release/1.2"},
+		},
+	}
+	now := time.Date(2017, time.October, 9, 23, 0, 0, 0, time.UTC)
+	body := newTemplateTag(request, "0123456789abcdef", now)
+	assert.Equal(t, model.TemplateTag{
+		Branch:      "release/1.2",
+		PullRequest: 12,
+		ShortSHA:    "0123456",
+		Date:        "20171009",
+	}, body)
+}
+
+type tagR struct {
+	remote.Remote
+	tag       string
+	annotated model.AnnotatedTag
+}
+
+func (m *tagR) Tag(c context.Context, u *model.User, r *model.Repo, tag string, sha string) error {
+	m.tag = tag
+	return nil
+}
+
+func (m *tagR) CreateAnnotatedTag(c context.Context, u *model.User, r *model.Repo, tag model.AnnotatedTag) error {
+	m.annotated = tag
+	return nil
+}
+
+func createTagRequest() *model.ApprovalRequest {
+	config := model.NonEmptyConfig()
+	universe := model.UniverseMatch{}
+	universe.Approvals = 1
+	config.Approvals[0].Match = model.MatcherHolder{Matcher: &universe}
+	config.Tag.Enable = true
+	config.Tag.Alg = "timestamp-millis"
+	return &model.ApprovalRequest{
+		Config: config,
+		Maintainer: &model.MaintainerSnapshot{
+			People: map[string]*model.Person{
+				"test_guy2": &model.Person{
+					Name:  "Test Guy",
+					Email: "test_guy2@mail.com",
+					Login: "test_guy2",
+				},
+			},
+		},
+		PullRequest: &model.PullRequest{
+			Issue: model.Issue{
+				Number: 7,
+				Title:  "Add a feature",
+				Author: lowercase.Create("test_guy"),
+			},
+		},
+		ApprovalComments: []model.Feedback{
+			&model.Comment{
+				Author: lowercase.Create("test_guy2"),
+				Body:   "I approve",
+			},
+		},
+		Repository: &model.Repo{Owner: "test_guy", Name: "test_repo"},
+	}
+}
+
+func TestGetTagMessage(t *testing.T) {
+	request := createTagRequest()
+	policy := request.Config.Approvals[0]
+	message, err := getTagMessage(request, policy, "1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0\n\nPull request #7: Add a feature\nApproval policy: # 1\n"+
+		"Approved by:\n- Test Guy <test_guy2@mail.com> (@test_guy2)\nTagged by checks-out\n", message)
+}
+
+func TestDoTagAnnotated(t *testing.T) {
+	c := &gin.Context{}
+	mock := &tagR{}
+	remote.ToContext(c, mock)
+	request := createTagRequest()
+	policy := request.Config.Approvals[0]
+	hook := &StatusHook{Repo: request.Repository}
+
+	request.Config.Tag.Annotated = false
+	tag, err := doTag(c, nil, hook, request, policy, "abcdef0123456789")
+	assert.Nil(t, err)
+	assert.Equal(t, tag, mock.tag)
+	assert.Equal(t, "", mock.annotated.Name)
+
+	mock.tag = ""
+	request.Config.Tag.Annotated = true
+	request.Config.Tag.Sign = true
+	tag, err = doTag(c, nil, hook, request, policy, "abcdef0123456789")
+	assert.Nil(t, err)
+	assert.Equal(t, "", mock.tag)
+	assert.Equal(t, tag, mock.annotated.Name)
+	assert.Equal(t, "abcdef0123456789", mock.annotated.SHA)
+	assert.True(t, mock.annotated.Sign)
+	assert.Contains(t, mock.annotated.Message, "Pull request #7: Add a feature\n")
+}
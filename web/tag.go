@@ -19,14 +19,18 @@ See the License for the specific language governing permissions and limitations
 package web
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/hashicorp/go-version"
+	"github.com/capitalone/checks-out/envvars"
+	"github.com/capitalone/checks-out/metrics"
 	"github.com/capitalone/checks-out/model"
 	"github.com/capitalone/checks-out/remote"
 )
@@ -43,7 +47,18 @@ func tagIfEnabled(c context.Context, user *model.User, hook *StatusHook,
 	}
 
 	if tagConfig.Enable {
-		return doTag(c, user, hook, req, policy, SHA)
+		// pull requests merged at the same time must not
+		// compute the same version
+		lc, release, err := lockKey(c, tagLockKey(req.Repository))
+		if err != nil {
+			return "", err
+		}
+		defer release()
+		tag, err := doTag(lc, user, hook, req, policy, SHA)
+		if err != nil || tag != "" {
+			metrics.Tags.Inc(metrics.Outcome(err))
+		}
+		return tag, err
 	}
 	return "", nil
 }
@@ -59,11 +74,12 @@ func doTag(c context.Context, user *model.User, hook *StatusHook,
 		tagConfig = policy.Tag
 	}
 
+	body := newTemplateTag(req, SHA, time.Now().UTC())
 	var err error
 	if strings.HasPrefix(tagConfig.Alg, "timestamp") {
 		vers, err = handleTimestamp(tagConfig)
 	} else if tagConfig.Alg == "semver" {
-		vers, err = handleSemver(c, user, hook, req, policy)
+		vers, err = handleSemver(c, user, hook, req, policy, body)
 	} else if tagConfig.Alg == "explicit" {
 		vers = handleExplicit(req, policy)
 		//if no version was found, just return
@@ -73,25 +89,76 @@ func doTag(c context.Context, user *model.User, hook *StatusHook,
 	} else {
 		log.Warnf("Repo %s should have had a valid tag algorithm configured -- using semver",
 			req.Repository.Name)
-		vers, err = handleSemver(c, user, hook, req, policy)
+		vers, err = handleSemver(c, user, hook, req, policy, body)
 	}
 	if err != nil {
 		return "", err
 	}
-	tag, err := tagConfig.GenerateTag(model.TemplateTag{Version: vers})
+	body.Version = vers
+	tag, err := tagConfig.GenerateTag(body)
 	if err != nil {
 		return "", err
 	}
 	log.Debugf("Tagging merge from PR with tag: %s", tag)
-	err = remote.Tag(c, user, req.Repository, tag, SHA)
+	if tagConfig.Annotated {
+		message, err := getTagMessage(req, policy, tag)
+		if err != nil {
+			return "", err
+		}
+		err = remote.CreateAnnotatedTag(c, user, req.Repository, model.AnnotatedTag{
+			Name:    tag,
+			SHA:     SHA,
+			Message: message,
+			Sign:    tagConfig.Sign,
+		})
+	} else {
+		err = remote.Tag(c, user, req.Repository, tag, SHA)
+	}
 	if err != nil {
 		return "", err
 	}
 	return tag, nil
 }
 
+// getTagMessage records the pull request and the approvers
+// of the merge in the message of an annotated tag.
+func getTagMessage(req *model.ApprovalRequest, policy *model.ApprovalPolicy, tag string) (string, error) {
+	approvals, err := calculateApprovalInfo(req, policy, true)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "%s\n\n", tag)
+	fmt.Fprintf(&buffer, "Pull request #%d: %s\n", req.PullRequest.Number, req.PullRequest.Title)
+	if desc := policyDescription(approvals); len(desc) > 0 {
+		fmt.Fprintf(&buffer, "Approval policy: %s\n", desc)
+	}
+	approvers := getPeople(req, approvals.Approvers)
+	if len(approvers) > 0 {
+		buffer.WriteString("Approved by:\n")
+		for _, p := range approvers {
+			fmt.Fprintf(&buffer, "- %s\n", model.FormatPerson(p))
+		}
+	}
+	fmt.Fprintf(&buffer, "Tagged by %s\n", envvars.Env.Branding.ShortName)
+	return buffer.String(), nil
+}
+
 const modifiedRFC3339 = "2006-01-02T15.04.05Z"
 
+func newTemplateTag(req *model.ApprovalRequest, SHA string, now time.Time) model.TemplateTag {
+	short := SHA
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	return model.TemplateTag{
+		Branch:      req.PullRequest.Branch.BaseName,
+		PullRequest: req.PullRequest.Number,
+		ShortSHA:    short,
+		Date:        now.Format("20060102"),
+	}
+}
+
 func handleTimestamp(tag *model.TagConfig) (string, error) {
 	/*
 		All times are in UTC
@@ -116,8 +183,8 @@ func handleTimestamp(tag *model.TagConfig) (string, error) {
 	return out, nil
 }
 
-func increment(segments []int, tag *model.TagConfig) {
-	switch tag.Increment {
+func increment(segments []int, inc model.Semver) {
+	switch inc {
 	case model.Major:
 		segments[0]++
 		segments[1] = 0
@@ -130,7 +197,7 @@ func increment(segments []int, tag *model.TagConfig) {
 	case model.None:
 		// do nothing
 	default:
-		log.Errorf("Unknown semver increment %d", tag.Increment)
+		log.Errorf("Unknown semver increment %d", inc)
 	}
 }
 
@@ -139,7 +206,7 @@ func handleExplicit(req *model.ApprovalRequest, policy *model.ApprovalPolicy) st
 	return foundVersion
 }
 
-func handleSemver(c context.Context, user *model.User, hook *StatusHook, req *model.ApprovalRequest, policy *model.ApprovalPolicy) (string, error) {
+func handleSemver(c context.Context, user *model.User, hook *StatusHook, req *model.ApprovalRequest, policy *model.ApprovalPolicy, body model.TemplateTag) (string, error) {
 
 	var tagConfig *model.TagConfig
 
@@ -155,6 +222,7 @@ func handleSemver(c context.Context, user *model.User, hook *StatusHook, req *mo
 	if err != nil {
 		log.Warnf("Unable to list tags for %s/%s: %s", hook.Repo.Owner, hook.Repo.Name, err)
 	}
+	tags = filterTags(tags, tagConfig.Prefix)
 	maxVer := getMaxExistingTag(tags)
 
 	foundVersion := getMaxVersionComment(req, policy)
@@ -162,15 +230,178 @@ func handleSemver(c context.Context, user *model.User, hook *StatusHook, req *mo
 	if foundVersion != nil && foundVersion.GreaterThan(maxVer) {
 		maxVer = foundVersion
 	} else {
-		maxParts := maxVer.Segments()
-		increment(maxParts, tagConfig)
-		maxVer, _ = version.NewVersion(fmt.Sprintf("%d.%d.%d", maxParts[0], maxParts[1], maxParts[2]))
+		maxVer = nextVersion(maxVer, getMaxReleaseTag(tags), getIncrement(req, policy, tagConfig))
+	}
+
+	if maxVer.Prerelease() == "" {
+		ident, err := tagConfig.GeneratePrerelease(body)
+		if err != nil {
+			return "", err
+		}
+		if ident != "" {
+			maxVer = addPrerelease(maxVer, tags, ident)
+		}
 	}
 
 	verStr := maxVer.String()
 	return verStr, nil
 }
 
+// nextVersion increments the highest existing version. If the highest
+// existing version is a prerelease then it is promoted to a release,
+// unless incrementing the highest release produces a larger version.
+func nextVersion(maxVer *version.Version, maxRelease *version.Version, inc model.Semver) *version.Version {
+	if maxVer.Prerelease() == "" {
+		maxParts := maxVer.Segments()
+		increment(maxParts, inc)
+		next, _ := version.NewVersion(fmt.Sprintf("%d.%d.%d", maxParts[0], maxParts[1], maxParts[2]))
+		return next
+	}
+	relParts := maxRelease.Segments()
+	increment(relParts, inc)
+	next, _ := version.NewVersion(fmt.Sprintf("%d.%d.%d", relParts[0], relParts[1], relParts[2]))
+	maxParts := maxVer.Segments()
+	core, _ := version.NewVersion(fmt.Sprintf("%d.%d.%d", maxParts[0], maxParts[1], maxParts[2]))
+	if next.GreaterThan(core) {
+		return next
+	}
+	return core
+}
+
+// addPrerelease appends the prerelease identifier and a counter
+// to the version. The counter is one greater than the highest
+// counter of the existing tags with the same version and identifier.
+func addPrerelease(ver *version.Version, tags []model.Tag, ident string) *version.Version {
+	parts := ver.Segments()
+	core := fmt.Sprintf("%d.%d.%d", parts[0], parts[1], parts[2])
+	counter := 0
+	for _, v := range tags {
+		curVer, err := version.NewVersion(string(v))
+		if err != nil {
+			continue
+		}
+		curParts := curVer.Segments()
+		if fmt.Sprintf("%d.%d.%d", curParts[0], curParts[1], curParts[2]) != core {
+			continue
+		}
+		pre := curVer.Prerelease()
+		if !strings.HasPrefix(pre, ident+".") {
+			continue
+		}
+		n, err := strconv.Atoi(pre[len(ident)+1:])
+		if err == nil && n > counter {
+			counter = n
+		}
+	}
+	result, _ := version.NewVersion(fmt.Sprintf("%s-%s.%d", core, ident, counter+1))
+	return result
+}
+
+// filterTags returns the tags that begin with the prefix.
+// The prefix is removed from the returned tags.
+func filterTags(tags []model.Tag, prefix string) []model.Tag {
+	if len(prefix) == 0 {
+		return tags
+	}
+	var result []model.Tag
+	for _, v := range tags {
+		if strings.HasPrefix(string(v), prefix) {
+			result = append(result, model.Tag(strings.TrimPrefix(string(v), prefix)))
+		}
+	}
+	return result
+}
+
+// labelPrefix is the prefix of pull request labels
+// that request a semver increment, ie. "semver:major"
+const labelPrefix = "semver:"
+
+// getIncrement returns the largest semver increment requested
+// by the sources in the tag configuration. If no increment is
+// requested then the increment of the tag configuration is returned.
+func getIncrement(req *model.ApprovalRequest, policy *model.ApprovalPolicy, tagConfig *model.TagConfig) model.Semver {
+	result := model.None
+	found := false
+	request := func(inc model.Semver) {
+		result = result.Max(inc)
+		found = true
+	}
+	for _, source := range tagConfig.Bump {
+		switch source {
+		case "label":
+			getLabelIncrements(req, request)
+		case "commit":
+			getCommitIncrements(req, request)
+		case "comment":
+			getCommentIncrements(req, policy, request)
+		}
+	}
+	if !found {
+		return tagConfig.Increment
+	}
+	log.Debugf("Pull request %d requested semver increment %s", req.PullRequest.Number, result)
+	return result
+}
+
+func getLabelIncrements(req *model.ApprovalRequest, request func(model.Semver)) {
+	for _, label := range req.PullRequest.Labels {
+		lower := strings.ToLower(label)
+		if !strings.HasPrefix(lower, labelPrefix) {
+			continue
+		}
+		if inc, ok := model.ParseSemver(lower[len(labelPrefix):]); ok {
+			request(inc)
+		}
+	}
+}
+
+func getCommitIncrements(req *model.ApprovalRequest, request func(model.Semver)) {
+	for _, commit := range req.Commits {
+		cc, ok := model.ParseConventional(commit.Message)
+		if !ok {
+			continue
+		}
+		if inc, ok := cc.Increment(); ok {
+			request(inc)
+		}
+	}
+}
+
+// getCommentIncrements analyzes the approval comments for
+// the "bump" capture group of the approval pattern.
+func getCommentIncrements(req *model.ApprovalRequest, policy *model.ApprovalPolicy, request func(model.Semver)) {
+	var matcher *regexp.Regexp
+	if policy.Pattern != nil {
+		matcher = policy.Pattern.Regex
+	} else {
+		matcher = req.Config.Pattern.Regex
+	}
+	if matcher == nil {
+		return
+	}
+	index := getGroupIndex(matcher, "bump")
+	if index == 0 {
+		return
+	}
+	model.Approve(req, policy,
+		func(f model.Feedback, op model.ApprovalOp) {
+			if op != model.Approval {
+				return
+			}
+			body := f.GetBody()
+			if len(body) == 0 {
+				return
+			}
+			// verify the comment matches the approval pattern
+			match := matcher.FindStringSubmatch(body)
+			if len(match) > index {
+				if inc, ok := model.ParseSemver(match[index]); ok {
+					request(inc)
+				}
+			}
+		})
+}
+
 // getMaxExistingTag is a helper function that scans all passed-in tags for a
 // comments with semantic versions. It returns the max version found. If no version
 // is found, the function returns a version with the value 0.0.0
@@ -192,6 +423,19 @@ func getMaxExistingTag(tags []model.Tag) *version.Version {
 	return maxVer
 }
 
+// getMaxReleaseTag is similar to getMaxExistingTag
+// but it ignores tags that are prerelease versions.
+func getMaxReleaseTag(tags []model.Tag) *version.Version {
+	var releases []model.Tag
+	for _, v := range tags {
+		curVer, err := version.NewVersion(string(v))
+		if err == nil && curVer.Prerelease() == "" {
+			releases = append(releases, v)
+		}
+	}
+	return getMaxExistingTag(releases)
+}
+
 func getGroupIndex(re *regexp.Regexp, name string) int {
 	for i, n := range re.SubexpNames() {
 		if n == name {
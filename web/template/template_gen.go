@@ -4,6 +4,7 @@
 // files/error.html
 // files/index.html
 // files/logout.html
+// files/slack_link.html
 // DO NOT EDIT!
 
 package template
@@ -151,6 +152,26 @@ func filesLogoutHtml() (*asset, error) {
 	return a, nil
 }
 
+var _filesSlack_linkHtml = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x8d\x55\xdf\x4f\xdb\x30\x10\x7e\xdf\x5f\xe1\xe5\xa5\x45\x90\xa4\xd3\xf6\x30\xb1\x04\xa4\x95\x6e\xa0\x95\x81\x4a\x27\xb1\x47\xd7\xb9\x34\x16\x8e\x9d\xd9\x97\x96\x0a\xf1\xbf\xef\x9c\x14\x5a\x9a\xc2\x96\x87\xfa\xd7\xe7\xef\xee\xbe\x3b\x5f\x93\xf7\x67\x57\xc3\xe9\xef\xeb\x11\x2b\xb0\x54\x27\xef\x92\x76\x60\xf4\x25\x05\xf0\xac\x9d\x36\x4b\x94\xa8\xe0\x24\x89\xdb\x71\xb3\x5f\x02\x72\x26\x0a\x6e\x1d\x60\x1a\xd4\x98\x87\x9f\x03\x16\x77\x00\x46\x23\x68\x02\x2c\x65\x86\x45\x9a\xc1\x42\x0a\x08\x9b\xc5\x11\x93\x5a\xa2\xe4\x2a\x74\x82\x2b\x48\x3f\x04\x4c\xf3\x12\xd2\x60\x21\x61\x59\x19\x8b\x6f\xd1\x49\x48\x21\x9b\x43\x40\xfe\x63\x15\xc2\x9f\x5a\x2e\xd2\xe0\x3e\xac\x79\x28\x4c\x59\x71\x94\x33\x05\x2f\xef\x2b\xa9\xef\x58\x61\x21\x4f\x83\xd8\x21\x21\x44\x9c\x73\xf2\xc6\xe8\x88\x7e\x02\x66\x41\x11\x2d\x2d\x03\x86\xab\x8a\xdc\x90\x25\x9f\x43\x7c\x1f\xb6\x7b\xfb\xa9\x7a\xde\xbc\x3b\x8e\xe3\x9c\x1c\x73\xd1\xdc\x98\xb9\x02\x5e\x49\x17\x91\x1b\xb1\x70\xee\x34\xe7\xa5\x54\xab\x74\x62\x66\x06\xcd\xf1\xa7\xc1\xe0\xe8\xe3\x60\xd0\x6b\xac\xf5\x1c\xae\x14\xb8\x02\x00\x7b\xad\xcd\x1e\xc2\x3d\xfa\x6b\xbd\xfd\x8e\xbf\x69\xcd\xfb\xf9\x64\xee\x92\x23\x58\x92\xf6\xf0\x82\x36\xdd\x3a\xb8\x8d\xb9\x7f\x2a\xd3\x42\x23\xf2\xe4\xf5\xbb\x49\xbc\xa9\x94\x64\x66\xb2\xd5\x16\x65\x26\x17\x4c\x28\xee\x5c\x1a\xf8\x94\x71\xa9\xc1\x32\x65\xe6\xa6\xc6\x60\x03\xdb\x85\x96\xe0\x1c\x69\xce\xd6\x63\x98\xd7\x4a\xb5\xb5\x12\x30\x99\x3d\x9f\xef\x30\xf8\x6f\xec\x43\xb8\x51\x5c\xdc\xb1\xda\x91\xa9\x87\x07\x16\xb9\x66\xf9\xf8\xc8\x4c\xce\x10\x78\xd9\x6c\x36\x13\xda\x43\xc3\xbe\x4b\x3c\xaf\x67\x1b\xbc\x9f\x44\x63\x33\x97\x9a\x00\xa7\x1d\x13\xd3\x02\xb6\x2d\x2c\xa5\x52\x6c\x06\x8c\x53\xa1\x79\x36\x51\x00\x1d\x21\x81\x78\x55\x59\xb3\xe0\x8a\x79\x2d\x6b\xd7\x98\x2f\xa0\xc3\x57\x51\x70\xa4\xed\x9f\x1a\x1c\x3a\x7a\x0a\xcd\x5d\x0b\x95\x71\x12\x8d\x95\xe0\x68\x83\x63\xd7\x33\x26\xb8\x26\x1c\xcf\xa2\x0e\x65\x92\x1b\x5b\x36\x4a\xf9\x94\xee\x91\xa9\x4d\x55\x8d\x68\xf4\xba\xc8\x5d\x3d\x2b\x25\xe5\xa4\x11\x90\x0b\x61\x6a\xaa\xac\x24\x6e\x31\x5d\x82\x24\xf6\x26\x76\x32\x18\x53\x0a\xb7\x72\xbf\xb3\x74\xc2\xca\x0a\x5f\x5e\xc9\x8c\xa8\x4b\x7a\xc7\xd1\x1c\x70\xa4\xc0\x4f\xbf\xae\x2e\xb2\x7e\xeb\xf7\x41\xc4\xb3\x6c\xb4\xa0\xcd\xb1\x74\xf4\xdc\xc1\xf6\x9f\xfc\x3c\x62\x79\xad\x05\x4a\xa3\xfb\xe0\x01\x07\xec\xa1\xe3\x63\x73\x10\x55\xb6\x19\xcf\x20\xe7\xb5\xc2\xfe\xc1\x97\x0e\x6e\xc1\xad\xd7\x9f\xa5\x4c\xc3\x92\xdd\x5e\x8e\xcf\xe9\x75\x4d\xda\x84\xec\xc3\x13\x36\x32\x15\xe8\x7e\x70\x7d\x75\x33\x25\x57\xe8\xb5\xf8\x7a\x88\x5b\xa7\xf7\x5f\xa0\xe6\xb8\xa6\x3c\xa7\x94\xf9\x48\x86\x6d\x0b\x0b\xa7\xa4\xbf\x27\xa1\x72\x51\x52\x70\x1f\x13\x35\x9b\xe5\x72\x19\x7a\x89\xc3\xda\x2a\xd0\xc2\x64\x90\xfd\x3f\xf5\x6d\x38\xbc\x99\x7c\x0b\xa7\x57\x3f\x46\x3f\x89\xda\x97\x8e\x70\x36\xa7\xa2\x79\x2d\x1c\xad\x0c\xcf\x48\x81\x67\x55\xf7\x09\xfa\x66\xc6\x9e\xde\xe4\x41\xe4\x7b\xd7\x3a\x38\x62\xf4\xf4\x16\x5c\x45\xed\x07\xa6\x74\xd2\x75\xe0\xf1\xb5\xb0\x34\xd1\xa2\xb9\x03\x9d\x06\xec\x90\xb5\x2a\xfc\x9a\x5c\x0c\xa9\xb1\x1b\x4d\xec\xfd\xe6\x21\x7b\x80\x8f\x6c\x27\xb4\xed\x50\x93\x78\xbb\xf8\xa8\xaa\x9b\x26\x45\x7d\xab\xf9\xb3\xfb\x0b\x7f\x90\x2b\xb6\x04\x07\x00\x00")
+
+func filesSlack_linkHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_filesSlack_linkHtml,
+		"files/slack_link.html",
+	)
+}
+
+func filesSlack_linkHtml() (*asset, error) {
+	bytes, err := filesSlack_linkHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "files/slack_link.html", size: 1796, mode: os.FileMode(420), modTime: time.Unix(1792352766, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -207,6 +228,7 @@ var _bindata = map[string]func() (*asset, error){
 	"files/error.html": filesErrorHtml,
 	"files/index.html": filesIndexHtml,
 	"files/logout.html": filesLogoutHtml,
+	"files/slack_link.html": filesSlack_linkHtml,
 }
 
 // AssetDir returns the file names below a certain
@@ -254,6 +276,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"error.html": &bintree{filesErrorHtml, map[string]*bintree{}},
 		"index.html": &bintree{filesIndexHtml, map[string]*bintree{}},
 		"logout.html": &bintree{filesLogoutHtml, map[string]*bintree{}},
+		"slack_link.html": &bintree{filesSlack_linkHtml, map[string]*bintree{}},
 	}},
 }}
 